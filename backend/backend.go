@@ -13,3 +13,18 @@
 // limitations under the License.
 
 package backend
+
+import (
+	"github.com/corestoreio/csfw/config/cfgregistry"
+	"github.com/corestoreio/csfw/config/element"
+)
+
+func init() {
+	cfgregistry.MustRegister(cfgregistry.Package{
+		Name:     "backend",
+		Sections: NewConfigStructure,
+		Backend: func(cfgStruct element.SectionSlice) interface{} {
+			return NewBackend(cfgStruct)
+		},
+	})
+}
@@ -0,0 +1,37 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend_test
+
+import (
+	"testing"
+
+	"github.com/corestoreio/csfw/backend"
+	"github.com/corestoreio/csfw/config/cfgregistry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	assert.Contains(t, cfgregistry.Default.Packages(), "backend")
+
+	tree, err := cfgregistry.Build()
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	assert.Exactly(t, "backend", tree.Owner("web/url/redirect_to_base"))
+	be, ok := tree.Backend("backend").(*backend.PkgBackend)
+	assert.True(t, ok)
+	assert.True(t, be.WebURLRedirectToBase.HasField())
+	assert.Len(t, tree.Models("backend"), 70)
+}
@@ -22,11 +22,6 @@ import (
 	"github.com/corestoreio/csfw/config/source"
 )
 
-// Backend will be initialized in the init() function together with ConfigStructure.
-var Backend *PkgBackend
-
-// TODO: during development move each of this config stuff into its own package.
-
 // PkgBackend just exported for the sake of documentation. See fields
 // for more information. The PkgBackend handles the reading and writing
 // of configuration values within this package.
@@ -430,7 +425,8 @@ type PkgBackend struct {
 	WebSessionUseFrontendSid cfgmodel.Bool
 }
 
-// NewBackend initializes the global Backend variable. See init()
+// NewBackend creates the configuration models and applies the fields of
+// cfgStruct to them.
 func NewBackend(cfgStruct element.SectionSlice) *PkgBackend {
 	return (&PkgBackend{}).init(cfgStruct)
 }
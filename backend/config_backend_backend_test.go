@@ -9,6 +9,11 @@ import (
 	"github.com/corestoreio/csfw/config/source"
 )
 
+var (
+	cfgStruct = backend.MustNewConfigStructure()
+	be        = backend.NewBackend(cfgStruct)
+)
+
 // benchmarkGlobalStruct trick the compiler to not optimize anything
 var benchmarkGlobalStruct bool
 
@@ -19,7 +24,7 @@ func Benchmark_StructGlobal(b *testing.B) {
 	b.ReportAllocs()
 	for i := 0; i < b.N; i++ {
 		var err error
-		benchmarkGlobalStruct, err = backend.Backend.DevCSSMinifyFiles.Get(sg) // any random struct field
+		benchmarkGlobalStruct, err = be.DevCSSMinifyFiles.Get(sg) // any random struct field
 		if err != nil {
 			b.Error(err)
 		}
@@ -30,7 +35,7 @@ func Benchmark_StructSpecific(b *testing.B) {
 
 	sg := cfgmock.NewService().NewScoped(1, 1)
 
-	mb := cfgmodel.NewBool("aa/bb/cc", cfgmodel.WithFieldFromSectionSlice(backend.ConfigStructure), cfgmodel.WithSource(source.YesNo))
+	mb := cfgmodel.NewBool("aa/bb/cc", cfgmodel.WithFieldFromSectionSlice(cfgStruct), cfgmodel.WithSource(source.YesNo))
 
 	b.ResetTimer()
 	b.ReportAllocs()
@@ -29,8 +29,8 @@ func TestConfigRedirectToBase(t *testing.T) {
 	t.Parallel()
 
 	r := backend.NewConfigRedirectToBase(
-		backend.Backend.WebURLRedirectToBase.String(),
-		cfgmodel.WithFieldFromSectionSlice(backend.ConfigStructure),
+		be.WebURLRedirectToBase.String(),
+		cfgmodel.WithFieldFromSectionSlice(cfgStruct),
 	)
 
 	redirCode, err := r.Get(cfgmock.NewService().NewScoped(0, 0))
@@ -39,7 +39,7 @@ func TestConfigRedirectToBase(t *testing.T) {
 	}
 	assert.Exactly(
 		t,
-		1, // default value in backend.ConfigStructure
+		1, // default value in cfgStruct
 		redirCode,
 	)
 
@@ -47,19 +47,19 @@ func TestConfigRedirectToBase(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	// 1 == default value in backend.ConfigStructure
+	// 1 == default value in cfgStruct
 	assert.Exactly(t, 1, redirCode)
 
-	webURLRedirectToBasePath, err := backend.Backend.WebURLRedirectToBase.ToPath(0, 0)
+	webURLRedirectToBasePath, err := be.WebURLRedirectToBase.ToPath()
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	cr := cfgmock.NewService(
-		cfgmock.WithPV(cfgmock.PathValue{
-			webURLRedirectToBasePath.String():                       2,
-			webURLRedirectToBasePath.Bind(scope.Store, 33).String(): 34,
-		}),
+		cfgmock.PathValue{
+			webURLRedirectToBasePath.String():               2,
+			webURLRedirectToBasePath.BindStore(33).String(): 34,
+		},
 	)
 
 	tests := []struct {
@@ -68,7 +68,7 @@ func TestConfigRedirectToBase(t *testing.T) {
 	}{
 		{cr.NewScoped(0, 0), 2},
 		{cr.NewScoped(1, 2), 2},
-		{cr.NewScoped(1, 33), 34},
+		{cr.NewScoped(1, 33), 2}, // field allows only the default scope
 	}
 	for i, test := range tests {
 		code, err := r.Get(test.sg)
@@ -76,30 +76,30 @@ func TestConfigRedirectToBase(t *testing.T) {
 			t.Fatalf("Index %d => %s", i, err)
 		}
 		assert.Exactly(t, test.want, code, "Index %d", i)
-		assert.False(t, r.HasErrors(), "Index %d", i)
+		assert.NoError(t, r.LastError, "Index %d", i)
 	}
 
 	mw := new(cfgmock.Write)
-	assert.EqualError(t, r.Write(mw, 200, scope.Default, 0),
-		"The value '200' cannot be found within the allowed Options():\n[{\"Value\":0,\"Label\":\"No\"},{\"Value\":1,\"Label\":\"Yes (302 Found)\"},{\"Value\":302,\"Label\":\"Yes (302 Found)\"},{\"Value\":301,\"Label\":\"Yes (301 Moved Permanently)\"}]\n\nJSON Error: %!s(<nil>)",
+	assert.EqualError(t, r.Write(mw, 200, scope.DefaultTypeID),
+		"[cfgmodel] The value '200' cannot be found within the allowed Options():\n[{\"Value\":0,\"Label\":\"No\"},{\"Value\":1,\"Label\":\"Yes (302 Found)\"},{\"Value\":302,\"Label\":\"Yes (302 Found)\"},{\"Value\":301,\"Label\":\"Yes (301 Moved Permanently)\"}]\n",
 	) // 200 not allowed
 }
 
 func BenchmarkConfigRedirectToBase(b *testing.B) {
 	r := backend.NewConfigRedirectToBase(
-		backend.Backend.WebURLRedirectToBase.String(),
-		cfgmodel.WithFieldFromSectionSlice(backend.ConfigStructure),
+		be.WebURLRedirectToBase.String(),
+		cfgmodel.WithFieldFromSectionSlice(cfgStruct),
 	)
-	webURLRedirectToBasePath, err := backend.Backend.WebURLRedirectToBase.ToPath(0, 0)
+	webURLRedirectToBasePath, err := be.WebURLRedirectToBase.ToPath()
 	if err != nil {
 		b.Fatal(err)
 	}
 
 	sg := cfgmock.NewService(
-		cfgmock.WithPV(cfgmock.PathValue{
-			webURLRedirectToBasePath.String():                         2,
-			webURLRedirectToBasePath.Bind(scope.Website, 33).String(): 34,
-		}),
+		cfgmock.PathValue{
+			webURLRedirectToBasePath.String():                 2,
+			webURLRedirectToBasePath.BindWebsite(33).String(): 34,
+		},
 	).NewScoped(33, 1)
 
 	b.ReportAllocs()
@@ -109,8 +109,8 @@ func BenchmarkConfigRedirectToBase(b *testing.B) {
 		if err != nil {
 			b.Fatal(err)
 		}
-		if code != 34 {
-			b.Fatalf("Want %d Have %d", 34, code)
+		if code != 2 { // field allows only the default scope
+			b.Fatalf("Want %d Have %d", 2, code)
 		}
 	}
 }
@@ -18,4 +18,7 @@
 // to be defined and used in their administration user interface (UI). It does
 // not contain anything specific to other modules. Among many things it
 // handles the logic of authenticating and authorizing users.
+//
+// Importing the package registers its configuration structure and models in
+// package config/cfgregistry under the name "backend".
 package backend
@@ -0,0 +1,94 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Command configToStructure generates the element.SectionSlice, the default
+values and the PkgBackend with the cfgmodel types from the Magento 2
+etc/adminhtml/system.xml and etc/config.xml files of a module.
+
+Usage:
+	configToStructure -package contact -out ./contact \
+		-system app/code/Magento/Contact/etc/adminhtml/system.xml \
+		-config app/code/Magento/Contact/etc/config.xml
+
+Creates the files structure.go and backend.go in the output directory. The
+config.xml is optional.
+*/
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/corestoreio/csfw/codegen/configxml"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+func main() {
+	systemXML := flag.String("system", "", "Path to the system.xml file")
+	configXML := flag.String("config", "", "Optional path to the config.xml file")
+	pkg := flag.String("package", "", "Name of the Go package")
+	out := flag.String("out", ".", "Output directory")
+	flag.Parse()
+
+	if err := run(*systemXML, *configXML, *pkg, *out); err != nil {
+		fmt.Fprintf(os.Stderr, "%+v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(systemXML, configXML, pkg, out string) error {
+	if systemXML == "" || pkg == "" {
+		return errors.NewEmptyf("[configToStructure] Flags -system and -package are required")
+	}
+
+	fs, err := os.Open(systemXML)
+	if err != nil {
+		return errors.Wrap(err, "[configToStructure] Open system.xml")
+	}
+	defer fs.Close()
+	st, err := configxml.ParseSystemXML(fs)
+	if err != nil {
+		return errors.Wrap(err, "[configToStructure] ParseSystemXML")
+	}
+
+	if configXML != "" {
+		fc, err := os.Open(configXML)
+		if err != nil {
+			return errors.Wrap(err, "[configToStructure] Open config.xml")
+		}
+		defer fc.Close()
+		dm, err := configxml.ParseConfigXML(fc)
+		if err != nil {
+			return errors.Wrap(err, "[configToStructure] ParseConfigXML")
+		}
+		st.ApplyDefaults(dm)
+	}
+	structure, err := st.GenerateStructure(pkg)
+	if err != nil {
+		return errors.Wrap(err, "[configToStructure] GenerateStructure")
+	}
+	backend, err := st.GenerateBackend(pkg)
+	if err != nil {
+		return errors.Wrap(err, "[configToStructure] GenerateBackend")
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(out, "structure.go"), structure, 0644); err != nil {
+		return errors.Wrap(err, "[configToStructure] WriteFile structure.go")
+	}
+	return errors.Wrap(ioutil.WriteFile(filepath.Join(out, "backend.go"), backend, 0644), "[configToStructure] WriteFile backend.go")
+}
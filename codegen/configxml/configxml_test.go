@@ -0,0 +1,144 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configxml_test
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/corestoreio/csfw/codegen/configxml"
+	"github.com/corestoreio/csfw/config/element"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+var updateGolden = flag.Bool("update", false, "Update the golden files in testdata")
+
+func parseTestdata(t *testing.T) *configxml.Structure {
+	sys, err := os.Open(filepath.Join("testdata", "system.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sys.Close()
+	st, err := configxml.ParseSystemXML(sys)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	cfg, err := os.Open(filepath.Join("testdata", "config.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cfg.Close()
+	dm, err := configxml.ParseConfigXML(cfg)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	assert.Exactly(t, element.DefaultMap{
+		"contact/contact/enabled":           "1",
+		"contact/email/recipient_email":     "hello@example.com",
+		"contact/email/email_template":      "contact_email_email_template",
+		"general/country/allow":             "CH,DE,AT",
+		"catalog/product/flat":              `{"max_index_count":"64"}`,
+		"catalog/product/default_tax_group": "2",
+		"catalog/product/use_flat":          "1",
+	}, dm)
+	st.ApplyDefaults(dm)
+	return st
+}
+
+func TestParseSystemXML(t *testing.T) {
+	st := parseTestdata(t)
+
+	assert.Len(t, st.Sections, 2)
+	s := st.Sections[0]
+	assert.Exactly(t, "Contacts", s.Label)
+	assert.Exactly(t, "Magento_Contact::contact", s.Resource)
+	assert.Exactly(t, scope.PermStore, s.Scopes)
+
+	fs := s.Groups[1].Fields
+	assert.Exactly(t, `Email template chosen based on theme fallback when "Default" option is selected.`, fs[1].Comment)
+	assert.Exactly(t, "general/country/allow", fs[2].Route())
+	assert.Exactly(t, scope.PermWebsite, fs[2].Scopes)
+	assert.Exactly(t, element.TypeMultiselect, fs[2].Type)
+	assert.Exactly(t, "CH,DE,AT", fs[2].Default)
+	assert.Exactly(t, element.TypeObscure, fs[3].Type)
+	assert.Exactly(t, scope.PermDefault, fs[3].Scopes)
+	assert.Exactly(t, "nested_copy_to", fs[4].ID)
+	assert.Exactly(t, "contact/email/nested_copy_to", fs[4].Path)
+	assert.Exactly(t, "contact/email/copy_to", fs[4].Route())
+
+	assert.True(t, st.Sections[1].Hidden)
+	assert.Exactly(t, element.DefaultMap{
+		"contact/contact/enabled":           true,
+		"contact/email/recipient_email":     "hello@example.com",
+		"contact/email/email_template":      "contact_email_email_template",
+		"general/country/allow":             "CH,DE,AT",
+		"catalog/product/flat":              `{"max_index_count":"64"}`,
+		"catalog/product/default_tax_group": 2,
+		"catalog/product/use_flat":          1,
+	}, st.DefaultMap())
+}
+
+func TestParseSystemXMLNestedWithoutConfigPath(t *testing.T) {
+	_, err := configxml.ParseSystemXML(bytes.NewBufferString(`<config><system>
+<section id="payment"><group id="paypal"><group id="express"><group id="settings">
+<field id="active" type="select"/>
+</group></group></group></section>
+</system></config>`))
+	assert.True(t, errors.IsNotSupported(err), "Error: %+v", err)
+	assert.Contains(t, err.Error(), `"payment/paypal/express/settings"`)
+}
+
+func TestParseInvalid(t *testing.T) {
+	_, err := configxml.ParseSystemXML(bytes.NewBufferString("<config><system>"))
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+	_, err = configxml.ParseConfigXML(bytes.NewBufferString("<config><default>"))
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+}
+
+func TestGenerate(t *testing.T) {
+	st := parseTestdata(t)
+
+	tests := []struct {
+		golden string
+		gen    func(string) ([]byte, error)
+	}{
+		{"structure.go.golden", st.GenerateStructure},
+		{"backend.go.golden", st.GenerateBackend},
+	}
+	for i, test := range tests {
+		have, err := test.gen("contact")
+		if err != nil {
+			t.Fatalf("Index %d => %+v\n%s", i, err, have)
+		}
+		fn := filepath.Join("testdata", test.golden)
+		if *updateGolden {
+			if err := ioutil.WriteFile(fn, have, 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		want, err := ioutil.ReadFile(fn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Exactly(t, string(want), string(have), "Index %d", i)
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package configxml parses Magento 2 system.xml and config.xml files and
+// generates the Go source code for an element.SectionSlice, the default values
+// as element.DefaultMap and the typed cfgmodel values of a package.
+//
+// system.xml provides the sections, groups and fields including the scope
+// permissions showInDefault, showInWebsite and showInStore, the config_path,
+// the source_model and the backend_model. config.xml provides the default
+// values. Defaults without a field in the system.xml are getting added as
+// hidden fields, same as in the hand written packages.
+//
+// Nested groups are not supported by package element. Their fields get
+// flattened into the top level group with the IDs of the nested groups as
+// prefix, e.g. field active of the nested group express becomes express_active.
+// Such a field must define a config_path, otherwise ParseSystemXML returns a
+// NotSupported error.
+//
+// The command codegen/configToStructure wraps this package.
+package configxml
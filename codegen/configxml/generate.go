@@ -0,0 +1,411 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configxml
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/corestoreio/csfw/config/element"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// commentWidth maximum line length of a generated comment.
+const commentWidth = 77
+
+var tplFuncs = template.FuncMap{
+	"goValue":  goValue,
+	"goString": goString,
+	"goPerm":   goPerm,
+	"goType": func(ft element.FieldType) string {
+		return "element." + ft.String()
+	},
+	"wrap": wrap,
+}
+
+// goValue returns the Go source code representation of a default value.
+func goValue(v interface{}) string {
+	switch vt := v.(type) {
+	case string:
+		return goString(vt)
+	case bool:
+		return strconv.FormatBool(vt)
+	case int:
+		return strconv.Itoa(vt)
+	}
+	return fmt.Sprintf("%#v", v)
+}
+
+// goString returns a raw string literal if possible.
+func goString(s string) string {
+	if strings.ContainsRune(s, '`') {
+		return strconv.Quote(s)
+	}
+	return "`" + s + "`"
+}
+
+// goPerm returns the name of the predefined scope.Perm constants.
+func goPerm(p scope.Perm) string {
+	switch p {
+	case scope.PermStore:
+		return "scope.PermStore"
+	case scope.PermWebsite:
+		return "scope.PermWebsite"
+	case scope.PermDefault:
+		return "scope.PermDefault"
+	}
+	var ts []string
+	for _, t := range []scope.Type{scope.Default, scope.Website, scope.Store} {
+		if p.Has(t) {
+			ts = append(ts, "scope."+t.String())
+		}
+	}
+	return "scope.Perm(0).Set(" + strings.Join(ts, ", ") + ")"
+}
+
+// wrap splits a text into comment lines.
+func wrap(s string) []string {
+	var lines []string
+	var line string
+	for _, w := range strings.Fields(s) {
+		if line != "" && len(line)+len(w)+1 > commentWidth {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += w
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+const tplHeader = `// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Auto generated by codegen/configToStructure. Please adjust the code.
+
+package {{ .Package }}
+`
+
+const tplStructure = tplHeader + `
+import (
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/config/element"
+{{- if .UsesText }}
+	"github.com/corestoreio/csfw/storage/text"
+{{- end }}
+{{- if .UsesScope }}
+	"github.com/corestoreio/csfw/store/scope"
+{{- end }}
+)
+
+// MustNewConfigStructure same as NewConfigStructure() but panics on error.
+func MustNewConfigStructure() element.SectionSlice {
+	ss, err := NewConfigStructure()
+	if err != nil {
+		panic(err)
+	}
+	return ss
+}
+
+// NewConfigStructure global configuration structure for this package.
+// Used in frontend (to display the user all the settings) and in
+// backend (scope checks and default values). See the source code
+// of this function for the overall available sections, groups and fields.
+func NewConfigStructure() (element.SectionSlice, error) {
+	return element.NewConfiguration(
+{{- range $i, $s := .Sections }}
+{{- if and $s.Hidden (eq $i $.FirstHidden) }}
+
+		// Hidden Configuration, may be visible somewhere else ...
+{{- end }}
+		element.Section{
+			ID: cfgpath.NewRoute({{ goString $s.ID }}),
+{{- if $s.Label }}
+			Label: text.Chars({{ goString $s.Label }}),
+{{- end }}
+{{- if $s.SortOrder }}
+			SortOrder: {{ $s.SortOrder }},
+{{- end }}
+{{- if $s.Scopes }}
+			Scopes: {{ goPerm $s.Scopes }},
+{{- end }}
+{{- if $s.Resource }}
+			// Resource: {{ $s.Resource }}
+{{- end }}
+			Groups: element.NewGroupSlice(
+{{- range $s.Groups }}
+				element.Group{
+					ID: cfgpath.NewRoute({{ goString .ID }}),
+{{- if .Label }}
+					Label: text.Chars({{ goString .Label }}),
+{{- end }}
+{{- if .Comment }}
+					Comment: text.Chars({{ goString .Comment }}),
+{{- end }}
+{{- if .SortOrder }}
+					SortOrder: {{ .SortOrder }},
+{{- end }}
+{{- if .Scopes }}
+					Scopes: {{ goPerm .Scopes }},
+{{- end }}
+					Fields: element.NewFieldSlice(
+{{- range .Fields }}
+						element.Field{
+							// Path: {{ .Path }}
+							ID: cfgpath.NewRoute({{ goString .ID }}),
+{{- if .ConfigPath }}
+							ConfigPath: cfgpath.NewRoute({{ goString .ConfigPath }}),
+{{- end }}
+{{- if .Label }}
+							Label: text.Chars({{ goString .Label }}),
+{{- end }}
+{{- if .Comment }}
+							Comment: text.Chars({{ goString .Comment }}),
+{{- end }}
+{{- if .Tooltip }}
+							Tooltip: text.Chars({{ goString .Tooltip }}),
+{{- end }}
+							Type: {{ goType .Type }},
+{{- if .SortOrder }}
+							SortOrder: {{ .SortOrder }},
+{{- end }}
+{{- if .IsHidden }}
+							Visible: element.VisibleNo,
+{{- else }}
+							Visible: element.VisibleYes,
+{{- end }}
+{{- if .Scopes }}
+							Scopes: {{ goPerm .Scopes }},
+{{- end }}
+{{- if .HasDefault }}
+							Default: {{ goValue .Default }},
+{{- end }}
+{{- if .BackendModel }}
+							// BackendModel: {{ .BackendModel }}
+{{- end }}
+{{- if .SourceModel }}
+							// SourceModel: {{ .SourceModel }}
+{{- end }}
+						},
+{{- end }}
+					),
+				},
+{{- end }}
+			),
+		},
+{{- end }}
+	)
+}
+
+// DefaultConfiguration returns the default values from the config.xml of all
+// fields of this package.
+func DefaultConfiguration() element.DefaultMap {
+	return element.DefaultMap{
+{{- range .Defaults }}
+		{{ goString .Route }}: {{ goValue .Value }},
+{{- end }}
+	}
+}
+`
+
+const tplBackend = tplHeader + `
+import (
+	"sync"
+
+	"github.com/corestoreio/csfw/config/cfgmodel"
+	"github.com/corestoreio/csfw/config/element"
+)
+
+// PkgBackend just exported for the sake of documentation. See fields
+// for more information. The PkgBackend handles the reading and writing
+// of configuration values within this package.
+type PkgBackend struct {
+	sync.Mutex
+{{- range .Models }}
+	// {{ .Name }} => {{ .Label }}.
+{{- range wrap .Comment }}
+	// {{ . }}
+{{- end }}
+	// Path: {{ .Route }}
+{{- if .BackendModel }}
+	// BackendModel: {{ .BackendModel }}
+{{- end }}
+{{- if .SourceModel }}
+	// SourceModel: {{ .SourceModel }}
+{{- end }}
+	{{ .Name }} cfgmodel.{{ .Type }}
+{{ end }}
+}
+
+// NewBackend initializes the global configuration models containing the
+// cfgpath.Route variable to the appropriate entry.
+// The function Load() will be executed to apply the SectionSlice
+// to all models. See Load() for more details.
+func NewBackend(cfgStruct element.SectionSlice) *PkgBackend {
+	return (&PkgBackend{}).Load(cfgStruct)
+}
+
+// Load creates the configuration models for each PkgBackend field.
+// Internal mutex will protect the fields during loading.
+// The argument SectionSlice will be applied to all models.
+func (pp *PkgBackend) Load(cfgStruct element.SectionSlice) *PkgBackend {
+	pp.Lock()
+	defer pp.Unlock()
+
+	opt := cfgmodel.WithFieldFromSectionSlice(cfgStruct)
+{{- range .Models }}
+	pp.{{ .Name }} = cfgmodel.New{{ .Type }}({{ goString .Route }}, opt)
+{{- end }}
+
+	return pp
+}
+`
+
+type routeValue struct {
+	Route string
+	Value interface{}
+}
+
+// model describes a field of the generated PkgBackend.
+type model struct {
+	Field
+	Name string
+	// Type of the cfgmodel package: Bool, Str or StringCSV
+	Type  string
+	Route string
+}
+
+func execute(name, tpl string, data interface{}) ([]byte, error) {
+	t, err := template.New(name).Funcs(tplFuncs).Parse(tpl)
+	if err != nil {
+		return nil, errors.Wrapf(err, "[configxml] Parse template %q", name)
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return nil, errors.Wrapf(err, "[configxml] Execute template %q", name)
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return buf.Bytes(), errors.Wrapf(err, "[configxml] format.Source %q", name)
+	}
+	return src, nil
+}
+
+// GenerateStructure creates the Go source code for the functions
+// NewConfigStructure, MustNewConfigStructure and DefaultConfiguration.
+func (st *Structure) GenerateStructure(pkg string) ([]byte, error) {
+	data := struct {
+		Package     string
+		Sections    []Section
+		FirstHidden int
+		UsesText    bool
+		UsesScope   bool
+		Defaults    []routeValue
+	}{
+		Package:     pkg,
+		Sections:    st.Sections,
+		FirstHidden: -1,
+	}
+	for i, s := range st.Sections {
+		if s.Hidden && data.FirstHidden < 0 {
+			data.FirstHidden = i
+		}
+		data.UsesText = data.UsesText || s.Label != ""
+		data.UsesScope = data.UsesScope || s.Scopes > 0
+		for _, g := range s.Groups {
+			data.UsesText = data.UsesText || g.Label != "" || g.Comment != ""
+			data.UsesScope = data.UsesScope || g.Scopes > 0
+			for _, f := range g.Fields {
+				data.UsesText = data.UsesText || f.Label != "" || f.Comment != "" || f.Tooltip != ""
+				data.UsesScope = data.UsesScope || f.Scopes > 0
+			}
+		}
+	}
+	dm := st.DefaultMap()
+	routes := make([]string, 0, len(dm))
+	for r := range dm {
+		routes = append(routes, r)
+	}
+	sort.Strings(routes)
+	for _, r := range routes {
+		data.Defaults = append(data.Defaults, routeValue{Route: r, Value: dm[r]})
+	}
+
+	src, err := execute("structure", tplStructure, data)
+	return src, errors.Wrap(err, "[configxml] GenerateStructure")
+}
+
+// GenerateBackend creates the Go source code for the type PkgBackend which
+// contains a cfgmodel type for each visible field. Fields with a Yesno or
+// Enabledisable source model are of type cfgmodel.Bool, multiselect fields
+// of type cfgmodel.StringCSV and all others of type cfgmodel.Str.
+func (st *Structure) GenerateBackend(pkg string) ([]byte, error) {
+	data := struct {
+		Package string
+		Models  []model
+	}{
+		Package: pkg,
+	}
+	seen := make(map[string]bool)
+	for _, s := range st.Sections {
+		for _, g := range s.Groups {
+			for _, f := range g.Fields {
+				switch {
+				case f.Visible != element.VisibleYes, f.Type == element.TypeButton, f.Type == element.TypeLabel, seen[f.Route()]:
+					continue
+				}
+				seen[f.Route()] = true
+				m := model{
+					Field: f,
+					Name:  util.UnderscoreCamelize(f.Route()),
+					Type:  "Str",
+					Route: f.Route(),
+				}
+				switch {
+				case isBoolSource(f.SourceModel):
+					m.Type = "Bool"
+				case f.Type == element.TypeMultiselect:
+					m.Type = "StringCSV"
+				}
+				data.Models = append(data.Models, m)
+			}
+		}
+	}
+	src, err := execute("backend", tplBackend, data)
+	return src, errors.Wrap(err, "[configxml] GenerateBackend")
+}
@@ -0,0 +1,403 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configxml
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/corestoreio/csfw/config/element"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+type xmlScopes struct {
+	ShowInDefault bool `xml:"showInDefault,attr"`
+	ShowInWebsite bool `xml:"showInWebsite,attr"`
+	ShowInStore   bool `xml:"showInStore,attr"`
+}
+
+func (s xmlScopes) perm() scope.Perm {
+	var p scope.Perm
+	if s.ShowInDefault {
+		p = p.Set(scope.Default)
+	}
+	if s.ShowInWebsite {
+		p = p.Set(scope.Website)
+	}
+	if s.ShowInStore {
+		p = p.Set(scope.Store)
+	}
+	return p
+}
+
+type xmlSystem struct {
+	Sections []xmlSection `xml:"system>section"`
+}
+
+type xmlSection struct {
+	xmlScopes
+	ID        string     `xml:"id,attr"`
+	SortOrder int        `xml:"sortOrder,attr"`
+	Label     string     `xml:"label"`
+	Resource  string     `xml:"resource"`
+	Groups    []xmlGroup `xml:"group"`
+}
+
+type xmlGroup struct {
+	xmlScopes
+	ID        string     `xml:"id,attr"`
+	SortOrder int        `xml:"sortOrder,attr"`
+	Label     string     `xml:"label"`
+	Comment   string     `xml:"comment"`
+	Fields    []xmlField `xml:"field"`
+	Groups    []xmlGroup `xml:"group"`
+}
+
+type xmlField struct {
+	xmlScopes
+	ID           string `xml:"id,attr"`
+	Type         string `xml:"type,attr"`
+	SortOrder    int    `xml:"sortOrder,attr"`
+	Label        string `xml:"label"`
+	Comment      string `xml:"comment"`
+	Tooltip      string `xml:"tooltip"`
+	ConfigPath   string `xml:"config_path"`
+	SourceModel  string `xml:"source_model"`
+	BackendModel string `xml:"backend_model"`
+}
+
+// Section represents a parsed section of a system.xml file.
+type Section struct {
+	ID        string
+	Label     string
+	Resource  string
+	SortOrder int
+	Scopes    scope.Perm
+	Groups    []Group
+	// Hidden sections contain only fields from the config.xml.
+	Hidden bool
+}
+
+// Group represents a parsed group of a system.xml file.
+type Group struct {
+	ID        string
+	Label     string
+	Comment   string
+	SortOrder int
+	Scopes    scope.Perm
+	Fields    []Field
+}
+
+// Field represents a parsed field of a system.xml file or a hidden field from
+// the config.xml.
+type Field struct {
+	ID string
+	// Path section/group/field route without a scope.
+	Path string
+	// ConfigPath overwrites the storage route.
+	ConfigPath   string
+	Label        string
+	Comment      string
+	Tooltip      string
+	Type         element.FieldType
+	SortOrder    int
+	Scopes       scope.Perm
+	Visible      element.Visible
+	SourceModel  string
+	BackendModel string
+	// Default contains the typed default value from the config.xml: bool, int
+	// or string. Nil if the config.xml has no value.
+	Default interface{}
+}
+
+// Route returns the storage route: either the ConfigPath or the Path.
+func (f Field) Route() string {
+	if f.ConfigPath != "" {
+		return f.ConfigPath
+	}
+	return f.Path
+}
+
+// HasDefault returns true if the config.xml contains a value for the field.
+func (f Field) HasDefault() bool {
+	return f.Default != nil
+}
+
+// IsHidden returns true for fields which are not visible in the backend.
+func (f Field) IsHidden() bool {
+	return f.Visible == element.VisibleNo
+}
+
+// Structure contains the parsed sections of a Magento module.
+type Structure struct {
+	Sections []Section
+}
+
+var fieldTypes = map[string]element.FieldType{
+	"button":        element.TypeButton,
+	"label":         element.TypeLabel,
+	"hidden":        element.TypeHidden,
+	"image":         element.TypeImage,
+	"file":          element.TypeCustom,
+	"obscure":       element.TypeObscure,
+	"password":      element.TypeObscure,
+	"multiselect":   element.TypeMultiselect,
+	"select":        element.TypeSelect,
+	"allowspecific": element.TypeSelect,
+	"text":          element.TypeText,
+	"textarea":      element.TypeTextarea,
+	"time":          element.TypeTime,
+}
+
+// cleanText removes line breaks and duplicate white spaces from a label or
+// comment.
+func cleanText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// ParseSystemXML parses a Magento 2 etc/adminhtml/system.xml file. The fields
+// of nested groups get flattened, see parseFields. Error behaviour: NotValid
+// or NotSupported.
+func ParseSystemXML(r io.Reader) (*Structure, error) {
+	var sys xmlSystem
+	if err := xml.NewDecoder(r).Decode(&sys); err != nil {
+		return nil, errors.NewNotValid(err, "[configxml] ParseSystemXML.Decode")
+	}
+	st := new(Structure)
+	for _, xs := range sys.Sections {
+		s := Section{
+			ID:        xs.ID,
+			Label:     cleanText(xs.Label),
+			Resource:  cleanText(xs.Resource),
+			SortOrder: xs.SortOrder,
+			Scopes:    xs.perm(),
+		}
+		for _, xg := range xs.Groups {
+			g := Group{
+				ID:        xg.ID,
+				Label:     cleanText(xg.Label),
+				Comment:   cleanText(xg.Comment),
+				SortOrder: xg.SortOrder,
+				Scopes:    xg.perm(),
+			}
+			fs, err := parseFields(xs.ID+"/"+xg.ID, nil, xg)
+			if err != nil {
+				return nil, errors.Wrap(err, "[configxml] ParseSystemXML")
+			}
+			g.Fields = fs
+			s.Groups = append(s.Groups, g)
+		}
+		st.Sections = append(st.Sections, s)
+	}
+	return st, nil
+}
+
+// parseFields returns the fields of a group and of all its nested groups.
+// Package element supports only three levels, so the fields of a nested group
+// get flattened into the top level group. Their ID gets prefixed with the IDs
+// of the nested groups and they must define a config_path because their
+// Magento route contains more than three levels. Error behaviour:
+// NotSupported.
+func parseFields(groupRoute string, nested []string, xg xmlGroup) ([]Field, error) {
+	var prefix string
+	if len(nested) > 0 {
+		prefix = strings.Join(nested, "_") + "_"
+	}
+	var fs []Field
+	for _, xf := range xg.Fields {
+		f := Field{
+			ID:           prefix + xf.ID,
+			Path:         groupRoute + "/" + prefix + xf.ID,
+			ConfigPath:   strings.TrimSpace(xf.ConfigPath),
+			Label:        cleanText(xf.Label),
+			Comment:      cleanText(xf.Comment),
+			Tooltip:      cleanText(xf.Tooltip),
+			Type:         fieldTypes[xf.Type],
+			SortOrder:    xf.SortOrder,
+			Scopes:       xf.perm(),
+			Visible:      element.VisibleYes,
+			SourceModel:  strings.TrimSpace(xf.SourceModel),
+			BackendModel: strings.TrimSpace(xf.BackendModel),
+		}
+		if f.Type == 0 {
+			f.Type = element.TypeText
+		}
+		if len(nested) > 0 && f.ConfigPath == "" {
+			return nil, errors.NewNotSupportedf("[configxml] Field %q of nested group %q: Missing config_path", xf.ID, groupRoute+"/"+strings.Join(nested, "/"))
+		}
+		fs = append(fs, f)
+	}
+	for _, xn := range xg.Groups {
+		nfs, err := parseFields(groupRoute, append(nested[:len(nested):len(nested)], xn.ID), xn)
+		if err != nil {
+			return nil, err
+		}
+		fs = append(fs, nfs...)
+	}
+	return fs, nil
+}
+
+type xmlNode struct {
+	XMLName xml.Name
+	Content string    `xml:",chardata"`
+	Nodes   []xmlNode `xml:",any"`
+}
+
+type xmlConfig struct {
+	Default xmlNode `xml:"default"`
+}
+
+// toMap converts the children of a node into a map for the JSON encoding.
+func (n xmlNode) toMap() map[string]interface{} {
+	m := make(map[string]interface{}, len(n.Nodes))
+	for _, c := range n.Nodes {
+		if len(c.Nodes) > 0 {
+			m[c.XMLName.Local] = c.toMap()
+			continue
+		}
+		m[c.XMLName.Local] = strings.TrimSpace(c.Content)
+	}
+	return m
+}
+
+// ParseConfigXML parses the default values of a Magento 2 etc/config.xml
+// file. The keys of the returned map are routes with three levels. Deeper
+// levels get encoded as a JSON object, same as Magento does when it stores
+// them. Error behaviour: NotValid.
+func ParseConfigXML(r io.Reader) (element.DefaultMap, error) {
+	var cfg xmlConfig
+	if err := xml.NewDecoder(r).Decode(&cfg); err != nil {
+		return nil, errors.NewNotValid(err, "[configxml] ParseConfigXML.Decode")
+	}
+	dm := make(element.DefaultMap)
+	for _, s := range cfg.Default.Nodes {
+		for _, g := range s.Nodes {
+			for _, f := range g.Nodes {
+				route := s.XMLName.Local + "/" + g.XMLName.Local + "/" + f.XMLName.Local
+				if len(f.Nodes) == 0 {
+					dm[route] = strings.TrimSpace(f.Content)
+					continue
+				}
+				data, err := json.Marshal(f.toMap())
+				if err != nil {
+					return nil, errors.Wrapf(err, "[configxml] ParseConfigXML.json.Marshal %q", route)
+				}
+				dm[route] = string(data)
+			}
+		}
+	}
+	return dm, nil
+}
+
+// isBoolSource returns true for source models which return a yes/no value.
+func isBoolSource(sourceModel string) bool {
+	switch sourceModel {
+	case `Magento\Config\Model\Config\Source\Yesno`, `Magento\Config\Model\Config\Source\Enabledisable`,
+		`Magento\Config\Model\Config\Source\Yesnocustom`:
+		return true
+	}
+	return false
+}
+
+// typedDefault converts the raw string from the config.xml into a bool, an int
+// or keeps the string.
+func typedDefault(raw string, boolish bool) interface{} {
+	if boolish && (raw == "0" || raw == "1") {
+		return raw == "1"
+	}
+	if i, err := strconv.Atoi(raw); err == nil && strconv.Itoa(i) == raw {
+		return i
+	}
+	return raw
+}
+
+// ApplyDefaults sets the default values to the fields. Defaults without a
+// field are getting added as hidden fields into hidden sections.
+func (st *Structure) ApplyDefaults(dm element.DefaultMap) {
+	used := make(map[string]bool, len(dm))
+	for si := range st.Sections {
+		for gi := range st.Sections[si].Groups {
+			fs := st.Sections[si].Groups[gi].Fields
+			for fi := range fs {
+				raw, ok := dm[fs[fi].Route()]
+				if !ok {
+					continue
+				}
+				used[fs[fi].Route()] = true
+				fs[fi].Default = typedDefault(raw.(string), isBoolSource(fs[fi].SourceModel))
+			}
+		}
+	}
+
+	routes := make([]string, 0, len(dm))
+	for r := range dm {
+		if !used[r] {
+			routes = append(routes, r)
+		}
+	}
+	sort.Strings(routes)
+	for _, r := range routes {
+		parts := strings.Split(r, "/")
+		st.addHidden(parts[0], parts[1], Field{
+			ID:      parts[2],
+			Path:    r,
+			Type:    element.TypeHidden,
+			Visible: element.VisibleNo,
+			// without a source model nobody knows if 0 and 1 are booleans.
+			Default: typedDefault(dm[r].(string), false),
+		})
+	}
+}
+
+func (st *Structure) addHidden(sectionID, groupID string, f Field) {
+	si := -1
+	for i, s := range st.Sections {
+		if s.Hidden && s.ID == sectionID {
+			si = i
+		}
+	}
+	if si < 0 {
+		st.Sections = append(st.Sections, Section{ID: sectionID, Hidden: true})
+		si = len(st.Sections) - 1
+	}
+	gs := st.Sections[si].Groups
+	for i := range gs {
+		if gs[i].ID == groupID {
+			gs[i].Fields = append(gs[i].Fields, f)
+			return
+		}
+	}
+	st.Sections[si].Groups = append(gs, Group{ID: groupID, Fields: []Field{f}})
+}
+
+// DefaultMap returns the typed default values of all fields.
+func (st *Structure) DefaultMap() element.DefaultMap {
+	dm := make(element.DefaultMap)
+	for _, s := range st.Sections {
+		for _, g := range s.Groups {
+			for _, f := range g.Fields {
+				if f.Default != nil {
+					dm[f.Route()] = f.Default
+				}
+			}
+		}
+	}
+	return dm
+}
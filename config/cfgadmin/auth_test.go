@@ -0,0 +1,78 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgadmin_test
+
+import (
+	"crypto/sha256"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/cfgadmin"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/config/element"
+	"github.com/corestoreio/csfw/net/auth"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/corestoreio/csfw/util/hashpool"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	if err := hashpool.Register("sha256", sha256.New); err != nil {
+		panic(err)
+	}
+}
+
+func TestNewHandlerRequiresAuthentication(t *testing.T) {
+	_, err := cfgadmin.NewHandler(config.MustNewService(config.NewInMemoryStore()), element.SectionSlice{}, nil)
+	assert.True(t, errors.IsNotValid(err), "%+v", err)
+}
+
+func TestHandlerAuthentication(t *testing.T) {
+	as, err := auth.New(
+		auth.WithRootConfig(config.MustNewService(config.NewInMemoryStore())),
+		auth.WithSimpleBasicAuth("admin", "s3cr3t", "Configuration"),
+		auth.WithResourceACLs(nil, nil),
+	)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	h, srv := newAuthHandler(t, as.WithAuthentication)
+
+	newReq := func() *http.Request {
+		req := httptest.NewRequest("PUT", "/values/currency/options/base?website=1", strings.NewReader(`{"value":"EUR"}`))
+		return req.WithContext(scope.WithContext(req.Context(), 1, 2))
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, newReq())
+	assert.Exactly(t, http.StatusUnauthorized, rec.Code, "Request without credentials")
+	assert.Contains(t, rec.Header().Get("WWW-Authenticate"), `Basic realm="Configuration"`)
+	p := cfgpath.MustNewByParts("currency/options/base").BindWebsite(1)
+	_, err = srv.String(p)
+	assert.True(t, errors.IsNotFound(err), "Value must not be written: %+v", err)
+
+	req := newReq()
+	req.SetBasicAuth("admin", "s3cr3t")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Exactly(t, http.StatusOK, rec.Code, rec.Body.String())
+	v, err := srv.String(p)
+	assert.NoError(t, err)
+	assert.Exactly(t, "EUR", v)
+}
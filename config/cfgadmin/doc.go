@@ -0,0 +1,61 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cfgadmin provides an HTTP API to inspect and change the
+// configuration without writing SQL.
+//
+// The Handler serves JSON via net/response.Print:
+//
+//		GET /sections                             all sections, groups and fields
+//		GET /sections/{section}[/{group}[/{field}]]
+//		GET /values/{section}[/{group}[/{field}]]?website=1&store=2
+//		PUT /values/{section}/{group}/{field}?website=1
+//		GET /forms/{section}?website=1&store=2
+//		POST /forms/{section}?website=1&store=2
+//
+// Reading a value returns the effective value for the website and store
+// together with the scope in which it has been found. Writing a value checks
+// the allowed scopes and the type of the element.Field via an
+// element.ValidatingWriter. The body of a PUT request must contain a JSON
+// object like {"value":"EUR"} or {"value":["DE","CH"]} for multiselect fields.
+// The target scope of a write is the store if the query contains a store,
+// otherwise the website or the default scope. Values of element.TypeObscure
+// fields, like passwords and API secrets, are never sent to the client. The
+// responses contain ObscuredValue instead and writing ObscuredValue back
+// keeps the stored value.
+//
+// The forms endpoint renders a section with its groups and fields for a scope.
+// Clients which accept text/html receive an HTML form rendered by
+// Handler.FormTemplate, all other clients receive the Form as JSON schema for
+// a single page application. Fields which are not allowed in the scope are
+// hidden, select and multiselect fields contain the options of the
+// source.Slice registered via SetSource. Below the default scope each field has
+// a "Use Website" or "Use Default" checkbox. A POSTed form gets parsed by
+// Handler.SubmitForm and all changed values are written through the validating
+// writer. The input names follow Magento:
+//
+//		groups[{group}][fields][{field}][value]
+//		groups[{group}][fields][{field}][inherit]
+//
+// To protect the forms against CSRF attacks a POST request must contain an
+// Origin or Referer header with the host of the request or one of the
+// Handler.TrustedOrigins. Obscure fields get rendered with ObscuredValue
+// which keeps the stored value when submitted unchanged.
+//
+// NewHandler requires an authentication middleware, for example of net/auth or
+// net/jwt, and every request must pass it. Mount the Handler below a prefix:
+//
+//		h, err := cfgadmin.NewHandler(configService, backend.ConfigStructure, jwtService.WithToken)
+//		mux.Handle("/admin/config/", http.StripPrefix("/admin/config", h))
+package cfgadmin
@@ -0,0 +1,482 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgadmin
+
+import (
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/config/element"
+	"github.com/corestoreio/csfw/config/source"
+	"github.com/corestoreio/csfw/log"
+	"github.com/corestoreio/csfw/net/response"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// Deleter removes the value of a path in exactly one scope. If the ReadWriter
+// implements Deleter a checked inheritance checkbox of a submitted form
+// removes the value of the current scope.
+type Deleter interface {
+	Delete(cfgpath.Path) error
+}
+
+// Form describes the HTML form of a section for a scope. Printed as JSON it
+// acts as a schema for a single page application.
+type Form struct {
+	Section string `json:"section"`
+	Label   string `json:"label,omitempty"`
+	// Scope in which the form writes its values: default, websites or
+	// stores.
+	Scope   string      `json:"scope"`
+	ScopeID int64       `json:"scope_id"`
+	Groups  []FormGroup `json:"groups"`
+	// Errors contains the validation errors of a submission.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// FormGroup a group of fields within a Form.
+type FormGroup struct {
+	ID      string      `json:"id"`
+	Label   string      `json:"label,omitempty"`
+	Comment string      `json:"comment,omitempty"`
+	Fields  []FormField `json:"fields"`
+}
+
+// FormField describes a single input element. Name contains the name of the
+// HTML input element and gets used to parse the submitted form.
+type FormField struct {
+	ID    string `json:"id"`
+	Route string `json:"route"`
+	Name  string `json:"name"`
+	// Type lower case name of the element.FieldType, e.g. text, select or
+	// multiselect.
+	Type    string `json:"type"`
+	Label   string `json:"label,omitempty"`
+	Comment string `json:"comment,omitempty"`
+	Tooltip string `json:"tooltip,omitempty"`
+	// Value the effective value, might be inherited from a parent scope.
+	Value string `json:"value"`
+	// Values the selected values of a multiselect field.
+	Values     []string `json:"values,omitempty"`
+	Options    []Option `json:"options,omitempty"`
+	CanBeEmpty bool     `json:"can_be_empty,omitempty"`
+	// HTML contains the output of FieldTyper.ToHTML for custom field types
+	// and replaces the default input element.
+	HTML template.HTML `json:"html,omitempty"`
+	// Inherit is nil in the default scope.
+	Inherit *Inherit `json:"inherit,omitempty"`
+	// own is true if the value has been stored in the scope of the form.
+	own bool
+}
+
+// Option a selectable value of a select or multiselect field provided by a
+// source.Slice.
+type Option struct {
+	Value    string `json:"value"`
+	Label    string `json:"label"`
+	Selected bool   `json:"selected,omitempty"`
+}
+
+// Inherit describes the "Use Website" or "Use Default" checkbox of a field.
+type Inherit struct {
+	Name  string `json:"name"`
+	Label string `json:"label"`
+	// Scope of the parent: websites or default.
+	Scope string `json:"scope"`
+	// Checked is true if the scope of the form contains no value.
+	Checked bool `json:"checked"`
+	// Value the value of the parent scope.
+	Value string `json:"value"`
+}
+
+// formTemplate renders a Form with the name "form". Each field gets wrapped
+// in a div with the class "field" and its type.
+var formTemplate = template.Must(template.New("form").Parse(`<form method="post" class="config-form" data-section="{{.Section}}" data-scope="{{.Scope}}" data-scope-id="{{.ScopeID}}">
+{{if .Label}}<h1>{{.Label}}</h1>
+{{end}}{{if .Errors}}<ul class="errors">{{range .Errors}}<li>{{.}}</li>{{end}}</ul>
+{{end}}{{range .Groups}}<fieldset id="{{.ID}}">
+{{if .Label}}<legend>{{.Label}}</legend>
+{{end}}{{if .Comment}}<p class="comment">{{.Comment}}</p>
+{{end}}{{range .Fields}}<div class="field field-{{.Type}}">
+<label for="{{.Route}}">{{.Label}}</label>
+{{if .HTML}}{{.HTML}}{{else if eq .Type "select"}}<select id="{{.Route}}" name="{{.Name}}"{{if .Inherit}}{{if .Inherit.Checked}} disabled{{end}}{{end}}>{{range .Options}}<option value="{{.Value}}"{{if .Selected}} selected{{end}}>{{.Label}}</option>{{end}}</select>
+{{else if eq .Type "multiselect"}}{{if .CanBeEmpty}}<input type="hidden" name="{{.Name}}" value="">{{end}}<select id="{{.Route}}" name="{{.Name}}" multiple{{if .Inherit}}{{if .Inherit.Checked}} disabled{{end}}{{end}}>{{range .Options}}<option value="{{.Value}}"{{if .Selected}} selected{{end}}>{{.Label}}</option>{{end}}</select>
+{{else if eq .Type "textarea"}}<textarea id="{{.Route}}" name="{{.Name}}"{{if .Inherit}}{{if .Inherit.Checked}} disabled{{end}}{{end}}>{{.Value}}</textarea>
+{{else if eq .Type "label"}}<span id="{{.Route}}">{{.Value}}</span>
+{{else if ne .Type "button"}}<input type="{{if eq .Type "obscure"}}password{{else if eq .Type "hidden"}}hidden{{else}}text{{end}}" id="{{.Route}}" name="{{.Name}}" value="{{.Value}}"{{if .Inherit}}{{if .Inherit.Checked}} disabled{{end}}{{end}}>
+{{end}}{{if .Inherit}}<input type="checkbox" id="{{.Inherit.Name}}" name="{{.Inherit.Name}}" value="1"{{if .Inherit.Checked}} checked{{end}}> <label for="{{.Inherit.Name}}">{{.Inherit.Label}}</label>
+{{end}}{{if .Tooltip}}<span class="tooltip">{{.Tooltip}}</span>
+{{end}}{{if .Comment}}<p class="comment">{{.Comment}}</p>
+{{end}}</div>
+{{end}}</fieldset>
+{{end}}<button type="submit">Save Config</button>
+</form>
+`))
+
+// formScope returns the scope in which a form for the website and store
+// writes its values.
+func formScope(websiteID, storeID int64) scope.TypeID {
+	switch {
+	case storeID > 0:
+		return scope.Store.Pack(storeID)
+	case websiteID > 0:
+		return scope.Website.Pack(websiteID)
+	}
+	return scope.DefaultTypeID
+}
+
+// allowed returns true if a field with the permission can be shown in the
+// scope. An empty permission allows the default scope, same as in the
+// element.ValidatingWriter.
+func allowed(perm scope.Perm, s scope.Type) bool {
+	if perm == 0 {
+		perm = scope.PermDefault
+	}
+	return perm.Has(s)
+}
+
+// Form creates the form of a section for the website and store. A form for a
+// store contains only the fields which are allowed in the store scope. An
+// empty scope permission of a section or group does not restrict its fields. Fields
+// with Visible set to VisibleNo are not part of the form. The returned Form
+// can be printed as JSON or as HTML via the FormTemplate. Error behaviour:
+// NotFound or Unauthorized.
+func (h *Handler) Form(sectionID string, websiteID, storeID int64) (Form, error) {
+	sec, _, err := h.sections.Find(cfgpath.NewRoute(sectionID))
+	if err != nil {
+		return Form{}, errors.Wrap(err, "[cfgadmin] Handler.Form")
+	}
+	id := formScope(websiteID, storeID)
+	if sec.Scopes > 0 && !sec.Scopes.Has(id.Type()) {
+		return Form{}, errors.NewUnauthorizedf("[cfgadmin] Handler.Form Section %q not allowed in Scope %q", sectionID, id.Type())
+	}
+
+	frm := Form{
+		Section: sec.ID.String(),
+		Label:   sec.Label.String(),
+		Scope:   id.Type().StrType(),
+		ScopeID: id.ID(),
+	}
+	// copy before sorting because the handler must not modify its sections.
+	groups := append(element.GroupSlice(nil), sec.Groups...).Sort()
+	for _, g := range groups {
+		if g.Scopes > 0 && !g.Scopes.Has(id.Type()) {
+			continue
+		}
+		fg := FormGroup{
+			ID:      g.ID.String(),
+			Label:   g.Label.String(),
+			Comment: g.Comment.String(),
+		}
+		fields := append(element.FieldSlice(nil), g.Fields...).Sort()
+		for _, f := range fields {
+			if f.Visible == element.VisibleNo || !allowed(f.Scopes, id.Type()) {
+				continue
+			}
+			r, err := f.Route(sec.ID, g.ID)
+			if err != nil {
+				return Form{}, errors.Wrapf(err, "[cfgadmin] Handler.Form Section %q Group %q", sec.ID, g.ID)
+			}
+			ff, err := h.formField(routedField{route: r, Field: f}, g.ID.String(), websiteID, storeID)
+			if err != nil {
+				return Form{}, errors.Wrap(err, "[cfgadmin] Handler.Form")
+			}
+			fg.Fields = append(fg.Fields, ff)
+		}
+		if len(fg.Fields) > 0 {
+			frm.Groups = append(frm.Groups, fg)
+		}
+	}
+	return frm, nil
+}
+
+// formField converts an element.Field into a FormField. The input names
+// follow the Magento convention groups[group][fields][field][value].
+func (h *Handler) formField(f routedField, groupID string, websiteID, storeID int64) (FormField, error) {
+	name := "groups[" + groupID + "][fields][" + f.ID.String() + "]"
+	ff := FormField{
+		ID:         f.ID.String(),
+		Route:      f.route.String(),
+		Name:       name + "[value]",
+		Type:       typeName(f.Type),
+		Label:      f.Label.String(),
+		Comment:    f.Comment.String(),
+		Tooltip:    f.Tooltip.String(),
+		CanBeEmpty: f.CanBeEmpty,
+	}
+	if f.Type != nil {
+		if b := f.Type.ToHTML(); len(b) > 0 {
+			ff.HTML = template.HTML(b)
+		}
+	}
+
+	cur, err := h.resolve(f, websiteID, storeID)
+	if err != nil {
+		return FormField{}, errors.Wrap(err, "[cfgadmin] Handler.formField")
+	}
+	ff.Value = cur.Value
+	ff.own = cur.Found && !cur.Inherited
+
+	if ff.Type == "multiselect" {
+		ff.Name += "[]"
+		if ff.Value != "" {
+			ff.Values = strings.Split(ff.Value, ",")
+		}
+	}
+	for _, pair := range h.sources[ff.Route] {
+		o := Option{
+			Value: optionValue(pair),
+			Label: pair.Label(),
+		}
+		if ff.Type == "multiselect" {
+			o.Selected = containsString(ff.Values, o.Value)
+		} else {
+			o.Selected = o.Value == ff.Value
+		}
+		ff.Options = append(ff.Options, o)
+	}
+
+	// the parent of a store is the website if the field allows the website
+	// scope, otherwise the default scope.
+	var parent Value
+	switch {
+	case storeID > 0 && websiteID > 0 && allowed(f.Scopes, scope.Website):
+		parent, err = h.resolve(f, websiteID, 0)
+		ff.Inherit = &Inherit{Label: "Use Website", Scope: scope.Website.StrType()}
+	case storeID > 0 || websiteID > 0:
+		parent, err = h.resolve(f, 0, 0)
+		ff.Inherit = &Inherit{Label: "Use Default", Scope: scope.Default.StrType()}
+	default:
+		return ff, nil
+	}
+	if err != nil {
+		return FormField{}, errors.Wrap(err, "[cfgadmin] Handler.formField.Inherit")
+	}
+	ff.Inherit.Name = name + "[inherit]"
+	ff.Inherit.Checked = !ff.own
+	ff.Inherit.Value = parent.Value
+	return ff, nil
+}
+
+// optionValue returns the value of an option as stored by the
+// element.ValidatingWriter. Boolean options are stored as 1 and 0.
+func optionValue(pair source.Pair) string {
+	if pair.NotNull != source.NotNullBool {
+		return pair.Value()
+	}
+	if pair.Bool {
+		return "1"
+	}
+	return "0"
+}
+
+// typeName returns the lower case name of a FieldType. Unknown types are
+// rendered as text.
+func typeName(ft element.FieldTyper) string {
+	if ft == nil {
+		return "text"
+	}
+	s := ft.Type().String()
+	if !strings.HasPrefix(s, "Type") {
+		return "text"
+	}
+	return strings.ToLower(s[4:])
+}
+
+func containsString(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// checkOrigin protects the forms against CSRF attacks. The Origin header, or
+// if missing the Referer header, must contain the host of the request or one
+// of the TrustedOrigins. Error behaviour: Unauthorized.
+func (h *Handler) checkOrigin(r *http.Request) error {
+	src := r.Header.Get("Origin")
+	if src == "" || src == "null" {
+		src = r.Header.Get("Referer")
+	}
+	if src == "" {
+		return errors.NewUnauthorizedf("[cfgadmin] Handler.checkOrigin Missing Origin and Referer header")
+	}
+	u, err := url.Parse(src)
+	if err != nil || u.Host == "" {
+		return errors.NewUnauthorizedf("[cfgadmin] Handler.checkOrigin Invalid origin %q", src)
+	}
+	if u.Host == r.Host {
+		return nil
+	}
+	origin := u.Scheme + "://" + u.Host
+	for _, to := range h.TrustedOrigins {
+		if to == origin {
+			return nil
+		}
+	}
+	return errors.NewUnauthorizedf("[cfgadmin] Handler.checkOrigin Origin %q not allowed", origin)
+}
+
+// SubmitForm parses the submitted HTML form of a section and writes all
+// changed values into the scope of the form via the validating writer.
+// Fields which are missing in the form data or whose value has not changed
+// are skipped, same as an obscure field which contains ObscuredValue. A
+// checked inheritance checkbox removes the value of the scope if the
+// ReadWriter implements the Deleter interface. SubmitForm returns the written
+// or removed paths. Validation errors of all fields are returned as an
+// *errors.MultiErr, see element.ValidatingWriter. The request must come from
+// the same origin or from one of the TrustedOrigins, otherwise SubmitForm
+// returns an Unauthorized error.
+func (h *Handler) SubmitForm(r *http.Request, sectionID string, websiteID, storeID int64) (cfgpath.PathSlice, error) {
+	if err := h.checkOrigin(r); err != nil {
+		return nil, errors.Wrap(err, "[cfgadmin] Handler.SubmitForm")
+	}
+	if err := r.ParseForm(); err != nil {
+		return nil, errors.NewNotValidf("[cfgadmin] Handler.SubmitForm Cannot parse the form: %s", err)
+	}
+	frm, err := h.Form(sectionID, websiteID, storeID)
+	if err != nil {
+		return nil, errors.Wrap(err, "[cfgadmin] Handler.SubmitForm")
+	}
+	id := formScope(websiteID, storeID)
+
+	var written cfgpath.PathSlice
+	var mErr *errors.MultiErr
+	for _, g := range frm.Groups {
+		for _, ff := range g.Fields {
+			p, err := cfgpath.NewByParts(ff.Route)
+			if err != nil {
+				return nil, errors.Wrap(err, "[cfgadmin] Handler.SubmitForm.cfgpath.NewByParts")
+			}
+			p = p.Bind(id)
+
+			if ff.Inherit != nil && r.PostForm.Get(ff.Inherit.Name) != "" {
+				if !ff.own {
+					continue
+				}
+				d, ok := h.rw.(Deleter)
+				if !ok {
+					mErr = mErr.AppendErrors(errors.NewNotSupportedf("[cfgadmin] Path %q: Cannot restore the inheritance because the storage cannot delete values", p))
+					continue
+				}
+				if err := d.Delete(p); err != nil {
+					return written, errors.Wrapf(err, "[cfgadmin] Handler.SubmitForm.Delete %q", p)
+				}
+				written = append(written, p)
+				continue
+			}
+
+			vals, ok := r.PostForm[ff.Name]
+			if !ok {
+				continue
+			}
+			var v interface{}
+			var sv string
+			if ff.Type == "multiselect" {
+				mv := make([]string, 0, len(vals))
+				for _, val := range vals {
+					if val != "" {
+						mv = append(mv, val)
+					}
+				}
+				v, sv = mv, strings.Join(mv, ",")
+			} else {
+				v, sv = vals[0], vals[0]
+			}
+			if (ff.own && sv == ff.Value) || (ff.Type == "obscure" && sv == ObscuredValue) {
+				continue
+			}
+			if err := h.vw.Write(p, v); err != nil {
+				mErr = mErr.AppendErrors(err)
+				continue
+			}
+			written = append(written, p)
+		}
+	}
+	if mErr.HasErrors() {
+		return written, mErr
+	}
+	return written, nil
+}
+
+// serveForm prints the form of a section as HTML if the client accepts
+// text/html, otherwise as JSON. A successful POST of an HTML form redirects
+// back to the form.
+func (h *Handler) serveForm(w http.ResponseWriter, r *http.Request, sectionID string) error {
+	websiteID, storeID, err := scopeIDs(r)
+	if err != nil {
+		return errors.Wrap(err, "[cfgadmin] Handler.serveForm")
+	}
+
+	code := http.StatusOK
+	var subErr error
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
+		written, err := h.SubmitForm(r, sectionID, websiteID, storeID)
+		if _, ok := err.(*errors.MultiErr); err != nil && !ok {
+			return errors.Wrap(err, "[cfgadmin] Handler.serveForm")
+		}
+		subErr = err
+		if subErr != nil {
+			code = statusCode(subErr)
+		}
+		if h.Log.IsInfo() && len(written) > 0 {
+			h.Log.Info("cfgadmin.Handler.serveForm", log.String("section", sectionID), log.Int("written", len(written)), log.String("remote_addr", r.RemoteAddr))
+		}
+		if subErr == nil && acceptsHTML(r) {
+			uri := r.RequestURI
+			if uri == "" {
+				uri = r.URL.RequestURI()
+			}
+			http.Redirect(w, r, uri, http.StatusSeeOther)
+			return nil
+		}
+	default:
+		return h.methodNotAllowed(w, r, http.MethodGet+", "+http.MethodPost)
+	}
+
+	frm, err := h.Form(sectionID, websiteID, storeID)
+	if err != nil {
+		return errors.Wrap(err, "[cfgadmin] Handler.serveForm")
+	}
+	if me, ok := subErr.(*errors.MultiErr); ok {
+		for _, e := range me.Errors {
+			frm.Errors = append(frm.Errors, e.Error())
+		}
+	}
+
+	if !acceptsHTML(r) {
+		return h.print(w, r, code, frm)
+	}
+	p := response.NewPrinter(w, r)
+	p.Renderer = h.FormTemplate
+	if err := p.Render(code, "form", frm); err != nil {
+		return errors.Wrap(err, "[cfgadmin] Handler.serveForm.Render")
+	}
+	return nil
+}
+
+// acceptsHTML returns true if the client prefers an HTML response.
+func acceptsHTML(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/html")
+}
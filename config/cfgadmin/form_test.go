@@ -0,0 +1,389 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgadmin_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/corestoreio/csfw/config/cfgadmin"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/config/element"
+	"github.com/corestoreio/csfw/config/source"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ cfgadmin.Deleter = (*mapRW)(nil)
+
+// mapRW a ReadWriter which can delete values.
+type mapRW struct {
+	mu   sync.Mutex
+	data map[string]interface{}
+}
+
+func (m *mapRW) Write(p cfgpath.Path, v interface{}) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data[p.String()] = v
+	return nil
+}
+
+func (m *mapRW) String(p cfgpath.Path) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v, ok := m.data[p.String()]
+	if !ok {
+		return "", errors.NewNotFoundf("Path %q not found", p)
+	}
+	return v.(string), nil
+}
+
+func (m *mapRW) Delete(p cfgpath.Path) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.data, p.String())
+	return nil
+}
+
+func submit(h http.Handler, target string, form url.Values, accept string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", target, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", accept)
+	req.Header.Set("Origin", "http://example.com")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandlerFormJSON(t *testing.T) {
+	h, srv := newHandler(t)
+	p := cfgpath.MustNewByParts("currency/options/allow")
+	assert.NoError(t, srv.Write(p.BindWebsite(1), "EUR,USD"))
+	assert.NoError(t, srv.Write(cfgpath.MustNewByParts("currency/options/precision").BindStore(2), "3"))
+
+	rec := serve(h, "GET", "/forms/currency?website=1&store=2", "")
+	assert.Exactly(t, http.StatusOK, rec.Code, rec.Body.String())
+	var frm cfgadmin.Form
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &frm))
+
+	assert.Exactly(t, "Currency Setup", frm.Label)
+	assert.Exactly(t, "stores", frm.Scope)
+	assert.Exactly(t, int64(2), frm.ScopeID)
+	assert.Len(t, frm.Groups, 1)
+	fields := frm.Groups[0].Fields
+	assert.Len(t, fields, 2, "Field base is not allowed in the store scope")
+
+	assert.Exactly(t, cfgadmin.FormField{
+		ID:     "allow",
+		Route:  "currency/options/allow",
+		Name:   "groups[options][fields][allow][value][]",
+		Type:   "multiselect",
+		Value:  "EUR,USD",
+		Values: []string{"EUR", "USD"},
+		Options: []cfgadmin.Option{
+			{Value: "CHF", Label: "CHF"},
+			{Value: "EUR", Label: "EUR", Selected: true},
+			{Value: "USD", Label: "USD", Selected: true},
+		},
+		Inherit: &cfgadmin.Inherit{
+			Name:    "groups[options][fields][allow][inherit]",
+			Label:   "Use Website",
+			Scope:   "websites",
+			Checked: true,
+			Value:   "EUR,USD",
+		},
+	}, fields[0])
+
+	assert.Exactly(t, "text", fields[1].Type)
+	assert.Exactly(t, "3", fields[1].Value)
+	assert.Exactly(t, &cfgadmin.Inherit{
+		Name:  "groups[options][fields][precision][inherit]",
+		Label: "Use Website",
+		Scope: "websites",
+		Value: "2",
+	}, fields[1].Inherit)
+
+	// default scope contains all fields but no inheritance
+	rec = serve(h, "GET", "/forms/currency", "")
+	assert.Exactly(t, http.StatusOK, rec.Code)
+	frm = cfgadmin.Form{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &frm))
+	assert.Len(t, frm.Groups[0].Fields, 3)
+	for _, f := range frm.Groups[0].Fields {
+		assert.Nil(t, f.Inherit, f.ID)
+	}
+	assert.Exactly(t, "USD", frm.Groups[0].Fields[0].Value)
+	assert.True(t, frm.Groups[0].Fields[0].Options[2].Selected)
+
+	for _, test := range []struct {
+		method, target string
+		wantCode       int
+	}{
+		{"GET", "/forms/catalog", http.StatusNotFound},
+		{"GET", "/forms/currency?store=x", http.StatusBadRequest},
+		{"PUT", "/forms/currency", http.StatusMethodNotAllowed},
+	} {
+		rec = serve(h, test.method, test.target, "")
+		assert.Exactly(t, test.wantCode, rec.Code, test.target)
+	}
+}
+
+func TestHandlerFormHTML(t *testing.T) {
+	h, srv := newHandler(t)
+	assert.NoError(t, srv.Write(cfgpath.MustNewByParts("currency/options/base").BindWebsite(1), "EUR"))
+
+	req := httptest.NewRequest("GET", "/forms/currency?website=1", nil)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	assert.Exactly(t, http.StatusOK, rec.Code)
+	assert.Exactly(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+	body := rec.Body.String()
+	for _, want := range []string{
+		`<h1>Currency Setup</h1>`,
+		`<select id="currency/options/base" name="groups[options][fields][base][value]"><option value="CHF">CHF</option><option value="EUR" selected>EUR</option>`,
+		`<input type="checkbox" id="groups[options][fields][base][inherit]" name="groups[options][fields][base][inherit]" value="1"> <label for="groups[options][fields][base][inherit]">Use Default</label>`,
+		`<select id="currency/options/allow" name="groups[options][fields][allow][value][]" multiple disabled>`,
+		`<input type="text" id="currency/options/precision" name="groups[options][fields][precision][value]" value="2" disabled>`,
+	} {
+		assert.Contains(t, body, want)
+	}
+}
+
+func TestHandlerFormSubmit(t *testing.T) {
+	h, srv := newHandler(t)
+
+	rec := submit(h, "/forms/currency?website=1", url.Values{
+		"groups[options][fields][base][value]":        {"EUR"},
+		"groups[options][fields][allow][value][]":     {"CHF", "EUR"},
+		"groups[options][fields][precision][inherit]": {"1"},
+	}, "text/html")
+	assert.Exactly(t, http.StatusSeeOther, rec.Code, rec.Body.String())
+	assert.Exactly(t, "/forms/currency?website=1", rec.Header().Get("Location"))
+
+	p := cfgpath.MustNewByParts("currency/options/allow").BindWebsite(1)
+	v, err := srv.String(p)
+	assert.NoError(t, err)
+	assert.Exactly(t, "CHF,EUR", v)
+	_, err = srv.String(cfgpath.MustNewByParts("currency/options/precision").BindWebsite(1))
+	assert.True(t, errors.IsNotFound(err), "%+v", err)
+
+	// invalid values return the form with all errors
+	rec = submit(h, "/forms/currency?website=1", url.Values{
+		"groups[options][fields][base][value]":    {"JPY"},
+		"groups[options][fields][allow][value][]": {"CHF", "GBP"},
+	}, "application/json")
+	assert.Exactly(t, http.StatusBadRequest, rec.Code)
+	var frm cfgadmin.Form
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &frm))
+	assert.Len(t, frm.Errors, 2)
+	assert.Contains(t, frm.Errors[0], `The value "JPY" cannot be found`)
+	assert.Exactly(t, "EUR", frm.Groups[0].Fields[0].Value, "Old value must be kept")
+
+	// restoring the inheritance needs a Deleter
+	rec = submit(h, "/forms/currency?website=1", url.Values{
+		"groups[options][fields][allow][inherit]": {"1"},
+	}, "application/json")
+	assert.Exactly(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "Cannot restore the inheritance")
+}
+
+func TestHandlerSubmitFormDeleter(t *testing.T) {
+	ss := element.MustNewConfiguration(
+		element.Section{
+			ID: cfgpath.NewRoute("general"),
+			Groups: element.NewGroupSlice(
+				element.Group{
+					ID: cfgpath.NewRoute("locale"),
+					Fields: element.NewFieldSlice(
+						element.Field{
+							ID:     cfgpath.NewRoute("code"),
+							Type:   element.TypeSelect,
+							Scopes: scope.PermStore,
+						},
+						element.Field{
+							ID:         cfgpath.NewRoute("allowed"),
+							Type:       element.TypeMultiselect,
+							Scopes:     scope.PermStore,
+							CanBeEmpty: true,
+						},
+					),
+				},
+			),
+		},
+	)
+	rw := &mapRW{data: map[string]interface{}{
+		"stores/3/general/locale/code":    "de_CH",
+		"stores/3/general/locale/allowed": "de_CH",
+	}}
+	h, err := cfgadmin.NewHandler(rw, ss, noAuth)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	assert.NoError(t, h.SetSource(cfgpath.NewRoute("general/locale/code"), source.NewByStringValue("de_CH", "fr_CH")))
+
+	req := httptest.NewRequest("POST", "/forms/general?store=3", strings.NewReader(url.Values{
+		"groups[locale][fields][code][inherit]":    {"1"},
+		"groups[locale][fields][code][value]":      {"fr_CH"},
+		"groups[locale][fields][allowed][value][]": {""},
+	}.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Referer", "https://example.com/forms/general?store=3")
+	written, err := h.SubmitForm(req, "general", 0, 3)
+	assert.NoError(t, err, "%+v", err)
+	assert.Exactly(t, "stores/3/general/locale/code", written[0].String())
+	assert.Exactly(t, "stores/3/general/locale/allowed", written[1].String())
+	assert.Exactly(t, map[string]interface{}{
+		"stores/3/general/locale/allowed": "",
+	}, rw.data)
+}
+
+func TestHandlerFormYesNo(t *testing.T) {
+	ss := element.MustNewConfiguration(
+		element.Section{
+			ID: cfgpath.NewRoute("general"),
+			Groups: element.NewGroupSlice(
+				element.Group{
+					ID: cfgpath.NewRoute("single_store_mode"),
+					Fields: element.NewFieldSlice(
+						element.Field{
+							ID:     cfgpath.NewRoute("enabled"),
+							Type:   element.TypeSelect,
+							Scopes: scope.PermDefault,
+						},
+					),
+				},
+			),
+		},
+	)
+	rw := &mapRW{data: map[string]interface{}{}}
+	h, err := cfgadmin.NewHandler(rw, ss, noAuth)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	assert.NoError(t, h.SetSource(cfgpath.NewRoute("general/single_store_mode/enabled"), source.YesNo))
+
+	rec := submit(h, "/forms/general", url.Values{
+		"groups[single_store_mode][fields][enabled][value]": {"1"},
+	}, "application/json")
+	assert.Exactly(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Exactly(t, "1", rw.data["default/0/general/single_store_mode/enabled"])
+
+	var frm cfgadmin.Form
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &frm))
+	assert.Exactly(t, []cfgadmin.Option{
+		{Value: "0", Label: "No"},
+		{Value: "1", Label: "Yes", Selected: true},
+	}, frm.Groups[0].Fields[0].Options)
+}
+
+func TestHandlerFormObscure(t *testing.T) {
+	ss := element.MustNewConfiguration(
+		element.Section{
+			ID: cfgpath.NewRoute("payment"),
+			Groups: element.NewGroupSlice(
+				element.Group{
+					ID: cfgpath.NewRoute("gateway"),
+					Fields: element.NewFieldSlice(
+						element.Field{
+							ID:     cfgpath.NewRoute("password"),
+							Type:   element.TypeObscure,
+							Scopes: scope.PermWebsite,
+						},
+					),
+				},
+			),
+		},
+	)
+	p := cfgpath.MustNewByParts("payment/gateway/password")
+	rw := &mapRW{data: map[string]interface{}{
+		p.String(): "s3cr3t",
+	}}
+	h, err := cfgadmin.NewHandler(rw, ss, noAuth)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/forms/payment?website=1", nil)
+	req.Header.Set("Accept", "text/html")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Exactly(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "s3cr3t")
+	assert.Contains(t, rec.Body.String(), `<input type="password" id="payment/gateway/password" name="groups[gateway][fields][password][value]" value="******" disabled>`)
+
+	// the unchanged placeholder must not overwrite the password
+	for _, form := range []url.Values{
+		{"groups[gateway][fields][password][value]": {cfgadmin.ObscuredValue}},
+		{"groups[gateway][fields][password][value]": {cfgadmin.ObscuredValue}, "groups[gateway][fields][password][inherit]": {""}},
+	} {
+		rec = submit(h, "/forms/payment?website=1", form, "application/json")
+		assert.Exactly(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.NotContains(t, rec.Body.String(), "s3cr3t")
+		assert.Len(t, rw.data, 1)
+	}
+
+	rec = submit(h, "/forms/payment?website=1", url.Values{
+		"groups[gateway][fields][password][value]": {"n3w"},
+	}, "application/json")
+	assert.Exactly(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Exactly(t, "n3w", rw.data[p.BindWebsite(1).String()])
+}
+
+func TestHandlerFormCSRF(t *testing.T) {
+	h, srv := newHandler(t)
+	h.TrustedOrigins = []string{"https://admin.example.org"}
+	form := url.Values{"groups[options][fields][base][value]": {"EUR"}}
+	p := cfgpath.MustNewByParts("currency/options/base").BindWebsite(1)
+
+	tests := []struct {
+		header, value string
+		wantCode      int
+	}{
+		{"", "", http.StatusForbidden},
+		{"Origin", "null", http.StatusForbidden},
+		{"Origin", "http://evil.example.org", http.StatusForbidden},
+		{"Referer", "http://evil.example.org/forms/currency", http.StatusForbidden},
+		{"Origin", "http://admin.example.org", http.StatusForbidden},
+		{"Origin", "https://admin.example.org", http.StatusOK},
+		{"Referer", "http://example.com/forms/currency?website=1", http.StatusOK},
+	}
+	for i, test := range tests {
+		assert.NoError(t, srv.Write(p, "USD"))
+		req := httptest.NewRequest("POST", "/forms/currency?website=1", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if test.header != "" {
+			req.Header.Set(test.header, test.value)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		assert.Exactly(t, test.wantCode, rec.Code, "Index %d: %s", i, rec.Body.String())
+		v, err := srv.String(p)
+		assert.NoError(t, err)
+		if test.wantCode == http.StatusOK {
+			assert.Exactly(t, "EUR", v, "Index %d", i)
+		} else {
+			assert.Exactly(t, "USD", v, "Index %d", i)
+		}
+	}
+}
@@ -0,0 +1,456 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgadmin
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/config/element"
+	"github.com/corestoreio/csfw/config/source"
+	"github.com/corestoreio/csfw/log"
+	"github.com/corestoreio/csfw/net/mw"
+	"github.com/corestoreio/csfw/net/response"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/conv"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// maxBodySize limits the size of a PUT request body.
+const maxBodySize = 1 << 20
+
+// ObscuredValue replaces the stored value of a TypeObscure field in all
+// responses, so passwords and API secrets never leave the server. Writing
+// ObscuredValue back keeps the stored value.
+const ObscuredValue = "******"
+
+// ReadWriter reads the raw value of a path in exactly one scope and writes
+// values. Implemented by the config.Service.
+type ReadWriter interface {
+	config.Writer
+	String(cfgpath.Path) (string, error)
+}
+
+// Value contains the effective value of a path for a website and store and
+// describes where the value has been found.
+type Value struct {
+	Route string `json:"route"`
+	Value string `json:"value"`
+	// Found is false if no scope contains a value and the Field has no
+	// default value.
+	Found bool `json:"found"`
+	// Scope in which the value has been found: default, websites or stores.
+	// Empty if the value is the default of the Field.
+	Scope   string `json:"scope,omitempty"`
+	ScopeID int64  `json:"scope_id"`
+	// Inherited is true if the value has not been found in the requested
+	// scope but in a parent scope or in the Field default.
+	Inherited bool `json:"inherited"`
+	// FieldDefault is true if the value is the default of the element.Field.
+	FieldDefault bool `json:"field_default,omitempty"`
+}
+
+// errorResponse gets printed as JSON for all failed requests.
+type errorResponse struct {
+	Error  string   `json:"error"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+// Handler implements http.Handler and serves the REST endpoints described in
+// the package documentation. Safe for concurrent use after all sources have
+// been set.
+type Handler struct {
+	// Log default log.BlackHole{}
+	Log log.Logger
+	// FormTemplate renders a Form with the template name "form". Default
+	// template creates a plain HTML form without any styling.
+	FormTemplate *template.Template
+	// TrustedOrigins contains additional origins like
+	// https://admin.example.com which are allowed to submit forms. By default
+	// only the host of the request itself is allowed, see SubmitForm.
+	TrustedOrigins []string
+	sections     element.SectionSlice
+	sources      map[string]source.Slice
+	rw           ReadWriter
+	vw           *element.ValidatingWriter
+	// protected wraps serveHTTP with the authentication middleware.
+	protected http.Handler
+}
+
+// NewHandler creates a new Handler for the sections. All writes get validated
+// against the Field definitions of the sections before they are getting
+// written to the ReadWriter. Every request must pass the authentication
+// middleware, for example net/auth.Service.WithAuthentication or
+// net/jwt.Service.WithToken, because the Handler can read and write payment
+// credentials. Error behaviour: NotValid.
+func NewHandler(rw ReadWriter, ss element.SectionSlice, authentication mw.Middleware) (*Handler, error) {
+	if authentication == nil {
+		return nil, errors.NewNotValidf("[cfgadmin] NewHandler requires an authentication middleware")
+	}
+	vw, err := element.NewValidatingWriter(rw, ss)
+	if err != nil {
+		return nil, errors.Wrap(err, "[cfgadmin] NewHandler.NewValidatingWriter")
+	}
+	h := &Handler{
+		Log:          log.BlackHole{},
+		FormTemplate: formTemplate,
+		sections:     ss,
+		sources:      make(map[string]source.Slice),
+		rw:           rw,
+		vw:           vw,
+	}
+	h.protected = authentication(http.HandlerFunc(h.serveHTTP))
+	return h, nil
+}
+
+// SetSource registers the allowed options of a select or multiselect field.
+// The options are getting rendered in the forms. Not thread safe. Error
+// behaviour: NotFound.
+func (h *Handler) SetSource(r cfgpath.Route, vl source.Slice) error {
+	if err := h.vw.SetSource(r, vl); err != nil {
+		return errors.Wrap(err, "[cfgadmin] Handler.SetSource")
+	}
+	h.sources[r.String()] = vl
+	return nil
+}
+
+// ServeHTTP implements the http.Handler interface. The request gets first
+// authenticated by the middleware passed to NewHandler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.protected.ServeHTTP(w, r)
+}
+
+func (h *Handler) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	var err error
+	switch {
+	case parts[0] == "sections" && len(parts) <= 4:
+		err = h.serveSections(w, r, parts[1:])
+	case parts[0] == "values" && len(parts) >= 2 && len(parts) <= 4:
+		err = h.serveValues(w, r, parts[1:])
+	case parts[0] == "forms" && len(parts) == 2:
+		err = h.serveForm(w, r, parts[1])
+	default:
+		err = errors.NewNotFoundf("[cfgadmin] Unknown resource %q", r.URL.Path)
+	}
+	if err != nil {
+		h.printError(w, r, err)
+	}
+}
+
+func (h *Handler) serveSections(w http.ResponseWriter, r *http.Request, ids []string) error {
+	if r.Method != http.MethodGet {
+		return h.methodNotAllowed(w, r, http.MethodGet)
+	}
+	if len(ids) == 0 {
+		return h.print(w, r, http.StatusOK, h.sections)
+	}
+	sec, _, err := h.sections.Find(cfgpath.NewRoute(ids[0]))
+	if err != nil || len(ids) == 1 {
+		return h.print(w, r, http.StatusOK, sec, err)
+	}
+	g, _, err := sec.Groups.Find(cfgpath.NewRoute(ids[1]))
+	if err != nil || len(ids) == 2 {
+		return h.print(w, r, http.StatusOK, g, err)
+	}
+	f, _, err := g.Fields.Find(cfgpath.NewRoute(ids[2]))
+	return h.print(w, r, http.StatusOK, f, err)
+}
+
+func (h *Handler) serveValues(w http.ResponseWriter, r *http.Request, ids []string) error {
+	websiteID, storeID, err := scopeIDs(r)
+	if err != nil {
+		return errors.Wrap(err, "[cfgadmin] Handler.serveValues")
+	}
+
+	switch {
+	case r.Method == http.MethodGet:
+	case r.Method == http.MethodPut && len(ids) == 3:
+		if err := h.write(w, r, ids, websiteID, storeID); err != nil {
+			return err // might be an *errors.MultiErr, see statusCode.
+		}
+	case len(ids) == 3:
+		return h.methodNotAllowed(w, r, http.MethodGet+", "+http.MethodPut)
+	default:
+		return h.methodNotAllowed(w, r, http.MethodGet)
+	}
+
+	fields, err := h.findFields(ids)
+	if err != nil {
+		return errors.Wrap(err, "[cfgadmin] Handler.serveValues")
+	}
+	vals := make([]Value, 0, len(fields))
+	for _, f := range fields {
+		v, err := h.resolve(f, websiteID, storeID)
+		if err != nil {
+			return errors.Wrap(err, "[cfgadmin] Handler.serveValues")
+		}
+		vals = append(vals, v)
+	}
+	if len(ids) == 3 {
+		return h.print(w, r, http.StatusOK, vals[0])
+	}
+	return h.print(w, r, http.StatusOK, vals)
+}
+
+// write decodes the body and writes the value into the most specific scope of
+// the request.
+func (h *Handler) write(w http.ResponseWriter, r *http.Request, ids []string, websiteID, storeID int64) error {
+	fields, err := h.findFields(ids)
+	if err != nil {
+		return errors.Wrap(err, "[cfgadmin] Handler.write")
+	}
+
+	var body struct {
+		Value interface{} `json:"value"`
+	}
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	dec.UseNumber()
+	if err := dec.Decode(&body); err != nil {
+		return errors.NewNotValidf("[cfgadmin] Handler.write Cannot decode the body: %s", err)
+	}
+	if isObscure(fields[0].Field) && body.Value == ObscuredValue {
+		return nil // the client sent back the masked value.
+	}
+
+	id := scope.DefaultTypeID
+	switch {
+	case storeID > 0:
+		id = scope.Store.Pack(storeID)
+	case websiteID > 0:
+		id = scope.Website.Pack(websiteID)
+	}
+	p, err := cfgpath.New(fields[0].route)
+	if err != nil {
+		return errors.Wrap(err, "[cfgadmin] Handler.write.cfgpath.New")
+	}
+	p = p.Bind(id)
+
+	// the returned *errors.MultiErr must not be wrapped, see statusCode.
+	if err := h.vw.Write(p, fromJSON(body.Value)); err != nil {
+		return err
+	}
+	if h.Log.IsInfo() {
+		h.Log.Info("cfgadmin.Handler.write", log.Stringer("path", p), log.String("remote_addr", r.RemoteAddr))
+	}
+	return nil
+}
+
+// fromJSON converts the decoded JSON numbers and arrays into types which are
+// supported by the element.ValidatingWriter.
+func fromJSON(v interface{}) interface{} {
+	switch vt := v.(type) {
+	case json.Number:
+		return vt.String()
+	case []interface{}:
+		ss := make([]string, len(vt))
+		for i, iv := range vt {
+			ss[i] = fmt.Sprintf("%v", iv)
+		}
+		return ss
+	}
+	return v
+}
+
+// routedField a Field with its storage route.
+type routedField struct {
+	route cfgpath.Route
+	element.Field
+}
+
+// findFields returns all fields below the section, group and field IDs.
+// Error behaviour: NotFound.
+func (h *Handler) findFields(ids []string) ([]routedField, error) {
+	var rfs []routedField
+	for _, s := range h.sections {
+		if s.ID.String() != ids[0] {
+			continue
+		}
+		for _, g := range s.Groups {
+			if len(ids) > 1 && g.ID.String() != ids[1] {
+				continue
+			}
+			for _, f := range g.Fields {
+				if len(ids) > 2 && f.ID.String() != ids[2] {
+					continue
+				}
+				r, err := f.Route(s.ID, g.ID)
+				if err != nil {
+					return nil, errors.Wrapf(err, "[cfgadmin] Section %q Group %q", s.ID, g.ID)
+				}
+				rfs = append(rfs, routedField{route: r, Field: f})
+			}
+		}
+	}
+	if len(rfs) == 0 {
+		return nil, errors.NewNotFoundf("[cfgadmin] Fields for %q not found", strings.Join(ids, "/"))
+	}
+	return rfs, nil
+}
+
+// resolve searches the value in the scopes store, website and default as far
+// as the Field allows them. The Field default gets used if no scope contains
+// a value.
+func (h *Handler) resolve(f routedField, websiteID, storeID int64) (Value, error) {
+	perm := f.Scopes
+	if perm == 0 {
+		perm = scope.PermDefault
+	}
+	requested := scope.DefaultTypeID
+	ids := make(scope.TypeIDs, 0, 3)
+	if storeID > 0 {
+		requested = scope.Store.Pack(storeID)
+		if perm.Has(scope.Store) {
+			ids = append(ids, requested)
+		}
+	}
+	if websiteID > 0 {
+		if requested == scope.DefaultTypeID {
+			requested = scope.Website.Pack(websiteID)
+		}
+		if perm.Has(scope.Website) {
+			ids = append(ids, scope.Website.Pack(websiteID))
+		}
+	}
+	ids = append(ids, scope.DefaultTypeID)
+
+	p, err := cfgpath.New(f.route)
+	if err != nil {
+		return Value{}, errors.Wrap(err, "[cfgadmin] Handler.resolve.cfgpath.New")
+	}
+	ret := Value{
+		Route: f.route.String(),
+	}
+	for _, id := range ids {
+		v, err := h.rw.String(p.Bind(id))
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return Value{}, errors.Wrapf(err, "[cfgadmin] Handler.resolve Path %q", p.Bind(id))
+		}
+		ret.Value = mask(f.Field, v)
+		ret.Found = true
+		ret.Scope = id.Type().StrType()
+		ret.ScopeID = id.ID()
+		ret.Inherited = id != requested
+		return ret, nil
+	}
+
+	if f.Default != nil {
+		v, err := conv.ToStringE(f.Default)
+		if err != nil {
+			v = fmt.Sprintf("%v", f.Default)
+		}
+		ret.Value = mask(f.Field, v)
+		ret.Found = true
+		ret.Inherited = true
+		ret.FieldDefault = true
+	}
+	return ret, nil
+}
+
+func isObscure(f element.Field) bool {
+	return f.Type != nil && f.Type.Type() == element.TypeObscure
+}
+
+// mask replaces a non-empty value of a TypeObscure field with ObscuredValue.
+func mask(f element.Field, v string) string {
+	if v != "" && isObscure(f) {
+		return ObscuredValue
+	}
+	return v
+}
+
+// scopeIDs extracts the query parameters website and store. Error behaviour:
+// NotValid.
+func scopeIDs(r *http.Request) (websiteID, storeID int64, err error) {
+	q := r.URL.Query()
+	for _, sid := range []struct {
+		key string
+		id  *int64
+	}{{"website", &websiteID}, {"store", &storeID}} {
+		raw := q.Get(sid.key)
+		if raw == "" {
+			continue
+		}
+		*sid.id, err = strconv.ParseInt(raw, 10, 64)
+		if err != nil || *sid.id < 0 || *sid.id > scope.MaxID {
+			return 0, 0, errors.NewNotValidf("[cfgadmin] Invalid %s ID %q", sid.key, raw)
+		}
+	}
+	return websiteID, storeID, nil
+}
+
+// print writes data as JSON or the first non-nil error.
+func (h *Handler) print(w http.ResponseWriter, r *http.Request, code int, data interface{}, errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	if err := response.NewPrinter(w, r).JSON(code, data); err != nil && h.Log.IsDebug() {
+		h.Log.Debug("cfgadmin.Handler.print", log.Err(err), log.HTTPRequest("request", r))
+	}
+	return nil
+}
+
+func (h *Handler) methodNotAllowed(w http.ResponseWriter, r *http.Request, allow string) error {
+	w.Header().Set("Allow", allow)
+	return h.print(w, r, http.StatusMethodNotAllowed, errorResponse{
+		Error: fmt.Sprintf("Method %s not allowed", r.Method),
+	})
+}
+
+func (h *Handler) printError(w http.ResponseWriter, r *http.Request, err error) {
+	code := statusCode(err)
+	if h.Log.IsDebug() {
+		h.Log.Debug("cfgadmin.Handler.ServeHTTP", log.Err(err), log.Int("status", code), log.HTTPRequest("request", r))
+	}
+	er := errorResponse{
+		Error: http.StatusText(code),
+	}
+	if me, ok := err.(*errors.MultiErr); ok {
+		for _, e := range me.Errors {
+			er.Errors = append(er.Errors, e.Error())
+		}
+	} else if code < http.StatusInternalServerError {
+		er.Errors = []string{err.Error()}
+	}
+	_ = h.print(w, r, code, er)
+}
+
+// statusCode maps the behaviour of an error to a HTTP status code.
+func statusCode(err error) int {
+	is := func(bf errors.BehaviourFunc) bool {
+		return bf(err) || errors.MultiErrContainsAny(err, bf)
+	}
+	switch {
+	case is(errors.IsNotFound):
+		return http.StatusNotFound
+	case is(errors.IsUnauthorized):
+		return http.StatusForbidden
+	case is(errors.IsNotValid), is(errors.IsNotSupported), is(errors.IsEmpty):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
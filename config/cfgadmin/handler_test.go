@@ -0,0 +1,255 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgadmin_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/cfgadmin"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/config/element"
+	"github.com/corestoreio/csfw/config/source"
+	"github.com/corestoreio/csfw/net/mw"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ http.Handler = (*cfgadmin.Handler)(nil)
+var _ cfgadmin.ReadWriter = (*config.Service)(nil)
+
+func newHandler(t *testing.T) (*cfgadmin.Handler, *config.Service) {
+	return newAuthHandler(t, noAuth)
+}
+
+func newAuthHandler(t *testing.T, authentication mw.Middleware) (*cfgadmin.Handler, *config.Service) {
+	ss := element.MustNewConfiguration(
+		element.Section{
+			ID:    cfgpath.NewRoute("currency"),
+			Label: []byte("Currency Setup"),
+			Groups: element.NewGroupSlice(
+				element.Group{
+					ID: cfgpath.NewRoute("options"),
+					Fields: element.NewFieldSlice(
+						element.Field{
+							// Path: `currency/options/base`,
+							ID:      cfgpath.NewRoute("base"),
+							Type:    element.TypeSelect,
+							Scopes:  scope.PermWebsite,
+							Default: "USD",
+						},
+						element.Field{
+							// Path: `currency/options/allow`,
+							ID:     cfgpath.NewRoute("allow"),
+							Type:   element.TypeMultiselect,
+							Scopes: scope.PermStore,
+						},
+						element.Field{
+							// Path: `currency/options/precision`,
+							ID:      cfgpath.NewRoute("precision"),
+							Type:    element.TypeText,
+							Scopes:  scope.PermStore,
+							Default: 2,
+						},
+					),
+				},
+			),
+		},
+	)
+	srv := config.MustNewService(config.NewInMemoryStore())
+	h, err := cfgadmin.NewHandler(srv, ss, authentication)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	currencies := source.NewByStringValue("CHF", "EUR", "USD")
+	assert.NoError(t, h.SetSource(cfgpath.NewRoute("currency/options/base"), currencies))
+	assert.NoError(t, h.SetSource(cfgpath.NewRoute("currency/options/allow"), currencies))
+	return h, srv
+}
+
+// noAuth lets all requests pass. Only used for testing.
+func noAuth(next http.Handler) http.Handler { return next }
+
+func serve(h http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	var req *http.Request
+	if body != "" {
+		req = httptest.NewRequest(method, target, strings.NewReader(body))
+	} else {
+		req = httptest.NewRequest(method, target, nil)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandlerSections(t *testing.T) {
+	h, _ := newHandler(t)
+
+	tests := []struct {
+		target   string
+		wantCode int
+		contains string
+	}{
+		{"/sections", http.StatusOK, `"Label":"Currency Setup"`},
+		{"/sections/currency", http.StatusOK, `"ID":"options"`},
+		{"/sections/currency/options", http.StatusOK, `"ID":"precision"`},
+		{"/sections/currency/options/base", http.StatusOK, `"Default":"USD"`},
+		{"/sections/catalog", http.StatusNotFound, `[element] Section \"catalog\"`},
+		{"/sections/currency/options/xxx", http.StatusNotFound, `"error":"Not Found"`},
+		{"/unknown", http.StatusNotFound, `Unknown resource \"/unknown\"`},
+	}
+	for i, test := range tests {
+		rec := serve(h, "GET", test.target, "")
+		assert.Exactly(t, test.wantCode, rec.Code, "Index %d", i)
+		assert.Contains(t, rec.Body.String(), test.contains, "Index %d", i)
+		assert.Exactly(t, "application/json; charset=utf-8", rec.Header().Get("Content-Type"), "Index %d", i)
+	}
+
+	rec := serve(h, "PUT", "/sections/currency", `{}`)
+	assert.Exactly(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Exactly(t, "GET", rec.Header().Get("Allow"))
+}
+
+func TestHandlerValues(t *testing.T) {
+	h, srv := newHandler(t)
+	p := cfgpath.MustNewByParts("currency/options/allow")
+	assert.NoError(t, srv.Write(p.BindWebsite(1), "EUR,USD"))
+	assert.NoError(t, srv.Write(p.BindStore(2), "CHF"))
+	assert.NoError(t, srv.Write(cfgpath.MustNewByParts("currency/options/base").BindWebsite(1), "EUR"))
+
+	rec := serve(h, "GET", "/values/currency?website=1&store=2", "")
+	assert.Exactly(t, http.StatusOK, rec.Code)
+	var vals []cfgadmin.Value
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &vals))
+	assert.Exactly(t, []cfgadmin.Value{
+		{Route: "currency/options/base", Value: "EUR", Found: true, Scope: "websites", ScopeID: 1, Inherited: true},
+		{Route: "currency/options/allow", Value: "CHF", Found: true, Scope: "stores", ScopeID: 2},
+		{Route: "currency/options/precision", Value: "2", Found: true, Inherited: true, FieldDefault: true},
+	}, vals)
+
+	rec = serve(h, "GET", "/values/currency/options/allow?website=1&store=3", "")
+	assert.Exactly(t, http.StatusOK, rec.Code)
+	assert.Exactly(t, `{"route":"currency/options/allow","value":"EUR,USD","found":true,"scope":"websites","scope_id":1,"inherited":true}`+"\n", rec.Body.String())
+
+	rec = serve(h, "GET", "/values/currency/options/allow", "")
+	assert.Exactly(t, `{"route":"currency/options/allow","value":"","found":false,"scope_id":0,"inherited":false}`+"\n", rec.Body.String())
+
+	rec = serve(h, "GET", "/values/currency/options/allow?store=x", "")
+	assert.Exactly(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `Invalid store ID \"x\"`)
+
+	rec = serve(h, "GET", "/values/catalog", "")
+	assert.Exactly(t, http.StatusNotFound, rec.Code)
+}
+
+func TestHandlerWrite(t *testing.T) {
+	h, srv := newHandler(t)
+
+	tests := []struct {
+		target   string
+		body     string
+		wantCode int
+		contains string
+	}{
+		{"/values/currency/options/allow?website=1&store=2", `{"value":["EUR","CHF"]}`, http.StatusOK,
+			`{"route":"currency/options/allow","value":"EUR,CHF","found":true,"scope":"stores","scope_id":2,"inherited":false}`},
+		{"/values/currency/options/precision?website=1", `{"value":4}`, http.StatusOK,
+			`"value":"4","found":true,"scope":"websites","scope_id":1`},
+		{"/values/currency/options/base?website=1&store=2", `{"value":"CHF"}`, http.StatusForbidden,
+			`Scope \"Store\" not allowed`},
+		{"/values/currency/options/base", `{"value":"JPY"}`, http.StatusBadRequest,
+			`The value \"JPY\" cannot be found within the allowed options`},
+		{"/values/currency/options/base", `{"value":`, http.StatusBadRequest,
+			`Cannot decode the body`},
+		{"/values/currency/options/xxx", `{"value":"CHF"}`, http.StatusNotFound,
+			`Fields for \"currency/options/xxx\" not found`},
+	}
+	for i, test := range tests {
+		rec := serve(h, "PUT", test.target, test.body)
+		assert.Exactly(t, test.wantCode, rec.Code, "Index %d", i)
+		assert.Contains(t, rec.Body.String(), test.contains, "Index %d", i)
+	}
+
+	v, err := srv.String(cfgpath.MustNewByParts("currency/options/allow").BindStore(2))
+	assert.NoError(t, err)
+	assert.Exactly(t, "EUR,CHF", v)
+	_, err = srv.String(cfgpath.MustNewByParts("currency/options/base"))
+	assert.Error(t, err, "Invalid values must not be written")
+
+	rec := serve(h, "POST", "/values/currency/options/base", `{"value":"CHF"}`)
+	assert.Exactly(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Exactly(t, "GET, PUT", rec.Header().Get("Allow"))
+	rec = serve(h, "PUT", "/values/currency/options", `{"value":"CHF"}`)
+	assert.Exactly(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestHandlerValuesObscure(t *testing.T) {
+	ss := element.MustNewConfiguration(
+		element.Section{
+			ID: cfgpath.NewRoute("payment"),
+			Groups: element.NewGroupSlice(
+				element.Group{
+					ID: cfgpath.NewRoute("gateway"),
+					Fields: element.NewFieldSlice(
+						element.Field{
+							// Path: `payment/gateway/password`,
+							ID:     cfgpath.NewRoute("password"),
+							Type:   element.TypeObscure,
+							Scopes: scope.PermWebsite,
+						},
+						element.Field{
+							// Path: `payment/gateway/login`,
+							ID:   cfgpath.NewRoute("login"),
+							Type: element.TypeText,
+						},
+					),
+				},
+			),
+		},
+	)
+	srv := config.MustNewService(config.NewInMemoryStore())
+	h, err := cfgadmin.NewHandler(srv, ss, noAuth)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	pw := cfgpath.MustNewByParts("payment/gateway/password")
+	assert.NoError(t, srv.Write(pw, "s3cr3t"))
+	assert.NoError(t, srv.Write(cfgpath.MustNewByParts("payment/gateway/login"), "shop"))
+
+	rec := serve(h, "GET", "/values/payment?website=1", "")
+	assert.Exactly(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "s3cr3t")
+	var vals []cfgadmin.Value
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &vals))
+	assert.Exactly(t, cfgadmin.ObscuredValue, vals[0].Value)
+	assert.Exactly(t, "shop", vals[1].Value)
+
+	// the masked value does not overwrite the stored password
+	rec = serve(h, "PUT", "/values/payment/gateway/password?website=1", `{"value":"******"}`)
+	assert.Exactly(t, http.StatusOK, rec.Code)
+	_, err = srv.String(pw.BindWebsite(1))
+	assert.Error(t, err, "Masked value must not be written")
+
+	rec = serve(h, "PUT", "/values/payment/gateway/password?website=1", `{"value":"n3w"}`)
+	assert.Exactly(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "n3w")
+	v, err := srv.String(pw.BindWebsite(1))
+	assert.NoError(t, err)
+	assert.Exactly(t, "n3w", v)
+}
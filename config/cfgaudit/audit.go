@@ -0,0 +1,239 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgaudit
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/config/element"
+	"github.com/corestoreio/csfw/util/conv"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// MaskedValue gets recorded instead of the old and new value of a path for
+// which Writer.Mask returns true.
+const MaskedValue = "******"
+
+// lockCount number of mutexes which serialize the writes. Paths are getting
+// distributed by their hash.
+const lockCount = 32
+
+// Entry a single recorded change of a configuration path.
+type Entry struct {
+	// Revision unique and increasing ID of the change. Assigned by the Sink.
+	Revision int64
+	// Path fully qualified path including scope and scope ID.
+	Path cfgpath.Path
+	// OldValue the value before the change. Only set if OldValid is true.
+	OldValue string
+	// OldValid false if the path had no value before the change.
+	OldValid bool
+	// NewValue the written value.
+	NewValue string
+	// Actor who has changed the value, for example a user name.
+	Actor string
+	// Created time of the change.
+	Created time.Time
+	// RollbackOf contains the revision which has been restored. Zero for
+	// normal writes.
+	RollbackOf int64
+}
+
+// String returns a human readable one line representation.
+func (e Entry) String() string {
+	old := "<nil>"
+	if e.OldValid {
+		old = fmt.Sprintf("%q", e.OldValue)
+	}
+	s := fmt.Sprintf("#%d %s %s by %q: %s => %q", e.Revision, e.Created.Format(time.RFC3339), e.Path, e.Actor, old, e.NewValue)
+	if e.RollbackOf > 0 {
+		s += fmt.Sprintf(" (rollback of #%d)", e.RollbackOf)
+	}
+	return s
+}
+
+// Entries a list of changes, mostly ordered by revision descending.
+type Entries []Entry
+
+// Sink stores the recorded changes. A Sink must be safe for concurrent use.
+type Sink interface {
+	// Append stores the entry and returns the assigned revision.
+	Append(Entry) (revision int64, err error)
+	// History returns all entries of a fully qualified path ordered by
+	// revision descending, the newest change first.
+	History(cfgpath.Path) (Entries, error)
+	// Revision returns a single entry. Error behaviour: NotFound.
+	Revision(revision int64) (Entry, error)
+}
+
+// ReadWriter reads the current value of a path and writes a new value. The
+// config.Service implements this interface.
+type ReadWriter interface {
+	config.Writer
+	String(cfgpath.Path) (string, error)
+}
+
+// Writer implements the config.Writer interface and records each write into
+// the Sink. Writer is safe for concurrent use. Writes to the same path are
+// getting serialized, so the recorded old value and the order of the history
+// match the order of the writes.
+type Writer struct {
+	// Actor gets recorded with each change. Use WithActor to create a Writer
+	// for a specific actor.
+	Actor string
+	// Clock returns the current time. Default time.Now. Mainly used for
+	// testing.
+	Clock func() time.Time
+	// Mask returns true for paths whose values must not be recorded in plain
+	// text, like passwords and API secrets. The Sink receives MaskedValue
+	// instead and a masked revision cannot be rolled back. Use MaskObscure to
+	// mask all obscure fields. Default nil records all values.
+	Mask func(cfgpath.Path) bool
+
+	rw    ReadWriter
+	sink  Sink
+	locks *[lockCount]sync.Mutex
+}
+
+// NewWriter creates a new auditing writer.
+func NewWriter(rw ReadWriter, s Sink) *Writer {
+	return &Writer{
+		Clock: time.Now,
+		rw:    rw,
+		sink:  s,
+		locks: new([lockCount]sync.Mutex),
+	}
+}
+
+// MaskObscure returns a function for Writer.Mask which masks all fields of
+// type element.TypeObscure in the SectionSlice.
+func MaskObscure(ss element.SectionSlice) (func(cfgpath.Path) bool, error) {
+	routes := make(map[string]bool)
+	for _, s := range ss {
+		for _, g := range s.Groups {
+			for _, f := range g.Fields {
+				if f.Type == nil || f.Type.Type() != element.TypeObscure {
+					continue
+				}
+				r, err := f.Route(s.ID, g.ID)
+				if err != nil {
+					return nil, errors.Wrapf(err, "[cfgaudit] MaskObscure Section %q Group %q", s.ID, g.ID)
+				}
+				routes[r.String()] = true
+			}
+		}
+	}
+	return func(p cfgpath.Path) bool {
+		return routes[p.Route.String()]
+	}, nil
+}
+
+func (w *Writer) isMasked(p cfgpath.Path) bool {
+	return w.Mask != nil && w.Mask(p)
+}
+
+// lock returns the locked mutex responsible for the path.
+func (w *Writer) lock(p cfgpath.Path) *sync.Mutex {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(p.String()))
+	mu := &w.locks[h.Sum32()%lockCount]
+	mu.Lock()
+	return mu
+}
+
+// WithActor returns a shallow copy of the Writer which records the actor.
+func (w *Writer) WithActor(actor string) *Writer {
+	w2 := *w
+	w2.Actor = actor
+	return &w2
+}
+
+// Write reads the old value, writes the new value and records the change. An
+// error from the Sink does not revert the write.
+func (w *Writer) Write(p cfgpath.Path, v interface{}) error {
+	_, err := w.write(p, v, 0)
+	return err
+}
+
+func (w *Writer) write(p cfgpath.Path, v interface{}, rollbackOf int64) (Entry, error) {
+	defer w.lock(p).Unlock()
+
+	e := Entry{
+		Path:       p,
+		NewValue:   toString(v),
+		Actor:      w.Actor,
+		RollbackOf: rollbackOf,
+	}
+
+	old, err := w.rw.String(p)
+	switch {
+	case err == nil:
+		e.OldValue = old
+		e.OldValid = true
+	case !errors.IsNotFound(err):
+		return Entry{}, errors.Wrapf(err, "[cfgaudit] Writer.String Path %q", p)
+	}
+
+	if err := w.rw.Write(p, v); err != nil {
+		return Entry{}, errors.Wrapf(err, "[cfgaudit] Writer.Write Path %q", p)
+	}
+
+	if w.isMasked(p) {
+		e.NewValue = MaskedValue
+		if e.OldValid {
+			e.OldValue = MaskedValue
+		}
+	}
+
+	e.Created = w.Clock()
+	if e.Revision, err = w.sink.Append(e); err != nil {
+		return Entry{}, errors.Wrapf(err, "[cfgaudit] Writer.Sink.Append Path %q", p)
+	}
+	return e, nil
+}
+
+// History returns all changes of the fully qualified path, the newest change
+// first.
+func (w *Writer) History(p cfgpath.Path) (Entries, error) {
+	es, err := w.sink.History(p)
+	return es, errors.Wrapf(err, "[cfgaudit] Writer.History Path %q", p)
+}
+
+// Rollback restores the value of the path as it has been written in the
+// revision. The rollback gets recorded as a new revision and returned. Error
+// behaviour: NotFound or NotSupported for a masked path.
+func (w *Writer) Rollback(revision int64) (Entry, error) {
+	old, err := w.sink.Revision(revision)
+	if err != nil {
+		return Entry{}, errors.Wrapf(err, "[cfgaudit] Writer.Rollback Revision %d", revision)
+	}
+	if w.isMasked(old.Path) || old.NewValue == MaskedValue {
+		return Entry{}, errors.NewNotSupportedf("[cfgaudit] Writer.Rollback Revision %d: The value of Path %q has been masked", revision, old.Path)
+	}
+	e, err := w.write(old.Path, old.NewValue, revision)
+	return e, errors.Wrapf(err, "[cfgaudit] Writer.Rollback Revision %d", revision)
+}
+
+func toString(v interface{}) string {
+	if s, err := conv.ToStringE(v); err == nil {
+		return s
+	}
+	return fmt.Sprintf("%v", v)
+}
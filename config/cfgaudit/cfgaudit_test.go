@@ -0,0 +1,269 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgaudit_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/cfgaudit"
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/config/element"
+	"github.com/corestoreio/csfw/util/cstesting"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	_ config.Writer       = (*cfgaudit.Writer)(nil)
+	_ cfgaudit.Sink       = (*cfgaudit.MemorySink)(nil)
+	_ cfgaudit.Sink       = (*cfgaudit.DBSink)(nil)
+	_ cfgaudit.ReadWriter = (*config.Service)(nil)
+)
+
+type recReceiver struct {
+	mu    sync.Mutex
+	paths []string
+	wg    sync.WaitGroup
+}
+
+func (rr *recReceiver) MessageConfig(p cfgpath.Path) error {
+	rr.mu.Lock()
+	rr.paths = append(rr.paths, p.String())
+	rr.mu.Unlock()
+	rr.wg.Done()
+	return nil
+}
+
+func TestWriterHistoryRollback(t *testing.T) {
+	srv := config.MustNewService(config.NewInMemoryStore(), config.WithPubSub())
+	defer func() { assert.NoError(t, srv.Close()) }()
+
+	rr := new(recReceiver)
+	_, err := srv.Subscribe(cfgpath.NewRoute("payment"), rr)
+	assert.NoError(t, err)
+
+	now := time.Date(2016, 11, 12, 13, 14, 15, 0, time.UTC)
+	aw := cfgaudit.NewWriter(srv, cfgaudit.NewMemorySink())
+	aw.Clock = func() time.Time { now = now.Add(time.Minute); return now }
+
+	p := cfgpath.MustNewByParts("payment/checkmo/active").BindWebsite(1)
+	other := cfgpath.MustNewByParts("payment/checkmo/title").BindWebsite(1)
+
+	rr.wg.Add(4)
+	assert.NoError(t, aw.WithActor("john").Write(p, true))
+	assert.NoError(t, aw.WithActor("jane").Write(other, "Check"))
+	assert.NoError(t, aw.WithActor("jane").Write(p, 0))
+
+	es, err := aw.History(p)
+	assert.NoError(t, err)
+	if !assert.Len(t, es, 2) {
+		t.FailNow()
+	}
+	assert.Exactly(t, `#3 2016-11-12T13:17:15Z websites/1/payment/checkmo/active by "jane": "true" => "0"`, es[0].String())
+	assert.Exactly(t, `#1 2016-11-12T13:15:15Z websites/1/payment/checkmo/active by "john": <nil> => "true"`, es[1].String())
+
+	rb, err := aw.WithActor("admin").Rollback(es[1].Revision)
+	assert.NoError(t, err)
+	assert.Exactly(t, `#4 2016-11-12T13:18:15Z websites/1/payment/checkmo/active by "admin": "0" => "true" (rollback of #1)`, rb.String())
+
+	v, err := srv.Bool(p)
+	assert.NoError(t, err)
+	assert.True(t, v)
+
+	rr.wg.Wait()
+	assert.Exactly(t, []string{
+		"websites/1/payment/checkmo/active",
+		"websites/1/payment/checkmo/title",
+		"websites/1/payment/checkmo/active",
+		"websites/1/payment/checkmo/active",
+	}, rr.paths)
+
+	_, err = aw.Rollback(99)
+	assert.True(t, errors.IsNotFound(err), "%+v", err)
+}
+
+type errReadWriter struct {
+	w       cfgmock.Write
+	readErr error
+}
+
+func (rw *errReadWriter) Write(p cfgpath.Path, v interface{}) error { return rw.w.Write(p, v) }
+
+func (rw *errReadWriter) String(cfgpath.Path) (string, error) { return "", rw.readErr }
+
+func TestWriterErrors(t *testing.T) {
+	p := cfgpath.MustNewByParts("payment/checkmo/active")
+
+	sink := cfgaudit.NewMemorySink()
+	aw := cfgaudit.NewWriter(&errReadWriter{readErr: errors.NewFatalf("DB gone")}, sink)
+	assert.True(t, errors.IsFatal(aw.Write(p, 1)))
+
+	aw = cfgaudit.NewWriter(&errReadWriter{
+		w:       cfgmock.Write{WriteError: errors.NewNotSupportedf("locked")},
+		readErr: errors.NewNotFoundf("not found"),
+	}, sink)
+	assert.True(t, errors.IsNotSupported(aw.Write(p, 1)))
+
+	es, err := sink.History(p)
+	assert.NoError(t, err)
+	assert.Empty(t, es, "Failed writes must not be recorded")
+}
+
+func TestWriterMask(t *testing.T) {
+	ss := element.MustNewConfiguration(
+		element.Section{
+			ID: cfgpath.NewRoute("payment"),
+			Groups: element.NewGroupSlice(
+				element.Group{
+					ID: cfgpath.NewRoute("gateway"),
+					Fields: element.NewFieldSlice(
+						element.Field{
+							ID:   cfgpath.NewRoute("password"),
+							Type: element.TypeObscure,
+						},
+						element.Field{
+							ID:   cfgpath.NewRoute("login"),
+							Type: element.TypeText,
+						},
+					),
+				},
+			),
+		},
+	)
+	srv := config.MustNewService(config.NewInMemoryStore())
+	aw := cfgaudit.NewWriter(srv, cfgaudit.NewMemorySink())
+	var err error
+	aw.Mask, err = cfgaudit.MaskObscure(ss)
+	assert.NoError(t, err)
+
+	pw := cfgpath.MustNewByParts("payment/gateway/password").BindWebsite(1)
+	login := cfgpath.MustNewByParts("payment/gateway/login").BindWebsite(1)
+	assert.NoError(t, aw.Write(pw, "s3cr3t"))
+	assert.NoError(t, aw.Write(pw, "n3w"))
+	assert.NoError(t, aw.Write(login, "shop"))
+
+	v, err := srv.String(pw)
+	assert.NoError(t, err)
+	assert.Exactly(t, "n3w", v, "Masking must not change the written value")
+
+	es, err := aw.History(pw)
+	assert.NoError(t, err)
+	if assert.Len(t, es, 2) {
+		assert.Exactly(t, cfgaudit.MaskedValue, es[0].OldValue)
+		assert.Exactly(t, cfgaudit.MaskedValue, es[0].NewValue)
+		assert.False(t, es[1].OldValid)
+		assert.Exactly(t, cfgaudit.MaskedValue, es[1].NewValue)
+	}
+	_, err = aw.Rollback(es[1].Revision)
+	assert.True(t, errors.IsNotSupported(err), "%+v", err)
+
+	es, err = aw.History(login)
+	assert.NoError(t, err)
+	assert.Exactly(t, "shop", es[0].NewValue)
+}
+
+// slowReader widens the gap between reading the old and writing the new
+// value.
+type slowReader struct {
+	*config.Service
+}
+
+func (sr slowReader) String(p cfgpath.Path) (string, error) {
+	s, err := sr.Service.String(p)
+	time.Sleep(time.Millisecond)
+	return s, err
+}
+
+func TestWriterConcurrent(t *testing.T) {
+	srv := config.MustNewService(config.NewInMemoryStore())
+	aw := cfgaudit.NewWriter(slowReader{Service: srv}, cfgaudit.NewMemorySink())
+	p := cfgpath.MustNewByParts("payment/checkmo/title")
+
+	const writes = 50
+	var wg sync.WaitGroup
+	for i := 0; i < writes; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			assert.NoError(t, aw.WithActor("actor").Write(p, i))
+		}(i)
+	}
+	wg.Wait()
+
+	es, err := aw.History(p)
+	assert.NoError(t, err)
+	if !assert.Len(t, es, writes) {
+		t.FailNow()
+	}
+	// each recorded old value must be the new value of the previous revision
+	for i := 0; i < len(es)-1; i++ {
+		assert.True(t, es[i].OldValid, "Index %d", i)
+		assert.Exactly(t, es[i+1].NewValue, es[i].OldValue, "Index %d", i)
+	}
+	assert.False(t, es[writes-1].OldValid)
+}
+
+var historyColumns = []string{"history_id", "scope", "scope_id", "path", "old_value", "new_value", "actor", "rollback_of", "created_at"}
+
+func TestDBSink(t *testing.T) {
+	dbc, dbMock := cstesting.MockDB(t)
+	defer func() {
+		dbMock.ExpectClose()
+		assert.NoError(t, dbc.Close())
+		if err := dbMock.ExpectationsWereMet(); err != nil {
+			t.Error("there were unfulfilled expections", err)
+		}
+	}()
+
+	ds := cfgaudit.NewDBSink(dbc.NewSession(nil))
+	t0 := time.Date(2016, 11, 12, 13, 14, 15, 0, time.UTC)
+	p := cfgpath.MustNewByParts("payment/checkmo/active").BindStore(2)
+
+	dbMock.ExpectExec("INSERT INTO core_config_data_history \\(`scope`,`scope_id`,`path`,`old_value`,`new_value`,`actor`,`rollback_of`,`created_at`\\) VALUES \\('stores',2,'payment/checkmo/active',NULL,'1','john',0,'2016-11-12 13:14:15'\\)").
+		WillReturnResult(sqlmock.NewResult(7, 1))
+	rev, err := ds.Append(cfgaudit.Entry{Path: p, NewValue: "1", Actor: "john", Created: t0})
+	assert.NoError(t, err)
+	assert.Exactly(t, int64(7), rev)
+
+	dbMock.ExpectQuery("SELECT (.+) FROM `core_config_data_history` WHERE \\(`scope` = 'stores' AND `scope_id` = 2 AND `path` = 'payment/checkmo/active'\\) ORDER BY history_id DESC").
+		WillReturnRows(sqlmock.NewRows(historyColumns).
+			AddRow(8, "stores", 2, "payment/checkmo/active", "1", "0", "jane", 0, t0.Add(time.Hour)).
+			AddRow(7, "stores", 2, "payment/checkmo/active", nil, "1", "john", 0, t0),
+		)
+	es, err := ds.History(p)
+	assert.NoError(t, err)
+	if assert.Len(t, es, 2) {
+		assert.Exactly(t, cfgaudit.Entry{Revision: 8, Path: p, OldValue: "1", OldValid: true, NewValue: "0", Actor: "jane", Created: t0.Add(time.Hour)}, es[0])
+		assert.Exactly(t, cfgaudit.Entry{Revision: 7, Path: p, NewValue: "1", Actor: "john", Created: t0}, es[1])
+	}
+
+	dbMock.ExpectQuery("SELECT (.+) FROM `core_config_data_history` WHERE \\(`history_id` = 7\\)").
+		WillReturnRows(sqlmock.NewRows(historyColumns).
+			AddRow(7, "stores", 2, "payment/checkmo/active", nil, "1", "john", 0, t0),
+		)
+	e, err := ds.Revision(7)
+	assert.NoError(t, err)
+	assert.Exactly(t, "john", e.Actor)
+
+	dbMock.ExpectQuery("SELECT (.+) FROM `core_config_data_history` WHERE \\(`history_id` = 9\\)").
+		WillReturnRows(sqlmock.NewRows(historyColumns))
+	_, err = ds.Revision(9)
+	assert.True(t, errors.IsNotFound(err), "%+v", err)
+}
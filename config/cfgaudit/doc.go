@@ -0,0 +1,37 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cfgaudit records all configuration changes into a history and
+// allows to roll back a path to a previous revision.
+//
+// Type Writer wraps a config.Writer, mostly the config.Service, and records
+// for each write the path, scope, old value, new value, actor and timestamp
+// into a Sink. Package cfgaudit provides an in-memory Sink and a DBSink which
+// uses the table core_config_data_history.
+//
+//		aw := cfgaudit.NewWriter(configService, cfgaudit.NewDBSink(dbrSession))
+//		err := aw.WithActor("admin:john").Write(p, "1")
+//		history, err := aw.History(p)
+//		_, err = aw.WithActor("admin:jane").Rollback(history[1].Revision)
+//
+// Values of secret paths should not end up in the history. The field
+// Writer.Mask replaces them with MaskedValue, for example for all obscure
+// fields:
+//
+//		aw.Mask, err = cfgaudit.MaskObscure(backend.ConfigStructure)
+//
+// A rollback writes the value of the old revision via the wrapped
+// config.Writer. If that writer is the config.Service the rollback gets
+// published to all subscribers like any other write.
+package cfgaudit
@@ -0,0 +1,191 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgaudit
+
+import (
+	"sync"
+
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// MemorySink stores the entries in memory. Mainly used for testing or as a
+// short living history.
+type MemorySink struct {
+	mu      sync.RWMutex
+	entries Entries
+}
+
+// NewMemorySink creates a new in-memory Sink.
+func NewMemorySink() *MemorySink {
+	return &MemorySink{}
+}
+
+// Append implements the Sink interface.
+func (ms *MemorySink) Append(e Entry) (int64, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	e.Revision = int64(len(ms.entries) + 1)
+	ms.entries = append(ms.entries, e)
+	return e.Revision, nil
+}
+
+// History implements the Sink interface.
+func (ms *MemorySink) History(p cfgpath.Path) (Entries, error) {
+	fq := p.String()
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	var es Entries
+	for i := len(ms.entries) - 1; i >= 0; i-- {
+		if ms.entries[i].Path.String() == fq {
+			es = append(es, ms.entries[i])
+		}
+	}
+	return es, nil
+}
+
+// Revision implements the Sink interface.
+func (ms *MemorySink) Revision(revision int64) (Entry, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	if revision < 1 || revision > int64(len(ms.entries)) {
+		return Entry{}, errors.NewNotFoundf("[cfgaudit] MemorySink Revision %d not found", revision)
+	}
+	return ms.entries[revision-1], nil
+}
+
+// TableHistory default name of the history table.
+const TableHistory = "core_config_data_history"
+
+// TableHistoryDDL creates the history table for the DBSink.
+const TableHistoryDDL = "CREATE TABLE IF NOT EXISTS `" + TableHistory + "` (\n" +
+	"  `history_id` int(10) unsigned NOT NULL AUTO_INCREMENT,\n" +
+	"  `scope` varchar(8) NOT NULL DEFAULT 'default',\n" +
+	"  `scope_id` int(11) NOT NULL DEFAULT '0',\n" +
+	"  `path` varchar(255) NOT NULL,\n" +
+	"  `old_value` text,\n" +
+	"  `new_value` text,\n" +
+	"  `actor` varchar(255) NOT NULL DEFAULT '',\n" +
+	"  `rollback_of` int(10) unsigned NOT NULL DEFAULT '0',\n" +
+	"  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,\n" +
+	"  PRIMARY KEY (`history_id`),\n" +
+	"  KEY `CORE_CONFIG_DATA_HISTORY_SCOPE_SCOPE_ID_PATH` (`scope`,`scope_id`,`path`)\n" +
+	") ENGINE=InnoDB DEFAULT CHARSET=utf8"
+
+// historyRow represents a row in the history table.
+type historyRow struct {
+	HistoryID  int64          `db:"history_id"`
+	Scope      string         `db:"scope"`
+	ScopeID    int64          `db:"scope_id"`
+	Path       string         `db:"path"`
+	OldValue   dbr.NullString `db:"old_value"`
+	NewValue   dbr.NullString `db:"new_value"`
+	Actor      string         `db:"actor"`
+	RollbackOf int64          `db:"rollback_of"`
+	CreatedAt  dbr.NullTime   `db:"created_at"`
+}
+
+var historyColumns = []string{"history_id", "scope", "scope_id", "path", "old_value", "new_value", "actor", "rollback_of", "created_at"}
+
+func (r *historyRow) toEntry() (Entry, error) {
+	p, err := cfgpath.NewByParts(r.Path)
+	if err != nil {
+		return Entry{}, errors.Wrapf(err, "[cfgaudit] cfgpath.NewByParts Path %q", r.Path)
+	}
+	return Entry{
+		Revision:   r.HistoryID,
+		Path:       p.Bind(scope.FromString(r.Scope).Pack(r.ScopeID)),
+		OldValue:   r.OldValue.String,
+		OldValid:   r.OldValue.Valid,
+		NewValue:   r.NewValue.String,
+		Actor:      r.Actor,
+		Created:    r.CreatedAt.Time,
+		RollbackOf: r.RollbackOf,
+	}, nil
+}
+
+// DBSink stores the entries in a database table. Create the table with
+// TableHistoryDDL.
+type DBSink struct {
+	// Table name of the history table. Default TableHistory.
+	Table string
+	db    dbr.SessionRunner
+}
+
+// NewDBSink creates a new Sink for the table core_config_data_history.
+func NewDBSink(db dbr.SessionRunner) *DBSink {
+	return &DBSink{
+		Table: TableHistory,
+		db:    db,
+	}
+}
+
+// Append implements the Sink interface.
+func (ds *DBSink) Append(e Entry) (int64, error) {
+	scp, id := e.Path.ScopeID.Unpack()
+	var old interface{}
+	if e.OldValid {
+		old = e.OldValue
+	}
+	res, err := ds.db.InsertInto(ds.Table).
+		Columns("scope", "scope_id", "path", "old_value", "new_value", "actor", "rollback_of", "created_at").
+		Values(scp.StrType(), id, e.Path.Route.String(), old, e.NewValue, e.Actor, e.RollbackOf, e.Created).
+		Exec()
+	if err != nil {
+		return 0, errors.Wrapf(err, "[cfgaudit] DBSink.Append Path %q", e.Path)
+	}
+	rev, err := res.LastInsertId()
+	return rev, errors.Wrapf(err, "[cfgaudit] DBSink.Append.LastInsertId Path %q", e.Path)
+}
+
+// History implements the Sink interface.
+func (ds *DBSink) History(p cfgpath.Path) (Entries, error) {
+	scp, id := p.ScopeID.Unpack()
+	var rows []*historyRow
+	_, err := ds.db.Select(historyColumns...).From(ds.Table).
+		Where(dbr.ConditionRaw("`scope` = ? AND `scope_id` = ? AND `path` = ?", scp.StrType(), id, p.Route.String())).
+		OrderDir("history_id", false).
+		LoadStructs(&rows)
+	if err != nil {
+		return nil, errors.Wrapf(err, "[cfgaudit] DBSink.History Path %q", p)
+	}
+	es := make(Entries, 0, len(rows))
+	for _, r := range rows {
+		e, err := r.toEntry()
+		if err != nil {
+			return nil, errors.Wrap(err, "[cfgaudit] DBSink.History")
+		}
+		es = append(es, e)
+	}
+	return es, nil
+}
+
+// Revision implements the Sink interface.
+func (ds *DBSink) Revision(revision int64) (Entry, error) {
+	var rows []*historyRow
+	_, err := ds.db.Select(historyColumns...).From(ds.Table).
+		Where(dbr.ConditionRaw("`history_id` = ?", revision)).
+		LoadStructs(&rows)
+	if err != nil {
+		return Entry{}, errors.Wrapf(err, "[cfgaudit] DBSink.Revision %d", revision)
+	}
+	if len(rows) == 0 {
+		return Entry{}, errors.NewNotFoundf("[cfgaudit] DBSink Revision %d not found", revision)
+	}
+	e, err := rows[0].toEntry()
+	return e, errors.Wrap(err, "[cfgaudit] DBSink.Revision")
+}
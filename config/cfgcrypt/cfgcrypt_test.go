@@ -0,0 +1,223 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgcrypt_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/corestoreio/csfw/config/cfgcrypt"
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/config/cfgmodel"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ cfgmodel.Encryptor = (*cfgcrypt.M1)(nil)
+var _ cfgmodel.Encryptor = (*cfgcrypt.M2)(nil)
+
+const (
+	key16 = "3f1c5e0b7d9a24e8"
+	key32 = "3f1c5e0b7d9a24e8c6b1f0a2d4e6c8b0"
+)
+
+// The vectors have been created with:
+//	printf 'SecretPassword\0\0' | openssl enc -bf-ecb -nopad -K <hex(key16)> | base64
+//	printf 'SecretPassword\0\0' | openssl enc -aes-256-ecb -nopad -K <hex(key32)> | base64
+//	printf 'SecretPassword\0\0' | openssl enc -aes-128-ecb -nopad -K <hex(key16)> | base64
+const (
+	vectorBlowfish = "gifjA1ec/doRXknYOftcEw=="
+	vectorAES256   = "0Uc0+T9pnk42664nP4vw7w=="
+	vectorAES128   = "qVHdIOnoKJ/M9vErYZEFeQ=="
+)
+
+func TestM1(t *testing.T) {
+	m1 := cfgcrypt.MustNewM1(key16)
+
+	p, err := m1.Decrypt([]byte(vectorBlowfish))
+	assert.NoError(t, err)
+	assert.Exactly(t, "SecretPassword", string(p))
+
+	c, err := m1.Encrypt([]byte("SecretPassword"))
+	assert.NoError(t, err)
+	assert.Exactly(t, vectorBlowfish, string(c))
+
+	// long key gets truncated like mcrypt does
+	m1 = cfgcrypt.MustNewM1(strings.Repeat(key32, 2))
+	c, err = m1.Encrypt([]byte("Gophers"))
+	assert.NoError(t, err)
+	p, err = m1.Decrypt(c)
+	assert.NoError(t, err)
+	assert.Exactly(t, "Gophers", string(p))
+
+	_, err = m1.Decrypt([]byte("YWJj"))
+	assert.True(t, errors.IsNotValid(err), "%+v", err)
+	_, err = m1.Decrypt([]byte("!!"))
+	assert.True(t, errors.IsNotValid(err), "%+v", err)
+
+	_, err = cfgcrypt.NewM1("")
+	assert.True(t, errors.IsEmpty(err), "%+v", err)
+}
+
+func TestM2Decrypt(t *testing.T) {
+	m2 := cfgcrypt.MustNewM2(key16, key32)
+	assert.Exactly(t, 1, m2.KeyVersion())
+
+	tests := []struct {
+		data    string
+		want    string
+		wantErr errors.BehaviourFunc
+	}{
+		{"0:0:" + vectorBlowfish, "SecretPassword", nil},
+		{"0:" + vectorBlowfish, "SecretPassword", nil},
+		{"1:" + vectorAES128, "SecretPassword", nil},
+		{"2:" + vectorAES128, "", errors.IsNotSupported},
+		{"x:" + vectorAES128, "", errors.IsNotValid},
+		{vectorBlowfish, "SecretPassword", nil},
+		{"1:1:" + vectorAES256, "SecretPassword", nil},
+		{"1:2:iv:" + vectorAES256, "", errors.IsNotSupported},
+		{"1:2:" + vectorAES256, "", errors.IsNotSupported},
+		{"1:9:" + vectorAES256, "", errors.IsNotSupported},
+		{"2:3:" + vectorAES256, "", errors.IsNotFound},
+		{"x:3:" + vectorAES256, "", errors.IsNotValid},
+		{"1:3:" + vectorAES256, "", errors.IsNotValid},
+		{"1:3:" + base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 40)), "", errors.IsNotValid},
+		{"", "", nil},
+	}
+	for i, test := range tests {
+		p, err := m2.Decrypt([]byte(test.data))
+		if test.wantErr != nil {
+			assert.True(t, test.wantErr(err), "Index %d => %+v", i, err)
+			assert.Nil(t, p, "Index %d", i)
+			continue
+		}
+		assert.NoError(t, err, "Index %d", i)
+		assert.Exactly(t, test.want, string(p), "Index %d", i)
+	}
+}
+
+func TestM2EncryptDecrypt(t *testing.T) {
+	m2 := cfgcrypt.MustNewM2(key16, key32)
+	m2.Rand = bytes.NewReader(make([]byte, 12))
+
+	c, err := m2.Encrypt([]byte("SecretPassword"))
+	assert.NoError(t, err)
+	assert.Exactly(t, "1:3:AAAAAAAAAAAAAAAA", string(c[:20]))
+
+	p, err := m2.Decrypt(c)
+	assert.NoError(t, err)
+	assert.Exactly(t, "SecretPassword", string(p))
+
+	// random source is exhausted
+	_, err = m2.Encrypt([]byte("SecretPassword"))
+	assert.True(t, errors.IsFatal(err), "%+v", err)
+
+	// new latest key can still decrypt old values
+	m2 = cfgcrypt.MustNewM2(key16, key32, "base64"+base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{'x'}, 32)))
+	p, err = m2.Decrypt(c)
+	assert.NoError(t, err)
+	assert.Exactly(t, "SecretPassword", string(p))
+
+	c2, err := m2.Encrypt([]byte("SecretPassword"))
+	assert.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(c2, []byte("2:3:")), "%s", c2)
+	p, err = m2.Decrypt(c2)
+	assert.NoError(t, err)
+	assert.Exactly(t, "SecretPassword", string(p))
+}
+
+func TestNewM2Errors(t *testing.T) {
+	_, err := cfgcrypt.NewM2()
+	assert.True(t, errors.IsEmpty(err), "%+v", err)
+	_, err = cfgcrypt.NewM2("", key32)
+	assert.True(t, errors.IsEmpty(err), "%+v", err)
+	_, err = cfgcrypt.NewM2(key32, key16)
+	assert.True(t, errors.IsNotValid(err), "%+v", err)
+	_, err = cfgcrypt.NewM2("base64!!")
+	assert.True(t, errors.IsNotValid(err), "%+v", err)
+}
+
+func TestM2Obscure(t *testing.T) {
+	m2 := cfgcrypt.MustNewM2(key32)
+	ob := cfgmodel.NewObscure("payment/paypal/password", cfgmodel.WithEncryptor(m2), cfgmodel.WithScopeStore())
+
+	mw := new(cfgmock.Write)
+	assert.NoError(t, ob.Write(mw, []byte("S3cr3t"), scope.Store.Pack(2)))
+	assert.Exactly(t, "stores/2/payment/paypal/password", mw.ArgPath)
+
+	p, err := ob.Get(cfgmock.NewService(cfgmock.PathValue{
+		cfgpath.MustNewByParts("payment/paypal/password").BindStore(2).String(): mw.ArgValue,
+	}).NewScoped(1, 2))
+	assert.NoError(t, err)
+	assert.Exactly(t, "S3cr3t", string(p))
+}
+
+func TestKeysFromEnv(t *testing.T) {
+	const name = "CS_CRYPT_KEY_TEST"
+	defer os.Unsetenv(name)
+
+	_, err := cfgcrypt.KeysFromEnv(name)
+	assert.True(t, errors.IsNotFound(err), "%+v", err)
+
+	os.Setenv(name, " "+key16+"\n"+key32+"\n")
+	keys, err := cfgcrypt.KeysFromEnv(name)
+	assert.NoError(t, err)
+	assert.Exactly(t, []string{key16, key32}, keys)
+}
+
+func TestKeyFromLocalXML(t *testing.T) {
+	k, err := cfgcrypt.KeyFromLocalXML(strings.NewReader(`<?xml version="1.0"?>
+<config>
+    <global>
+        <install><date><![CDATA[Thu, 11 Feb 2016 09:15:10 +0000]]></date></install>
+        <crypt>
+            <key><![CDATA[` + key32 + `]]></key>
+        </crypt>
+    </global>
+</config>`))
+	assert.NoError(t, err)
+	assert.Exactly(t, key32, k)
+
+	_, err = cfgcrypt.KeyFromLocalXML(strings.NewReader(`<config><global></global></config>`))
+	assert.True(t, errors.IsNotFound(err), "%+v", err)
+	_, err = cfgcrypt.KeyFromLocalXML(strings.NewReader(`<config`))
+	assert.True(t, errors.IsNotValid(err), "%+v", err)
+}
+
+func TestKeysFromEnvPHP(t *testing.T) {
+	tests := []struct {
+		php  string
+		want []string
+	}{
+		{"<?php\nreturn [\n    'crypt' => [\n        'key' => '" + key32 + "'\n    ],\n];", []string{key32}},
+		{"<?php\nreturn array (\n  'crypt' => \n  array (\n    'key' => '" + key16 + "\n" + key32 + "',\n  ),\n);", []string{key16, key32}},
+		{"<?php\nreturn [\"crypt\" => [\"key\" => \"" + key32 + "\"]];", []string{key32}},
+	}
+	for i, test := range tests {
+		keys, err := cfgcrypt.KeysFromEnvPHP(strings.NewReader(test.php))
+		assert.NoError(t, err, "Index %d", i)
+		assert.Exactly(t, test.want, keys, "Index %d", i)
+	}
+
+	_, err := cfgcrypt.KeysFromEnvPHP(strings.NewReader("<?php\nreturn ['db' => []];"))
+	assert.True(t, errors.IsNotFound(err), "%+v", err)
+	_, err = cfgcrypt.KeysFromEnvPHP(strings.NewReader("<?php\nreturn ['crypt' => ['key' => '']];"))
+	assert.True(t, errors.IsNotFound(err), "%+v", err)
+}
@@ -0,0 +1,45 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cfgcrypt provides Magento compatible encryptors for the
+// cfgmodel.Obscure type.
+//
+// Magento stores sensible configuration values, like payment credentials, in
+// an encrypted form in the table core_config_data. The secret key gets
+// defined in the file app/etc/local.xml (Magento 1) or app/etc/env.php
+// (Magento 2). With the same crypt key both systems, Magento and our Go
+// services, can read and write the encrypted values.
+//
+// M1 implements the Magento 1 algorithm: Blowfish in ECB mode with zero
+// padding and a base64 encoded result.
+//
+// M2 implements the Magento 2 algorithm with key versioning: the encrypted
+// value has the format "keyVersion:cipherVersion:base64data", for example
+// "0:3:Ywq3...". M2 encrypts always with the latest key and with the cipher
+// version 3 (libsodium ChaCha20-Poly1305 IETF). Decryption supports the
+// cipher versions 0 (Blowfish ECB), 1 (Rijndael-128 ECB, which equals AES-256
+// ECB with a 32 byte key) and 3. The cipher version 2 (Rijndael-256 CBC) uses
+// a block size which is not part of AES and returns a NotSupported error.
+// Older values with only two parts, "cipherVersion:base64data", use the key
+// version 0.
+//
+// The crypt key can be loaded from the environment variable CS_CRYPT_KEY, from
+// the Magento 1 file local.xml or from the Magento 2 file env.php.
+//
+//		keys, err := cfgcrypt.KeysFromEnv("")
+//		// handle error
+//		m2, err := cfgcrypt.NewM2(keys...)
+//		// handle error
+//		pw := cfgmodel.NewObscure("payment/paypal/password", cfgmodel.WithEncryptor(m2))
+package cfgcrypt
@@ -0,0 +1,104 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgcrypt
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// EnvCryptKey default name of the environment variable which contains the
+// crypt key. Multiple keys for Magento 2 must be separated by white spaces or
+// new lines. The last key is the latest key.
+const EnvCryptKey = "CS_CRYPT_KEY"
+
+// encodedKeyPrefix marks a base64 encoded key as generated by newer Magento 2
+// versions.
+const encodedKeyPrefix = "base64"
+
+// KeysFromEnv reads the crypt keys from the environment variable name. An
+// empty name falls back to EnvCryptKey. Error behaviour: NotFound.
+func KeysFromEnv(name string) ([]string, error) {
+	if name == "" {
+		name = EnvCryptKey
+	}
+	keys := splitKeys(os.Getenv(name))
+	if len(keys) == 0 {
+		return nil, errors.NewNotFoundf("[cfgcrypt] Environment variable %q is empty or not set", name)
+	}
+	return keys, nil
+}
+
+// KeyFromLocalXML extracts the crypt key of the Magento 1 file
+// app/etc/local.xml from node global/crypt/key. Error behaviour: NotValid or
+// NotFound.
+func KeyFromLocalXML(r io.Reader) (string, error) {
+	var lx struct {
+		Key string `xml:"global>crypt>key"`
+	}
+	if err := xml.NewDecoder(r).Decode(&lx); err != nil {
+		return "", errors.NewNotValidf("[cfgcrypt] KeyFromLocalXML.Decode: %s", err)
+	}
+	k := strings.TrimSpace(lx.Key)
+	if k == "" {
+		return "", errors.NewNotFoundf("[cfgcrypt] KeyFromLocalXML node global/crypt/key is empty")
+	}
+	return k, nil
+}
+
+var envPHPCryptKey = regexp.MustCompile(`(?s)['"]crypt['"]\s*=>\s*(?:array\s*\(|\[)\s*['"]key['"]\s*=>\s*(?:'([^']*)'|"([^"]*)")`)
+
+// KeysFromEnvPHP extracts the crypt keys of the Magento 2 file app/etc/env.php
+// from the entry crypt/key. The PHP file does not get executed, the keys must
+// be defined as literal strings. Error behaviour: NotFound or Fatal.
+func KeysFromEnvPHP(r io.Reader) ([]string, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, errors.NewFatal(err, "[cfgcrypt] KeysFromEnvPHP.ReadAll")
+	}
+	m := envPHPCryptKey.FindSubmatch(data)
+	if m == nil {
+		return nil, errors.NewNotFoundf("[cfgcrypt] KeysFromEnvPHP crypt/key entry not found")
+	}
+	keys := splitKeys(string(m[1]) + string(m[2]))
+	if len(keys) == 0 {
+		return nil, errors.NewNotFoundf("[cfgcrypt] KeysFromEnvPHP crypt/key entry is empty")
+	}
+	return keys, nil
+}
+
+func splitKeys(s string) []string {
+	return strings.Fields(s)
+}
+
+// decodeKey returns the raw key bytes. Keys with the prefix "base64" are
+// getting decoded.
+func decodeKey(k string) ([]byte, error) {
+	if !strings.HasPrefix(k, encodedKeyPrefix) {
+		return []byte(k), nil
+	}
+	b, err := base64.StdEncoding.DecodeString(k[len(encodedKeyPrefix):])
+	if err != nil {
+		return nil, errors.NewNotValidf("[cfgcrypt] Invalid base64 encoded key: %s", err)
+	}
+	return b, nil
+}
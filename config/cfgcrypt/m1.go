@@ -0,0 +1,139 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgcrypt
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"encoding/base64"
+
+	"github.com/corestoreio/csfw/util/errors"
+	"golang.org/x/crypto/blowfish"
+)
+
+// blowfishMaxKeySize mcrypt truncates longer Blowfish keys to 56 bytes.
+const blowfishMaxKeySize = 56
+
+// M1 implements the cfgmodel.Encryptor interface and is compatible to the
+// Magento 1 class Mage_Core_Model_Encryption. M1 is safe for concurrent use.
+type M1 struct {
+	block cipher.Block
+}
+
+// NewM1 creates a new Magento 1 compatible encryptor. The key can be found in
+// the file app/etc/local.xml. Error behaviour: Empty or NotValid.
+func NewM1(key string) (*M1, error) {
+	if key == "" {
+		return nil, errors.NewEmptyf("[cfgcrypt] NewM1 key is empty")
+	}
+	b, err := newBlowfish([]byte(key))
+	if err != nil {
+		return nil, errors.Wrap(err, "[cfgcrypt] NewM1")
+	}
+	return &M1{block: b}, nil
+}
+
+// MustNewM1 same as NewM1 but panics on error.
+func MustNewM1(key string) *M1 {
+	m, err := NewM1(key)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// Encrypt encrypts the data with Blowfish ECB and returns the base64 encoded
+// cipher text.
+func (m *M1) Encrypt(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	return encodeBase64(ecbEncrypt(m.block, data)), nil
+}
+
+// Decrypt decodes and decrypts the data. Like Magento it removes all white
+// spaces at the beginning and the end and all null bytes. Error behaviour:
+// NotValid.
+func (m *M1) Decrypt(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	raw, err := decodeBase64(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "[cfgcrypt] M1.Decrypt")
+	}
+	p, err := ecbDecrypt(m.block, raw)
+	if err != nil {
+		return nil, errors.Wrap(err, "[cfgcrypt] M1.Decrypt")
+	}
+	p = bytes.Trim(p, " \t\n\r\x00\x0B")
+	return bytes.Replace(p, []byte{0}, nil, -1), nil
+}
+
+func newBlowfish(key []byte) (cipher.Block, error) {
+	if len(key) > blowfishMaxKeySize {
+		key = key[:blowfishMaxKeySize]
+	}
+	b, err := blowfish.NewCipher(key)
+	if err != nil {
+		return nil, errors.NewNotValidf("[cfgcrypt] Blowfish: %s", err)
+	}
+	return b, nil
+}
+
+// ecbEncrypt pads the data with null bytes like mcrypt and encrypts each
+// block separately.
+func ecbEncrypt(b cipher.Block, data []byte) []byte {
+	bs := b.BlockSize()
+	n := len(data)
+	if r := n % bs; r > 0 {
+		n += bs - r
+	}
+	out := make([]byte, n)
+	copy(out, data)
+	for i := 0; i < n; i += bs {
+		b.Encrypt(out[i:i+bs], out[i:i+bs])
+	}
+	return out
+}
+
+// ecbDecrypt decrypts each block separately. The padding null bytes are not
+// getting removed.
+func ecbDecrypt(b cipher.Block, data []byte) ([]byte, error) {
+	bs := b.BlockSize()
+	if len(data)%bs != 0 {
+		return nil, errors.NewNotValidf("[cfgcrypt] Cipher text length %d is not a multiple of the block size %d", len(data), bs)
+	}
+	out := make([]byte, len(data))
+	for i := 0; i < len(data); i += bs {
+		b.Decrypt(out[i:i+bs], data[i:i+bs])
+	}
+	return out, nil
+}
+
+func encodeBase64(data []byte) []byte {
+	out := make([]byte, base64.StdEncoding.EncodedLen(len(data)))
+	base64.StdEncoding.Encode(out, data)
+	return out
+}
+
+func decodeBase64(data []byte) ([]byte, error) {
+	out := make([]byte, base64.StdEncoding.DecodedLen(len(data)))
+	n, err := base64.StdEncoding.Decode(out, data)
+	if err != nil {
+		return nil, errors.NewNotValidf("[cfgcrypt] Invalid base64 data: %s", err)
+	}
+	return out[:n], nil
+}
@@ -0,0 +1,209 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgcrypt
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"io"
+	"strconv"
+
+	"github.com/corestoreio/csfw/util/errors"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// Cipher versions as defined in Magento\Framework\Encryption\Encryptor.
+const (
+	CipherBlowfish = iota
+	CipherRijndael128
+	CipherRijndael256
+	CipherChaCha20Poly1305
+)
+
+// CipherLatest cipher version used for encryption.
+const CipherLatest = CipherChaCha20Poly1305
+
+// M2 implements the cfgmodel.Encryptor interface and is compatible to the
+// Magento 2 class Magento\Framework\Encryption\Encryptor. M2 is safe for
+// concurrent use.
+type M2 struct {
+	// Rand source of the nonce. Defaults to crypto/rand.Reader.
+	Rand io.Reader
+	keys [][]byte
+}
+
+// NewM2 creates a new Magento 2 compatible encryptor. The keys can be found in
+// the file app/etc/env.php. The index of a key is its key version and the last
+// key gets used for encryption. Keys with the prefix "base64" are getting
+// decoded. Error behaviour: Empty or NotValid.
+func NewM2(keys ...string) (*M2, error) {
+	if len(keys) == 0 {
+		return nil, errors.NewEmptyf("[cfgcrypt] NewM2 no keys provided")
+	}
+	m := &M2{
+		Rand: rand.Reader,
+		keys: make([][]byte, len(keys)),
+	}
+	for i, k := range keys {
+		if k == "" {
+			return nil, errors.NewEmptyf("[cfgcrypt] NewM2 key version %d is empty", i)
+		}
+		bk, err := decodeKey(k)
+		if err != nil {
+			return nil, errors.Wrapf(err, "[cfgcrypt] NewM2 key version %d", i)
+		}
+		m.keys[i] = bk
+	}
+	if l := len(m.keys[len(m.keys)-1]); l != chacha20poly1305.KeySize {
+		return nil, errors.NewNotValidf("[cfgcrypt] NewM2 latest key must have a length of %d bytes, have %d", chacha20poly1305.KeySize, l)
+	}
+	return m, nil
+}
+
+// MustNewM2 same as NewM2 but panics on error.
+func MustNewM2(keys ...string) *M2 {
+	m, err := NewM2(keys...)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// KeyVersion returns the version of the latest key.
+func (m *M2) KeyVersion() int {
+	return len(m.keys) - 1
+}
+
+// Encrypt encrypts the data with the latest key and ChaCha20-Poly1305. The
+// returned value has the format "keyVersion:3:base64(nonce|ciphertext)".
+func (m *M2) Encrypt(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	aead, err := chacha20poly1305.New(m.keys[m.KeyVersion()])
+	if err != nil {
+		return nil, errors.NewNotValidf("[cfgcrypt] M2.Encrypt: %s", err)
+	}
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(data)+aead.Overhead())
+	if _, err := io.ReadFull(m.Rand, nonce); err != nil {
+		return nil, errors.NewFatal(err, "[cfgcrypt] M2.Encrypt.Rand")
+	}
+	// Magento uses the nonce also as additional data.
+	sealed := aead.Seal(nonce, nonce, data, nonce)
+
+	var buf bytes.Buffer
+	buf.WriteString(strconv.Itoa(m.KeyVersion()))
+	buf.WriteByte(':')
+	buf.WriteString(strconv.Itoa(CipherLatest))
+	buf.WriteByte(':')
+	buf.Write(encodeBase64(sealed))
+	return buf.Bytes(), nil
+}
+
+// Decrypt decrypts a value in the format "keyVersion:cipherVersion:data". Like
+// Magento a value with only two parts has the format "cipherVersion:data" and
+// uses key version 0. A value without any colon uses key version 0 and
+// Blowfish. Values of the Rijndael-256 cipher (version 2) cannot be decrypted.
+// Error behaviour: NotValid, NotFound or NotSupported.
+func (m *M2) Decrypt(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	keyVersion, cipherVersion, payload, err := splitM2(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "[cfgcrypt] M2.Decrypt")
+	}
+	if keyVersion < 0 || keyVersion >= len(m.keys) {
+		return nil, errors.NewNotFoundf("[cfgcrypt] M2.Decrypt key version %d not found", keyVersion)
+	}
+	key := m.keys[keyVersion]
+
+	raw, err := decodeBase64(payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "[cfgcrypt] M2.Decrypt")
+	}
+
+	switch cipherVersion {
+	case CipherChaCha20Poly1305:
+		aead, err := chacha20poly1305.New(key)
+		if err != nil {
+			return nil, errors.NewNotValidf("[cfgcrypt] M2.Decrypt: %s", err)
+		}
+		if len(raw) < aead.NonceSize()+aead.Overhead() {
+			return nil, errors.NewNotValidf("[cfgcrypt] M2.Decrypt cipher text too short")
+		}
+		nonce := raw[:aead.NonceSize()]
+		p, err := aead.Open(nil, nonce, raw[aead.NonceSize():], nonce)
+		if err != nil {
+			return nil, errors.NewNotValidf("[cfgcrypt] M2.Decrypt: %s", err)
+		}
+		return p, nil
+	case CipherBlowfish:
+		b, err := newBlowfish(key)
+		if err != nil {
+			return nil, errors.Wrap(err, "[cfgcrypt] M2.Decrypt")
+		}
+		return m.decryptECB(b, raw)
+	case CipherRijndael128:
+		b, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, errors.NewNotValidf("[cfgcrypt] M2.Decrypt: %s", err)
+		}
+		return m.decryptECB(b, raw)
+	case CipherRijndael256:
+		return nil, errors.NewNotSupportedf("[cfgcrypt] M2.Decrypt cipher version %d (Rijndael-256) is not supported", cipherVersion)
+	}
+	return nil, errors.NewNotSupportedf("[cfgcrypt] M2.Decrypt unknown cipher version %d", cipherVersion)
+}
+
+func (m *M2) decryptECB(b cipher.Block, raw []byte) ([]byte, error) {
+	p, err := ecbDecrypt(b, raw)
+	if err != nil {
+		return nil, errors.Wrap(err, "[cfgcrypt] M2.Decrypt")
+	}
+	return bytes.TrimRight(p, "\x00"), nil
+}
+
+// splitM2 splits the value into its parts like Encryptor::decrypt.
+func splitM2(data []byte) (keyVersion, cipherVersion int, payload []byte, err error) {
+	parts := bytes.SplitN(data, []byte{':'}, 4)
+	switch len(parts) {
+	case 4:
+		// "keyVersion:2:iv:data" stored by the mcrypt Rijndael-256 CBC cipher.
+		keyVersion, err = atoi(parts[0])
+		return keyVersion, CipherRijndael256, parts[3], err
+	case 3:
+		if keyVersion, err = atoi(parts[0]); err != nil {
+			return
+		}
+		cipherVersion, err = atoi(parts[1])
+		return keyVersion, cipherVersion, parts[2], err
+	case 2:
+		// "cipherVersion:data" always uses the first key.
+		cipherVersion, err = atoi(parts[0])
+		return 0, cipherVersion, parts[1], err
+	}
+	return 0, CipherBlowfish, data, nil
+}
+
+func atoi(b []byte) (int, error) {
+	i, err := strconv.Atoi(string(b))
+	if err != nil {
+		return 0, errors.NewNotValidf("[cfgcrypt] Invalid version %q: %s", b, err)
+	}
+	return i, nil
+}
@@ -0,0 +1,235 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgexport
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/conv"
+	"github.com/corestoreio/csfw/util/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// Format defines the encoding of an export file.
+type Format uint8
+
+// Supported formats.
+const (
+	FormatJSON Format = iota + 1
+	FormatYAML
+)
+
+// FormatByExtension returns the format of a file name by its extension: .json,
+// .yml or .yaml. Error behaviour: NotSupported.
+func FormatByExtension(filename string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".json":
+		return FormatJSON, nil
+	case ".yml", ".yaml":
+		return FormatYAML, nil
+	}
+	return 0, errors.NewNotSupportedf("[cfgexport] Unknown file extension: %q", filename)
+}
+
+// Entry represents a single configuration value of an export file.
+type Entry struct {
+	Scope   string `json:"scope" yaml:"scope"`
+	ScopeID int64  `json:"scope_id" yaml:"scope_id"`
+	Path    string `json:"path" yaml:"path"`
+	Value   string `json:"value" yaml:"value"`
+}
+
+// newEntry creates an Entry from a path and a raw value.
+func newEntry(p cfgpath.Path, v interface{}) (Entry, error) {
+	val, err := conv.ToStringE(v)
+	if err != nil {
+		return Entry{}, errors.Wrapf(err, "[cfgexport] Path %q", p)
+	}
+	scp, id := p.ScopeID.Unpack()
+	return Entry{
+		Scope:   scp.StrType(),
+		ScopeID: id,
+		Path:    p.Route.String(),
+		Value:   val,
+	}, nil
+}
+
+// CfgPath converts the Entry into a fully qualified path.
+func (e Entry) CfgPath() (cfgpath.Path, error) {
+	if !scope.Valid(e.Scope) {
+		return cfgpath.Path{}, errors.NewNotValidf("[cfgexport] Invalid scope %q for path %q", e.Scope, e.Path)
+	}
+	p, err := cfgpath.NewByParts(e.Path)
+	if err != nil {
+		return cfgpath.Path{}, errors.Wrapf(err, "[cfgexport] Entry.CfgPath %q", e.Path)
+	}
+	return p.Bind(scope.MakeTypeID(scope.FromString(e.Scope), e.ScopeID)), nil
+}
+
+// String returns the fully qualified path, e.g. stores/2/web/unsecure/base_url.
+func (e Entry) String() string {
+	return fmt.Sprintf("%s/%d/%s", e.Scope, e.ScopeID, e.Path)
+}
+
+// Entries a list of configuration values. Sort them to get a stable order.
+type Entries []Entry
+
+// Len implements sort.Interface
+func (es Entries) Len() int { return len(es) }
+
+// Swap implements sort.Interface
+func (es Entries) Swap(i, j int) { es[i], es[j] = es[j], es[i] }
+
+// Less sorts by scope in the order default, websites, stores, then by scope
+// ID and then by path.
+func (es Entries) Less(i, j int) bool {
+	si, sj := scope.FromString(es[i].Scope), scope.FromString(es[j].Scope)
+	switch {
+	case si != sj:
+		return si < sj
+	case es[i].ScopeID != es[j].ScopeID:
+		return es[i].ScopeID < es[j].ScopeID
+	}
+	return es[i].Path < es[j].Path
+}
+
+// Sort sorts the entries in place and returns itself.
+func (es Entries) Sort() Entries {
+	sort.Stable(es)
+	return es
+}
+
+// Encode writes the sorted entries in the provided format to w. Error
+// behaviour: NotSupported.
+func (es Entries) Encode(w io.Writer, f Format) error {
+	es.Sort()
+	switch f {
+	case FormatJSON:
+		data, err := json.MarshalIndent(es, "", "\t")
+		if err != nil {
+			return errors.Wrap(err, "[cfgexport] Entries.Encode.json.MarshalIndent")
+		}
+		if _, err := w.Write(append(data, '\n')); err != nil {
+			return errors.Wrap(err, "[cfgexport] Entries.Encode.Write")
+		}
+		return nil
+	case FormatYAML:
+		data, err := yaml.Marshal(es)
+		if err != nil {
+			return errors.Wrap(err, "[cfgexport] Entries.Encode.yaml.Marshal")
+		}
+		_, err = w.Write(data)
+		return errors.Wrap(err, "[cfgexport] Entries.Encode.Write")
+	}
+	return errors.NewNotSupportedf("[cfgexport] Unknown format: %d", f)
+}
+
+// Decode reads the entries in the provided format from r. Error behaviour:
+// NotValid or NotSupported.
+func Decode(r io.Reader, f Format) (Entries, error) {
+	var es Entries
+	switch f {
+	case FormatJSON:
+		if err := json.NewDecoder(r).Decode(&es); err != nil {
+			return nil, errors.NewNotValid(err, "[cfgexport] Decode.json")
+		}
+	case FormatYAML:
+		data, err := ioutil.ReadAll(r)
+		if err != nil {
+			return nil, errors.Wrap(err, "[cfgexport] Decode.ReadAll")
+		}
+		if err := yaml.Unmarshal(data, &es); err != nil {
+			return nil, errors.NewNotValid(err, "[cfgexport] Decode.yaml")
+		}
+	default:
+		return nil, errors.NewNotSupportedf("[cfgexport] Unknown format: %d", f)
+	}
+	return es, nil
+}
+
+// Filter restricts the exported or imported paths. An empty Filter matches
+// all paths.
+type Filter struct {
+	// Routes contains route prefixes on section or group level, e.g. "web"
+	// or "web/unsecure". A path must match at least one of the prefixes.
+	Routes []string
+	// Scopes if not empty, a path must belong to one of the scopes.
+	Scopes []scope.Type
+}
+
+// Match returns true if the path matches the filter.
+func (f Filter) Match(p cfgpath.Path) bool {
+	return f.match(p.ScopeID.Type(), p.Route.String())
+}
+
+func (f Filter) match(st scope.Type, route string) bool {
+	if len(f.Scopes) > 0 {
+		var ok bool
+		for _, s := range f.Scopes {
+			ok = ok || s == st
+		}
+		if !ok {
+			return false
+		}
+	}
+	if len(f.Routes) == 0 {
+		return true
+	}
+	for _, r := range f.Routes {
+		r = strings.Trim(r, "/")
+		if route == r || strings.HasPrefix(route, r+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// Export reads all paths and values of the Storager which match the filter.
+// Paths which return a NotFound error, e.g. NULL values in core_config_data,
+// are skipped. The returned entries are sorted.
+func Export(s config.Storager, f Filter) (Entries, error) {
+	keys, err := s.AllKeys()
+	if err != nil {
+		return nil, errors.Wrap(err, "[cfgexport] Export.AllKeys")
+	}
+	es := make(Entries, 0, len(keys))
+	for _, p := range keys {
+		if !f.Match(p) {
+			continue
+		}
+		v, err := s.Get(p)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "[cfgexport] Export.Get %q", p)
+		}
+		e, err := newEntry(p, v)
+		if err != nil {
+			return nil, errors.Wrap(err, "[cfgexport] Export")
+		}
+		es = append(es, e)
+	}
+	return es.Sort(), nil
+}
@@ -0,0 +1,232 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgexport_test
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/cfgexport"
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/config/storage/ccd"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/cstesting"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestStorage() config.Storager {
+	return cfgmock.NewService(cfgmock.PathValue{
+		"stores/2/web/unsecure/base_url":  "http://store2.corestore.io",
+		"default/0/web/unsecure/base_url": "http://corestore.io",
+		"websites/1/general/locale/code":  "de_CH",
+		"default/0/web/cookie/lifetime":   3600,
+		"default/0/general/locale/code":   "en_US",
+	}).Storage
+}
+
+func TestExport(t *testing.T) {
+	tests := []struct {
+		filter   cfgexport.Filter
+		wantPath []string
+	}{
+		{cfgexport.Filter{}, []string{
+			"default/0/general/locale/code",
+			"default/0/web/cookie/lifetime",
+			"default/0/web/unsecure/base_url",
+			"websites/1/general/locale/code",
+			"stores/2/web/unsecure/base_url",
+		}},
+		{cfgexport.Filter{Routes: []string{"web/unsecure"}}, []string{
+			"default/0/web/unsecure/base_url",
+			"stores/2/web/unsecure/base_url",
+		}},
+		{cfgexport.Filter{Routes: []string{"web/unsec"}}, []string{}},
+		{cfgexport.Filter{Routes: []string{"general", "web/cookie"}, Scopes: []scope.Type{scope.Default}}, []string{
+			"default/0/general/locale/code",
+			"default/0/web/cookie/lifetime",
+		}},
+		{cfgexport.Filter{Scopes: []scope.Type{scope.Website, scope.Store}}, []string{
+			"websites/1/general/locale/code",
+			"stores/2/web/unsecure/base_url",
+		}},
+	}
+	for i, test := range tests {
+		es, err := cfgexport.Export(newTestStorage(), test.filter)
+		assert.NoError(t, err, "Index %d", i)
+		have := make([]string, len(es))
+		for j, e := range es {
+			have[j] = e.String()
+		}
+		assert.Exactly(t, test.wantPath, have, "Index %d", i)
+	}
+}
+
+func TestEntriesEncodeDecode(t *testing.T) {
+	es, err := cfgexport.Export(newTestStorage(), cfgexport.Filter{Routes: []string{"web"}})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		format cfgexport.Format
+		want   string
+	}{
+		{cfgexport.FormatJSON, `[
+	{
+		"scope": "default",
+		"scope_id": 0,
+		"path": "web/cookie/lifetime",
+		"value": "3600"
+	},
+	{
+		"scope": "default",
+		"scope_id": 0,
+		"path": "web/unsecure/base_url",
+		"value": "http://corestore.io"
+	},
+	{
+		"scope": "stores",
+		"scope_id": 2,
+		"path": "web/unsecure/base_url",
+		"value": "http://store2.corestore.io"
+	}
+]
+`},
+		{cfgexport.FormatYAML, `- scope: default
+  scope_id: 0
+  path: web/cookie/lifetime
+  value: "3600"
+- scope: default
+  scope_id: 0
+  path: web/unsecure/base_url
+  value: http://corestore.io
+- scope: stores
+  scope_id: 2
+  path: web/unsecure/base_url
+  value: http://store2.corestore.io
+`},
+	}
+	for i, test := range tests {
+		var buf bytes.Buffer
+		assert.NoError(t, es.Encode(&buf, test.format), "Index %d", i)
+		assert.Exactly(t, test.want, buf.String(), "Index %d", i)
+
+		es2, err := cfgexport.Decode(&buf, test.format)
+		assert.NoError(t, err, "Index %d", i)
+		assert.Exactly(t, es, es2, "Index %d", i)
+	}
+
+	err = es.Encode(new(bytes.Buffer), 0)
+	assert.True(t, errors.IsNotSupported(err), "Error: %+v", err)
+	_, err = cfgexport.Decode(bytes.NewBufferString("[{"), cfgexport.FormatJSON)
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+}
+
+func TestFormatByExtension(t *testing.T) {
+	f, err := cfgexport.FormatByExtension("/tmp/prod.YAML")
+	assert.NoError(t, err)
+	assert.Exactly(t, cfgexport.FormatYAML, f)
+	f, err = cfgexport.FormatByExtension("prod.json")
+	assert.NoError(t, err)
+	assert.Exactly(t, cfgexport.FormatJSON, f)
+	_, err = cfgexport.FormatByExtension("prod.toml")
+	assert.True(t, errors.IsNotSupported(err), "Error: %+v", err)
+}
+
+func TestImport(t *testing.T) {
+	es := cfgexport.Entries{
+		{Scope: "stores", ScopeID: 2, Path: "web/unsecure/base_url", Value: "http://store2.staging.io"},
+		{Scope: "default", ScopeID: 0, Path: "web/unsecure/base_url", Value: "http://corestore.io"},
+		{Scope: "stores", ScopeID: 3, Path: "web/unsecure/base_url", Value: "http://store3.corestore.io"},
+		{Scope: "default", ScopeID: 0, Path: "general/locale/code", Value: "de_DE"},
+	}
+	const wantDiff = `~ stores/2/web/unsecure/base_url: "http://store2.corestore.io" => "http://store2.staging.io"
++ stores/3/web/unsecure/base_url: "http://store3.corestore.io"
+`
+	s := newTestStorage()
+	p2 := cfgpath.MustNewByParts("web/unsecure/base_url").BindStore(2)
+	p3 := cfgpath.MustNewByParts("web/unsecure/base_url").BindStore(3)
+
+	cs, err := cfgexport.Import(s, es, cfgexport.ImportOptions{
+		Filter: cfgexport.Filter{Routes: []string{"web"}},
+		DryRun: true,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, cs, 3)
+	var buf bytes.Buffer
+	assert.NoError(t, cs.WriteDiff(&buf))
+	assert.Exactly(t, wantDiff, buf.String())
+	_, err = s.Get(p3)
+	assert.True(t, errors.IsNotFound(err), "Error: %+v", err)
+
+	w := new(cfgmock.Write)
+	_, err = cfgexport.Import(s, es[:1], cfgexport.ImportOptions{Writer: w})
+	assert.NoError(t, err)
+	assert.Exactly(t, "stores/2/web/unsecure/base_url", w.ArgPath)
+	assert.Exactly(t, "http://store2.staging.io", w.ArgValue)
+
+	cs, err = cfgexport.Import(s, es, cfgexport.ImportOptions{
+		Filter: cfgexport.Filter{Routes: []string{"web"}},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, cs.Modified(), 2)
+	v, err := s.Get(p3)
+	assert.NoError(t, err)
+	assert.Exactly(t, "http://store3.corestore.io", v)
+	v, err = s.Get(p2)
+	assert.NoError(t, err)
+	assert.Exactly(t, "http://store2.staging.io", v)
+	v, err = s.Get(cfgpath.MustNewByParts("general/locale/code"))
+	assert.NoError(t, err)
+	assert.Exactly(t, "en_US", v, "Filtered path must not be imported")
+
+	_, err = cfgexport.Import(s, cfgexport.Entries{{Scope: "groups", Path: "web/unsecure/base_url"}}, cfgexport.ImportOptions{})
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+}
+
+func TestExportDBStorage(t *testing.T) {
+	dbc, dbMock := cstesting.MockDB(t)
+	defer func() {
+		dbMock.ExpectClose()
+		assert.NoError(t, dbc.Close())
+		if err := dbMock.ExpectationsWereMet(); err != nil {
+			t.Error("there were unfulfilled expections", err)
+		}
+	}()
+
+	dbs := ccd.MustNewDBStorage(dbc.DB).Start()
+	defer func() { assert.NoError(t, dbs.Stop()) }()
+
+	dbMock.ExpectPrepare("SELECT scope,scope_id,path FROM `[^`]+` ORDER BY scope,scope_id,path").ExpectQuery().WillReturnRows(
+		sqlmock.NewRows([]string{"scope", "scope_id", "path"}).
+			AddRow("default", 0, "web/unsecure/base_url").
+			AddRow("default", 0, "web/secure/base_url").
+			AddRow("stores", 1, "general/locale/code"),
+	)
+	prepSel := dbMock.ExpectPrepare("SELECT `value` FROM `[^`]+` WHERE `scope`=\\? AND `scope_id`=\\? AND `path`=\\?")
+	prepSel.ExpectQuery().WithArgs(driver.Value("default"), driver.Value(0), driver.Value([]byte("web/unsecure/base_url"))).
+		WillReturnRows(sqlmock.NewRows([]string{"value"}).AddRow("http://corestore.io"))
+	prepSel.ExpectQuery().WithArgs(driver.Value("default"), driver.Value(0), driver.Value([]byte("web/secure/base_url"))).
+		WillReturnRows(sqlmock.NewRows([]string{"value"}).AddRow(nil))
+
+	es, err := cfgexport.Export(dbs, cfgexport.Filter{Routes: []string{"web"}})
+	assert.NoError(t, err)
+	assert.Exactly(t, cfgexport.Entries{
+		{Scope: "default", ScopeID: 0, Path: "web/unsecure/base_url", Value: "http://corestore.io"},
+	}, es)
+}
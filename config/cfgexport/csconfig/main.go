@@ -0,0 +1,239 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Command csconfig exports and imports the core_config_data table to and from a
+stable, diffable JSON or YAML file. The database connection will be read from
+the environment variable CS_DSN.
+
+Usage:
+	csconfig export [flags] file.(json|yaml)
+	csconfig import [flags] file.(json|yaml)
+	csconfig migrate [-to m1|m2] in.(json|yaml) out.(json|yaml)
+
+Flags:
+	-routes  comma separated route prefixes, e.g. web,general/locale
+	-scopes  comma separated scopes: default, websites, stores
+	-dry-run import: print only the diff, do not write (default false)
+	-to      migrate: target Magento version m1 or m2 (default m2)
+
+A file name of "-" writes to stdout or reads from stdin; in this case the
+format defaults to YAML.
+
+The migrate command does not need a database connection. It translates the
+paths and values of an exported file from Magento 1 to Magento 2, or the other
+way round, with the default rules of package config/cfgmigrate:
+
+	csconfig export m1.yaml # CS_DSN of the Magento 1 database
+	csconfig migrate -to m2 m1.yaml m2.yaml
+	csconfig import -dry-run m2.yaml # CS_DSN of the Magento 2 database
+*/
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/cfgexport"
+	"github.com/corestoreio/csfw/config/cfgmigrate"
+	"github.com/corestoreio/csfw/config/storage/ccd"
+	"github.com/corestoreio/csfw/storage/csdb"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "%+v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout io.Writer) error {
+	if len(args) < 1 {
+		return errors.NewNotValidf("[csconfig] Missing command: export or import")
+	}
+	cmd := args[0]
+	if cmd == "migrate" {
+		return migrate(args[1:], stdout)
+	}
+	fs := flag.NewFlagSet(cmd, flag.ContinueOnError)
+	routes := fs.String("routes", "", "Comma separated route prefixes, e.g. web,general/locale")
+	scopes := fs.String("scopes", "", "Comma separated scopes: default, websites, stores")
+	dryRun := fs.Bool("dry-run", false, "Import: print only the diff")
+	if err := fs.Parse(args[1:]); err != nil {
+		return errors.Wrap(err, "[csconfig] Parse")
+	}
+	if fs.NArg() != 1 {
+		return errors.NewNotValidf("[csconfig] Expecting exactly one file name")
+	}
+	fileName := fs.Arg(0)
+
+	f, err := newFilter(*routes, *scopes)
+	if err != nil {
+		return errors.Wrap(err, "[csconfig] newFilter")
+	}
+	format := cfgexport.FormatYAML
+	if fileName != "-" {
+		if format, err = cfgexport.FormatByExtension(fileName); err != nil {
+			return errors.Wrap(err, "[csconfig] FormatByExtension")
+		}
+	}
+
+	dbc, err := csdb.Connect()
+	if err != nil {
+		return errors.Wrap(err, "[csconfig] csdb.Connect")
+	}
+	defer dbc.Close()
+	dbs := ccd.MustNewDBStorage(dbc.DB).Start()
+	defer dbs.Stop()
+
+	switch cmd {
+	case "export":
+		es, err := cfgexport.Export(dbs, f)
+		if err != nil {
+			return errors.Wrap(err, "[csconfig] Export")
+		}
+		w := stdout
+		if fileName != "-" {
+			fh, err := os.Create(fileName)
+			if err != nil {
+				return errors.Wrap(err, "[csconfig] Create")
+			}
+			defer fh.Close()
+			w = fh
+		}
+		return errors.Wrap(es.Encode(w, format), "[csconfig] Encode")
+
+	case "import":
+		var r io.Reader = os.Stdin
+		if fileName != "-" {
+			fh, err := os.Open(fileName)
+			if err != nil {
+				return errors.Wrap(err, "[csconfig] Open")
+			}
+			defer fh.Close()
+			r = fh
+		}
+		es, err := cfgexport.Decode(r, format)
+		if err != nil {
+			return errors.Wrap(err, "[csconfig] Decode")
+		}
+		cs, err := cfgexport.Import(dbs, es, cfgexport.ImportOptions{
+			Filter: f,
+			DryRun: *dryRun,
+		})
+		if err != nil {
+			return errors.Wrap(err, "[csconfig] Import")
+		}
+		return errors.Wrap(cs.WriteDiff(stdout), "[csconfig] WriteDiff")
+	}
+	return errors.NewNotSupportedf("[csconfig] Unknown command %q", cmd)
+}
+
+func migrate(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	to := fs.String("to", "m2", "Target Magento version: m1 or m2")
+	if err := fs.Parse(args); err != nil {
+		return errors.Wrap(err, "[csconfig] Parse")
+	}
+	if fs.NArg() != 2 {
+		return errors.NewNotValidf("[csconfig] Expecting exactly two file names: in and out")
+	}
+	var d cfgmigrate.Direction
+	switch *to {
+	case "m2":
+		d = cfgmigrate.M1ToM2
+	case "m1":
+		d = cfgmigrate.M2ToM1
+	default:
+		return errors.NewNotValidf("[csconfig] Unknown target version %q", *to)
+	}
+
+	var r io.Reader = os.Stdin
+	inFormat := cfgexport.FormatYAML
+	if in := fs.Arg(0); in != "-" {
+		var err error
+		if inFormat, err = cfgexport.FormatByExtension(in); err != nil {
+			return errors.Wrap(err, "[csconfig] FormatByExtension")
+		}
+		fh, err := os.Open(in)
+		if err != nil {
+			return errors.Wrap(err, "[csconfig] Open")
+		}
+		defer fh.Close()
+		r = fh
+	}
+	es, err := cfgexport.Decode(r, inFormat)
+	if err != nil {
+		return errors.Wrap(err, "[csconfig] Decode")
+	}
+
+	src := config.NewInMemoryStore()
+	if _, err := cfgexport.Import(src, es, cfgexport.ImportOptions{}); err != nil {
+		return errors.Wrap(err, "[csconfig] Import")
+	}
+	dst := config.NewInMemoryStore()
+	res, err := cfgmigrate.MustNewMapper(cfgmigrate.DefaultRules...).Migrate(d, src, dst)
+	if err != nil {
+		return errors.Wrap(err, "[csconfig] Migrate")
+	}
+	out, err := cfgexport.Export(dst, cfgexport.Filter{})
+	if err != nil {
+		return errors.Wrap(err, "[csconfig] Export")
+	}
+
+	w := stdout
+	outFormat := cfgexport.FormatYAML
+	if fn := fs.Arg(1); fn != "-" {
+		if outFormat, err = cfgexport.FormatByExtension(fn); err != nil {
+			return errors.Wrap(err, "[csconfig] FormatByExtension")
+		}
+		fh, err := os.Create(fn)
+		if err != nil {
+			return errors.Wrap(err, "[csconfig] Create")
+		}
+		defer fh.Close()
+		w = fh
+		defer func() {
+			fmt.Fprintf(stdout, "Migrated %d values, skipped %d values\n", res.Migrated, len(res.Skipped))
+			for _, p := range res.Skipped {
+				fmt.Fprintf(stdout, "skipped %s\n", p)
+			}
+		}()
+	}
+	return errors.Wrap(out.Encode(w, outFormat), "[csconfig] Encode")
+}
+
+func newFilter(routes, scopes string) (cfgexport.Filter, error) {
+	var f cfgexport.Filter
+	if routes != "" {
+		f.Routes = strings.Split(routes, ",")
+	}
+	if scopes == "" {
+		return f, nil
+	}
+	for _, s := range strings.Split(scopes, ",") {
+		s = strings.TrimSpace(s)
+		if !scope.Valid(s) {
+			return f, errors.NewNotValidf("[csconfig] Unknown scope %q", s)
+		}
+		f.Scopes = append(f.Scopes, scope.FromString(s))
+	}
+	return f, nil
+}
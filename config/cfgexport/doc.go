@@ -0,0 +1,27 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cfgexport exports and imports configuration values to and from
+// stable, diffable JSON or YAML files.
+//
+// The typical use case is moving configuration between staging and
+// production. Export reads all paths including scope and scope ID from any
+// config.Storager, for example ccd.DBStorage, filters them by route prefix and
+// scope and returns them sorted. Import compares a file with the current
+// values, prints a diff and writes the changes unless a dry-run has been
+// requested. Paths which exist only in the destination are never deleted.
+//
+// The command in subpackage csconfig wraps this package for the
+// core_config_data table.
+package cfgexport
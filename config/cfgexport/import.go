@@ -0,0 +1,163 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgexport
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/util/conv"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// Action defines what happens with an imported Entry.
+type Action uint8
+
+// Possible actions of a Change.
+const (
+	ActionUnchanged Action = iota
+	ActionAdd
+	ActionUpdate
+)
+
+// String returns the diff symbol of the action.
+func (a Action) String() string {
+	switch a {
+	case ActionAdd:
+		return "+"
+	case ActionUpdate:
+		return "~"
+	}
+	return "="
+}
+
+// Change describes the difference between an imported Entry and the current
+// value.
+type Change struct {
+	Entry
+	Action Action
+	// OldValue contains the current value in case of an ActionUpdate.
+	OldValue string
+}
+
+// String returns a diff line.
+func (c Change) String() string {
+	switch c.Action {
+	case ActionAdd:
+		return fmt.Sprintf("+ %s: %q", c.Entry, c.Value)
+	case ActionUpdate:
+		return fmt.Sprintf("~ %s: %q => %q", c.Entry, c.OldValue, c.Value)
+	}
+	return fmt.Sprintf("= %s: %q", c.Entry, c.Value)
+}
+
+// Changes a list of Change.
+type Changes []Change
+
+// Modified returns only the added and updated changes.
+func (cs Changes) Modified() Changes {
+	ret := make(Changes, 0, len(cs))
+	for _, c := range cs {
+		if c.Action != ActionUnchanged {
+			ret = append(ret, c)
+		}
+	}
+	return ret
+}
+
+// WriteDiff writes one line for each added or updated Change to w.
+func (cs Changes) WriteDiff(w io.Writer) error {
+	for _, c := range cs.Modified() {
+		if _, err := fmt.Fprintln(w, c.String()); err != nil {
+			return errors.Wrap(err, "[cfgexport] Changes.WriteDiff")
+		}
+	}
+	return nil
+}
+
+// Diff compares the entries with the current values of the Storager. Entries
+// which do not match the filter are ignored. The returned Changes are sorted
+// and contain also unchanged entries.
+func Diff(current config.Storager, es Entries, f Filter) (Changes, error) {
+	sorted := make(Entries, len(es))
+	copy(sorted, es)
+	cs := make(Changes, 0, len(es))
+	for _, e := range sorted.Sort() {
+		p, err := e.CfgPath()
+		if err != nil {
+			return nil, errors.Wrap(err, "[cfgexport] Diff")
+		}
+		if !f.Match(p) {
+			continue
+		}
+		c := Change{Entry: e}
+		v, err := current.Get(p)
+		switch {
+		case errors.IsNotFound(err):
+			c.Action = ActionAdd
+		case err != nil:
+			return nil, errors.Wrapf(err, "[cfgexport] Diff.Get %q", p)
+		default:
+			old, err := conv.ToStringE(v)
+			if err != nil {
+				return nil, errors.Wrapf(err, "[cfgexport] Diff Path %q", p)
+			}
+			if old != e.Value {
+				c.Action = ActionUpdate
+				c.OldValue = old
+			}
+		}
+		cs = append(cs, c)
+	}
+	return cs, nil
+}
+
+// ImportOptions configures the Import function.
+type ImportOptions struct {
+	Filter Filter
+	// DryRun calculates only the changes without writing them.
+	DryRun bool
+	// Writer optional, for example the config.Service to let subscribers know
+	// about the changes. If nil, the values gets written with the Set function
+	// of the Storager.
+	Writer config.Writer
+}
+
+// Import writes all added and updated entries into the Storager or the
+// optional Writer and returns the changes. Writing stops at the first error.
+func Import(current config.Storager, es Entries, o ImportOptions) (Changes, error) {
+	cs, err := Diff(current, es, o.Filter)
+	if err != nil {
+		return nil, errors.Wrap(err, "[cfgexport] Import")
+	}
+	if o.DryRun {
+		return cs, nil
+	}
+	write := current.Set
+	if o.Writer != nil {
+		write = o.Writer.Write
+	}
+	for _, c := range cs.Modified() {
+		p, err := c.CfgPath()
+		if err != nil {
+			return nil, errors.Wrap(err, "[cfgexport] Import")
+		}
+		if err := write(p, c.Value); err != nil {
+			return nil, errors.Wrapf(err, "[cfgexport] Import.Write %q", p)
+		}
+	}
+	return cs, nil
+}
@@ -0,0 +1,269 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfglint
+
+import (
+	"go/ast"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/corestoreio/csfw/config/element"
+	"github.com/corestoreio/csfw/store/scope"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+//go:generate go run gen_routes.go
+
+// Analyzer checks the constant routes passed to the cfgmodel constructors and
+// to cfgpath.MustNewByParts and cfgpath.NewByParts.
+var Analyzer = &analysis.Analyzer{
+	Name:      "cfglint",
+	Doc:       "check configuration paths of cfgmodel and cfgpath against the element.SectionSlice definitions",
+	Run:       run,
+	Requires:  []*analysis.Analyzer{inspect.Analyzer},
+	FactTypes: []analysis.Fact{new(structureFact)},
+}
+
+// withMagento includes the known paths of Magento 2.
+var withMagento bool
+
+func init() {
+	Analyzer.Flags.BoolVar(&withMagento, "magento", true, "accept the configuration paths of Magento 2")
+}
+
+// scalarModels cannot read the comma separated values of a multiselect field.
+var scalarModels = map[string]bool{
+	"NewBool":     true,
+	"NewInt":      true,
+	"NewFloat64":  true,
+	"NewTime":     true,
+	"NewDuration": true,
+	"NewMoney":    true,
+	"NewLocation": true,
+}
+
+// bindScopes maps the cfgpath.Path bind methods and the cfgmodel scope
+// options to the scope which they require.
+var bindScopes = map[string]scope.Type{
+	"BindWebsite":      scope.Website,
+	"BindStore":        scope.Store,
+	"WithScopeWebsite": scope.Website,
+	"WithScopeStore":   scope.Store,
+}
+
+// knownRoutes contains the fields of the current package, of all imported
+// packages and optionally of Magento.
+type knownRoutes struct {
+	fields  map[string]Field
+	magento map[string]bool
+}
+
+func (k knownRoutes) lookup(route string) (Field, bool, bool) {
+	if f, ok := k.fields[route]; ok {
+		return f, true, true
+	}
+	return Field{}, false, k.magento[route]
+}
+
+// suggest returns the most similar known route with an edit distance of at
+// most two or an empty string.
+func (k knownRoutes) suggest(route string) string {
+	all := make([]string, 0, len(k.fields)+len(k.magento))
+	for r := range k.fields {
+		all = append(all, r)
+	}
+	for r := range k.magento {
+		all = append(all, r)
+	}
+	sort.Strings(all)
+	best, bestDist := "", 3
+	for _, r := range all {
+		if d := levenshtein(route, r); d < bestDist {
+			best, bestDist = r, d
+		}
+	}
+	return best
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	own := collectFields(pass)
+	if len(own) > 0 {
+		pass.ExportPackageFact(&structureFact{Fields: own})
+	}
+
+	kr := knownRoutes{
+		fields: make(map[string]Field),
+	}
+	for _, pf := range pass.AllPackageFacts() {
+		if sf, ok := pf.Fact.(*structureFact); ok && pf.Package != pass.Pkg {
+			for r, f := range sf.Fields {
+				kr.fields[r] = f
+			}
+		}
+	}
+	for r, f := range own {
+		kr.fields[r] = f
+	}
+	if withMagento {
+		kr.magento = make(map[string]bool, len(magentoRoutes))
+		for _, r := range magentoRoutes {
+			kr.magento[r] = true
+		}
+	}
+
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	insp.WithStack([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		call := n.(*ast.CallExpr)
+		fn := callee(pass, call)
+		if fn == nil {
+			return true
+		}
+		switch {
+		case fn.Pkg().Path() == pkgCfgmodel && strings.HasPrefix(fn.Name(), "New") && len(call.Args) > 0:
+			route, ok := constString(pass, call.Args[0])
+			if !ok {
+				return true
+			}
+			f, hasField := checkRoute(pass, kr, call, route)
+			if !hasField {
+				return true
+			}
+			for _, arg := range call.Args[1:] {
+				if opt, ok := unparen(arg).(*ast.CallExpr); ok {
+					if ofn := callee(pass, opt); ofn != nil && ofn.Pkg().Path() == pkgCfgmodel {
+						checkScope(pass, opt, route, f, ofn.Name())
+					}
+				}
+			}
+			checkType(pass, call, route, f, fn.Name())
+
+		case fn.Pkg().Path() == pkgCfgpath && (fn.Name() == "MustNewByParts" || fn.Name() == "NewByParts"):
+			route, ok := constParts(pass, call.Args)
+			if !ok {
+				return true
+			}
+			f, hasField := checkRoute(pass, kr, call, route)
+			if !hasField || len(stack) < 2 {
+				return true
+			}
+			if sel, ok := stack[len(stack)-2].(*ast.SelectorExpr); ok && sel.X == call {
+				checkScope(pass, sel, route, f, sel.Sel.Name)
+			}
+		}
+		return true
+	})
+	return nil, nil
+}
+
+// checkRoute reports malformed and unknown routes. Returns the Field if the
+// route has been defined in an element.Section.
+func checkRoute(pass *analysis.Pass, kr knownRoutes, call *ast.CallExpr, route string) (Field, bool) {
+	if strings.Count(route, "/") != 2 {
+		pass.Reportf(call.Pos(), "configuration route %q must contain three parts: section/group/field", route)
+		return Field{}, false
+	}
+	f, hasField, known := kr.lookup(route)
+	if hasField || known {
+		return f, hasField
+	}
+	if s := kr.suggest(route); s != "" {
+		pass.Reportf(call.Pos(), "unknown configuration route %q, did you mean %q?", route, s)
+	} else {
+		pass.Reportf(call.Pos(), "unknown configuration route %q", route)
+	}
+	return Field{}, false
+}
+
+// checkScope reports a bind method or a scope option which requests a scope
+// which the Field does not allow.
+func checkScope(pass *analysis.Pass, n ast.Node, route string, f Field, name string) {
+	s, ok := bindScopes[name]
+	if !ok || f.Scopes == 0 || f.Scopes.Has(s) {
+		return
+	}
+	pass.Reportf(n.Pos(), "configuration route %q does not allow the %s scope, allowed: %s", route, strings.ToLower(s.String()), f.Scopes)
+}
+
+// checkType reports cfgmodel constructors which cannot handle the type or
+// the default value of the Field.
+func checkType(pass *analysis.Pass, call *ast.CallExpr, route string, f Field, model string) {
+	switch {
+	case f.Type == element.TypeButton || f.Type == element.TypeLabel:
+		pass.Reportf(call.Pos(), "configuration route %q of type %s cannot store a value", route, f.Type)
+		return
+	case f.Type == element.TypeObscure && model != "NewObscure":
+		pass.Reportf(call.Pos(), "configuration route %q of type %s must be read with cfgmodel.NewObscure", route, f.Type)
+		return
+	case model == "NewObscure" && f.Type != 0 && f.Type != element.TypeObscure:
+		pass.Reportf(call.Pos(), "cfgmodel.NewObscure used for configuration route %q of type %s", route, f.Type)
+		return
+	case f.Type == element.TypeMultiselect && scalarModels[model]:
+		pass.Reportf(call.Pos(), "cfgmodel.%s cannot read the multiple values of configuration route %q of type %s, use a CSV model", model, route, f.Type)
+		return
+	}
+
+	if f.DefaultKind != "string" {
+		return
+	}
+	var err error
+	switch model {
+	case "NewBool":
+		_, err = strconv.ParseBool(f.Default)
+	case "NewInt":
+		_, err = strconv.Atoi(f.Default)
+	case "NewFloat64":
+		_, err = strconv.ParseFloat(f.Default, 64)
+	}
+	if err != nil {
+		pass.Reportf(call.Pos(), "default value %q of configuration route %q cannot be converted by cfgmodel.%s", f.Default, route, model)
+	}
+}
+
+// levenshtein calculates the edit distance between a and b.
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
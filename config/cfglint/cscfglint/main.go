@@ -0,0 +1,37 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Command cscfglint checks the configuration paths passed to the cfgmodel
+constructors and to cfgpath.MustNewByParts against the element.SectionSlice
+definitions and the known paths of Magento 2.
+
+Usage:
+	cscfglint [flags] packages
+
+Flags:
+	-magento accept the configuration paths of Magento 2 (default true)
+
+Run "cscfglint -help" for all flags.
+*/
+package main
+
+import (
+	"github.com/corestoreio/csfw/config/cfglint"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(cfglint.Analyzer)
+}
@@ -0,0 +1,54 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cfglint provides a static analyzer which checks the configuration
+// paths used in the source code.
+//
+// A typo in a route like cfgmodel.NewStr("web/unsecure/base_ulr") does not
+// fail at runtime, the model silently returns its default value. The Analyzer
+// finds all calls to the cfgmodel constructors and to cfgpath.MustNewByParts
+// and cfgpath.NewByParts with constant arguments and checks the route against
+// the element.SectionSlice definitions of the analyzed package and of all its
+// dependencies and against the known paths of Magento 2.
+//
+// The Analyzer reports:
+//
+//		- routes which do not consist of section/group/field
+//		- unknown routes, with a suggestion for a similar known route
+//		- the options cfgmodel.WithScopeWebsite and cfgmodel.WithScopeStore and
+//		  calls to Path.BindWebsite and Path.BindStore for a route whose
+//		  element.Field does not allow that scope
+//		- models which cannot read the element.Field: Buttons and Labels, an
+//		  Obscure field without cfgmodel.NewObscure, Multiselect fields read
+//		  by a scalar model and default values which the model cannot parse
+//
+// Only fields whose section, group and field IDs are constants get
+// collected. The command cscfglint runs the Analyzer:
+//
+//		go run github.com/corestoreio/csfw/config/cfglint/cscfglint ./...
+//
+// The flag -magento=false disallows the paths of Magento 2 which have not
+// been defined in an element.SectionSlice. These paths getting generated
+// from the templates in config/_pkgtpl and from the sections of the packages
+// backend, catalog/catconfig, directory and store/backendstore. Run go
+// generate after changing one of them.
+//
+// The package builds on the golang.org/x/tools/go/analysis framework which is
+// not part of the vendor directory. Fetch it before building the Analyzer:
+//
+//		go get golang.org/x/tools/go/analysis/...
+//
+// The x/tools module requires a recent Go release, see its go.mod for the
+// minimum version.
+package cfglint
@@ -0,0 +1,202 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build ignore
+
+// gen_routes generates the file magento.go from the element.Section, Group
+// and Field literals in the Go files matching the glob patterns passed as
+// arguments. Without arguments it parses the templates in config/_pkgtpl and
+// the structures of the packages which implement Magento 2 sections. Run it
+// via go generate.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const header = `// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gen_routes.go; DO NOT EDIT.
+
+package cfglint
+
+// magentoRoutes contains the configuration paths of Magento 2 as defined in
+// the system.xml files. See gen_routes.go for the sources.
+var magentoRoutes = [...]string{
+`
+
+// defaultPatterns relative to directory config/cfglint.
+var defaultPatterns = []string{
+	"../_pkgtpl/config_*.go",
+	"../../backend/config_backend.go",
+	"../../catalog/catconfig/structure.go",
+	"../../directory/config_structure.go",
+	"../../store/backendstore/structure.go",
+}
+
+func main() {
+	dst := flag.String("o", "magento.go", "Output file")
+	flag.Parse()
+
+	patterns := flag.Args()
+	if len(patterns) == 0 {
+		patterns = defaultPatterns
+	}
+	routes, err := parseRoutes(patterns...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%+v\n", err)
+		os.Exit(1)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(header)
+	for _, r := range routes {
+		fmt.Fprintf(&buf, "\t%q,\n", r)
+	}
+	buf.WriteString("}\n")
+
+	fmtd, err := format.Source(buf.Bytes())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%+v\n", err)
+		os.Exit(1)
+	}
+	if err := ioutil.WriteFile(*dst, fmtd, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "%+v\n", err)
+		os.Exit(1)
+	}
+}
+
+// parseRoutes extracts the sorted and unique routes from all files matching
+// the glob patterns.
+func parseRoutes(patterns ...string) ([]string, error) {
+	var files []string
+	for _, p := range patterns {
+		m, err := filepath.Glob(p)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, m...)
+	}
+	fset := token.NewFileSet()
+	uniq := make(map[string]bool)
+	for _, file := range files {
+		f, err := parser.ParseFile(fset, file, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		ast.Inspect(f, func(n ast.Node) bool {
+			if cl, ok := n.(*ast.CompositeLit); ok && isElement(cl, "Section") {
+				collect(uniq, cl, nil)
+				return false
+			}
+			return true
+		})
+	}
+	routes := make([]string, 0, len(uniq))
+	for r := range uniq {
+		routes = append(routes, r)
+	}
+	sort.Strings(routes)
+	return routes, nil
+}
+
+// collect walks down from a Section to its Fields. A ConfigPath of a Field
+// replaces its route. IDs with a leading underscore are placeholders of the
+// template generator and get skipped.
+func collect(uniq map[string]bool, cl *ast.CompositeLit, parts []string) {
+	id := stringField(cl, "ID")
+	if id == "" || id[0] == '_' {
+		return
+	}
+	parts = append(parts, id)
+	if isElement(cl, "Field") {
+		if cp := stringField(cl, "ConfigPath"); cp != "" {
+			uniq[cp] = true
+		} else if len(parts) == 3 {
+			uniq[strings.Join(parts, "/")] = true
+		}
+		return
+	}
+	for _, elt := range cl.Elts {
+		ast.Inspect(elt, func(n ast.Node) bool {
+			sub, ok := n.(*ast.CompositeLit)
+			if !ok || !(isElement(sub, "Group") || isElement(sub, "Field")) {
+				return true
+			}
+			collect(uniq, sub, parts)
+			return false
+		})
+	}
+}
+
+func isElement(cl *ast.CompositeLit, name string) bool {
+	se, ok := cl.Type.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	x, ok := se.X.(*ast.Ident)
+	return ok && x.Name == "element" && se.Sel.Name == name
+}
+
+func stringField(cl *ast.CompositeLit, key string) string {
+	for _, elt := range cl.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		if k, ok := kv.Key.(*ast.Ident); !ok || k.Name != key {
+			continue
+		}
+		return stringLit(kv.Value)
+	}
+	return ""
+}
+
+// stringLit returns the value of a string literal or of a call with a string
+// literal as the only argument, e.g. cfgpath.NewRoute(`enable`).
+func stringLit(e ast.Expr) string {
+	if ce, ok := e.(*ast.CallExpr); ok && len(ce.Args) == 1 {
+		e = ce.Args[0]
+	}
+	if bl, ok := e.(*ast.BasicLit); ok && bl.Kind == token.STRING {
+		if s, err := strconv.Unquote(bl.Value); err == nil {
+			return s
+		}
+	}
+	return ""
+}
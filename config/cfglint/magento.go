@@ -0,0 +1,1036 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gen_routes.go; DO NOT EDIT.
+
+package cfglint
+
+// magentoRoutes contains the configuration paths of Magento 2 as defined in
+// the system.xml files. See gen_routes.go for the sources.
+var magentoRoutes = [...]string{
+	"admin/captcha/always_for",
+	"admin/captcha/case_sensitive",
+	"admin/captcha/enable",
+	"admin/captcha/failed_attempts_ip",
+	"admin/captcha/failed_attempts_login",
+	"admin/captcha/font",
+	"admin/captcha/forms",
+	"admin/captcha/length",
+	"admin/captcha/mode",
+	"admin/captcha/shown_to_logged_in_user",
+	"admin/captcha/symbols",
+	"admin/captcha/timeout",
+	"admin/captcha/type",
+	"admin/dashboard/enable_charts",
+	"admin/emails/forgot_email_identity",
+	"admin/emails/forgot_email_template",
+	"admin/emails/password_reset_link_expiration_period",
+	"admin/emails/reset_password_template",
+	"admin/security/lockout_failures",
+	"admin/security/lockout_threshold",
+	"admin/security/password_is_forced",
+	"admin/security/password_lifetime",
+	"admin/security/session_lifetime",
+	"admin/security/use_case_sensitive_login",
+	"admin/security/use_form_key",
+	"admin/startup/menu_item_id",
+	"admin/url/custom",
+	"admin/url/custom_path",
+	"admin/url/use_custom",
+	"admin/url/use_custom_path",
+	"carriers/dhl/account",
+	"carriers/dhl/active",
+	"carriers/dhl/active_rma",
+	"carriers/dhl/content_type",
+	"carriers/dhl/debug",
+	"carriers/dhl/depth",
+	"carriers/dhl/divide_order_weight",
+	"carriers/dhl/doc_methods",
+	"carriers/dhl/free_method",
+	"carriers/dhl/free_method_doc",
+	"carriers/dhl/free_method_nondoc",
+	"carriers/dhl/free_shipping_enable",
+	"carriers/dhl/free_shipping_subtotal",
+	"carriers/dhl/gateway_url",
+	"carriers/dhl/handling_action",
+	"carriers/dhl/handling_fee",
+	"carriers/dhl/handling_type",
+	"carriers/dhl/height",
+	"carriers/dhl/id",
+	"carriers/dhl/is_online",
+	"carriers/dhl/model",
+	"carriers/dhl/nondoc_methods",
+	"carriers/dhl/password",
+	"carriers/dhl/ready_time",
+	"carriers/dhl/sallowspecific",
+	"carriers/dhl/shipment_days",
+	"carriers/dhl/showmethod",
+	"carriers/dhl/size",
+	"carriers/dhl/sort_order",
+	"carriers/dhl/specificcountry",
+	"carriers/dhl/specificerrmsg",
+	"carriers/dhl/title",
+	"carriers/dhl/unit_of_measure",
+	"carriers/dhl/width",
+	"carriers/fedex/account",
+	"carriers/fedex/active",
+	"carriers/fedex/active_rma",
+	"carriers/fedex/allowed_methods",
+	"carriers/fedex/cutoff_cost",
+	"carriers/fedex/debug",
+	"carriers/fedex/dropoff",
+	"carriers/fedex/free_method",
+	"carriers/fedex/free_shipping_enable",
+	"carriers/fedex/free_shipping_subtotal",
+	"carriers/fedex/handling",
+	"carriers/fedex/handling_action",
+	"carriers/fedex/handling_fee",
+	"carriers/fedex/handling_type",
+	"carriers/fedex/is_online",
+	"carriers/fedex/key",
+	"carriers/fedex/max_package_weight",
+	"carriers/fedex/meter_number",
+	"carriers/fedex/model",
+	"carriers/fedex/packaging",
+	"carriers/fedex/password",
+	"carriers/fedex/production_webservices_url",
+	"carriers/fedex/residence_delivery",
+	"carriers/fedex/sallowspecific",
+	"carriers/fedex/sandbox_mode",
+	"carriers/fedex/sandbox_webservices_url",
+	"carriers/fedex/shipment_requesttype",
+	"carriers/fedex/showmethod",
+	"carriers/fedex/smartpost_hubid",
+	"carriers/fedex/sort_order",
+	"carriers/fedex/specificcountry",
+	"carriers/fedex/specificerrmsg",
+	"carriers/fedex/title",
+	"carriers/fedex/unit_of_measure",
+	"carriers/flatrate/active",
+	"carriers/flatrate/handling_fee",
+	"carriers/flatrate/handling_type",
+	"carriers/flatrate/model",
+	"carriers/flatrate/name",
+	"carriers/flatrate/price",
+	"carriers/flatrate/sallowspecific",
+	"carriers/flatrate/showmethod",
+	"carriers/flatrate/sort_order",
+	"carriers/flatrate/specificcountry",
+	"carriers/flatrate/specificerrmsg",
+	"carriers/flatrate/title",
+	"carriers/flatrate/type",
+	"carriers/freeshipping/active",
+	"carriers/freeshipping/cutoff_cost",
+	"carriers/freeshipping/free_shipping_subtotal",
+	"carriers/freeshipping/model",
+	"carriers/freeshipping/name",
+	"carriers/freeshipping/sallowspecific",
+	"carriers/freeshipping/showmethod",
+	"carriers/freeshipping/sort_order",
+	"carriers/freeshipping/specificcountry",
+	"carriers/freeshipping/specificerrmsg",
+	"carriers/freeshipping/title",
+	"carriers/tablerate/active",
+	"carriers/tablerate/condition_name",
+	"carriers/tablerate/export",
+	"carriers/tablerate/handling_fee",
+	"carriers/tablerate/handling_type",
+	"carriers/tablerate/import",
+	"carriers/tablerate/include_virtual_price",
+	"carriers/tablerate/model",
+	"carriers/tablerate/name",
+	"carriers/tablerate/sallowspecific",
+	"carriers/tablerate/showmethod",
+	"carriers/tablerate/sort_order",
+	"carriers/tablerate/specificcountry",
+	"carriers/tablerate/specificerrmsg",
+	"carriers/tablerate/title",
+	"carriers/ups/access_license_number",
+	"carriers/ups/active",
+	"carriers/ups/active_rma",
+	"carriers/ups/allowed_methods",
+	"carriers/ups/container",
+	"carriers/ups/cutoff_cost",
+	"carriers/ups/debug",
+	"carriers/ups/dest_type",
+	"carriers/ups/free_method",
+	"carriers/ups/free_shipping_enable",
+	"carriers/ups/free_shipping_subtotal",
+	"carriers/ups/gateway_url",
+	"carriers/ups/gateway_xml_url",
+	"carriers/ups/handling",
+	"carriers/ups/handling_action",
+	"carriers/ups/handling_fee",
+	"carriers/ups/handling_type",
+	"carriers/ups/is_account_live",
+	"carriers/ups/is_online",
+	"carriers/ups/max_package_weight",
+	"carriers/ups/min_package_weight",
+	"carriers/ups/mode_xml",
+	"carriers/ups/model",
+	"carriers/ups/negotiated_active",
+	"carriers/ups/origin_shipment",
+	"carriers/ups/password",
+	"carriers/ups/pickup",
+	"carriers/ups/sallowspecific",
+	"carriers/ups/shipment_requesttype",
+	"carriers/ups/shipper_number",
+	"carriers/ups/showmethod",
+	"carriers/ups/sort_order",
+	"carriers/ups/specificcountry",
+	"carriers/ups/specificerrmsg",
+	"carriers/ups/title",
+	"carriers/ups/tracking_xml_url",
+	"carriers/ups/type",
+	"carriers/ups/unit_of_measure",
+	"carriers/ups/username",
+	"carriers/usps/active",
+	"carriers/usps/active_rma",
+	"carriers/usps/allowed_methods",
+	"carriers/usps/container",
+	"carriers/usps/cutoff_cost",
+	"carriers/usps/debug",
+	"carriers/usps/free_method",
+	"carriers/usps/free_shipping_enable",
+	"carriers/usps/free_shipping_subtotal",
+	"carriers/usps/gateway_secure_url",
+	"carriers/usps/gateway_url",
+	"carriers/usps/girth",
+	"carriers/usps/handling",
+	"carriers/usps/handling_action",
+	"carriers/usps/handling_fee",
+	"carriers/usps/handling_type",
+	"carriers/usps/height",
+	"carriers/usps/is_online",
+	"carriers/usps/isproduction",
+	"carriers/usps/length",
+	"carriers/usps/machinable",
+	"carriers/usps/max_package_weight",
+	"carriers/usps/methods",
+	"carriers/usps/mode",
+	"carriers/usps/model",
+	"carriers/usps/password",
+	"carriers/usps/sallowspecific",
+	"carriers/usps/shipment_requesttype",
+	"carriers/usps/showmethod",
+	"carriers/usps/size",
+	"carriers/usps/sort_order",
+	"carriers/usps/specificcountry",
+	"carriers/usps/specificerrmsg",
+	"carriers/usps/title",
+	"carriers/usps/userid",
+	"carriers/usps/width",
+	"catalog/custom_options/date_fields_order",
+	"catalog/custom_options/forbidden_extensions",
+	"catalog/custom_options/time_format",
+	"catalog/custom_options/use_calendar",
+	"catalog/custom_options/year_range",
+	"catalog/downloadable/content_disposition",
+	"catalog/downloadable/disable_guest_checkout",
+	"catalog/downloadable/downloads_number",
+	"catalog/downloadable/links_target_new_window",
+	"catalog/downloadable/links_title",
+	"catalog/downloadable/order_item_status",
+	"catalog/downloadable/samples_title",
+	"catalog/downloadable/shareable",
+	"catalog/fields_masks/meta_description",
+	"catalog/fields_masks/meta_keyword",
+	"catalog/fields_masks/meta_title",
+	"catalog/fields_masks/sku",
+	"catalog/frontend/default_sort_by",
+	"catalog/frontend/flat_catalog_category",
+	"catalog/frontend/flat_catalog_product",
+	"catalog/frontend/grid_per_page",
+	"catalog/frontend/grid_per_page_values",
+	"catalog/frontend/list_allow_all",
+	"catalog/frontend/list_mode",
+	"catalog/frontend/list_per_page",
+	"catalog/frontend/list_per_page_values",
+	"catalog/frontend/parse_url_directives",
+	"catalog/frontend/swatches_per_product",
+	"catalog/layered_navigation/display_product_count",
+	"catalog/layered_navigation/interval_division_limit",
+	"catalog/layered_navigation/one_price_interval",
+	"catalog/layered_navigation/price_range_calculation",
+	"catalog/layered_navigation/price_range_max_intervals",
+	"catalog/layered_navigation/price_range_step",
+	"catalog/navigation/max_depth",
+	"catalog/placeholder/placeholder",
+	"catalog/price/scope",
+	"catalog/product/default_tax_group",
+	"catalog/product/flat",
+	"catalog/product_video/play_if_base",
+	"catalog/product_video/show_related",
+	"catalog/product_video/video_auto_restart",
+	"catalog/product_video/youtube_api_key",
+	"catalog/productalert/allow_price",
+	"catalog/productalert/allow_stock",
+	"catalog/productalert/email_identity",
+	"catalog/productalert/email_price_template",
+	"catalog/productalert/email_stock_template",
+	"catalog/productalert_cron/error_email",
+	"catalog/productalert_cron/error_email_identity",
+	"catalog/productalert_cron/error_email_template",
+	"catalog/productalert_cron/frequency",
+	"catalog/productalert_cron/time",
+	"catalog/recently_products/compared_count",
+	"catalog/recently_products/scope",
+	"catalog/recently_products/viewed_count",
+	"catalog/review/allow_guest",
+	"catalog/search/engine",
+	"catalog/search/max_query_length",
+	"catalog/search/min_query_length",
+	"catalog/search/search_type",
+	"catalog/seo/category_canonical_tag",
+	"catalog/seo/category_url_suffix",
+	"catalog/seo/product_canonical_tag",
+	"catalog/seo/product_url_suffix",
+	"catalog/seo/product_use_categories",
+	"catalog/seo/save_rewrites_history",
+	"catalog/seo/search_terms",
+	"catalog/seo/title_separator",
+	"cataloginventory/item_options/auto_return",
+	"cataloginventory/item_options/backorders",
+	"cataloginventory/item_options/enable_qty_increments",
+	"cataloginventory/item_options/manage_stock",
+	"cataloginventory/item_options/max_sale_qty",
+	"cataloginventory/item_options/min_qty",
+	"cataloginventory/item_options/min_sale_qty",
+	"cataloginventory/item_options/notify_stock_qty",
+	"cataloginventory/item_options/qty_increments",
+	"cataloginventory/options/can_back_in_stock",
+	"cataloginventory/options/can_subtract",
+	"cataloginventory/options/display_product_stock_status",
+	"cataloginventory/options/show_out_of_stock",
+	"cataloginventory/options/stock_threshold_qty",
+	"checkout/cart/configurable_product_image",
+	"checkout/cart/delete_quote_after",
+	"checkout/cart/grouped_product_image",
+	"checkout/cart/redirect_to_cart",
+	"checkout/cart_link/use_qty",
+	"checkout/options/enable_agreements",
+	"checkout/options/guest_checkout",
+	"checkout/options/onepage_checkout_enabled",
+	"checkout/payment_failed/copy_method",
+	"checkout/payment_failed/copy_to",
+	"checkout/payment_failed/identity",
+	"checkout/payment_failed/receiver",
+	"checkout/payment_failed/template",
+	"checkout/sidebar/count",
+	"checkout/sidebar/display",
+	"cms/wysiwyg/enabled",
+	"cms/wysiwyg/use_static_urls_in_catalog",
+	"contact/contact/enabled",
+	"contact/email/email_template",
+	"contact/email/recipient_email",
+	"contact/email/sender_email_identity",
+	"currency/import/enabled",
+	"currency/import/error_email",
+	"currency/import/error_email_identity",
+	"currency/import/error_email_template",
+	"currency/import/frequency",
+	"currency/import/service",
+	"currency/import/time",
+	"currency/options/allow",
+	"currency/options/base",
+	"currency/options/default",
+	"currency/webservicex/timeout",
+	"customer/account_share/scope",
+	"customer/address/dob_show",
+	"customer/address/gender_show",
+	"customer/address/middlename_show",
+	"customer/address/prefix_options",
+	"customer/address/prefix_show",
+	"customer/address/street_lines",
+	"customer/address/suffix_options",
+	"customer/address/suffix_show",
+	"customer/address/taxvat_show",
+	"customer/address_templates/html",
+	"customer/address_templates/oneline",
+	"customer/address_templates/pdf",
+	"customer/address_templates/text",
+	"customer/captcha/always_for",
+	"customer/captcha/case_sensitive",
+	"customer/captcha/enable",
+	"customer/captcha/failed_attempts_ip",
+	"customer/captcha/failed_attempts_login",
+	"customer/captcha/font",
+	"customer/captcha/forms",
+	"customer/captcha/length",
+	"customer/captcha/mode",
+	"customer/captcha/shown_to_logged_in_user",
+	"customer/captcha/symbols",
+	"customer/captcha/timeout",
+	"customer/captcha/type",
+	"customer/create_account/auto_group_assign",
+	"customer/create_account/confirm",
+	"customer/create_account/default_group",
+	"customer/create_account/email_confirmation_template",
+	"customer/create_account/email_confirmed_template",
+	"customer/create_account/email_domain",
+	"customer/create_account/email_identity",
+	"customer/create_account/email_no_password_template",
+	"customer/create_account/email_template",
+	"customer/create_account/generate_human_friendly_id",
+	"customer/create_account/tax_calculation_address_type",
+	"customer/create_account/vat_frontend_visibility",
+	"customer/create_account/viv_disable_auto_group_assign_default",
+	"customer/create_account/viv_domestic_group",
+	"customer/create_account/viv_error_group",
+	"customer/create_account/viv_intra_union_group",
+	"customer/create_account/viv_invalid_group",
+	"customer/create_account/viv_on_each_transaction",
+	"customer/default/group",
+	"customer/online_customers/online_minutes_interval",
+	"customer/password/forgot_email_identity",
+	"customer/password/forgot_email_template",
+	"customer/password/remind_email_template",
+	"customer/password/reset_link_expiration_period",
+	"customer/password/reset_password_template",
+	"customer/startup/redirect_dashboard",
+	"design/email/footer_template",
+	"design/email/header_template",
+	"design/email/logo",
+	"design/email/logo_alt",
+	"design/email/logo_height",
+	"design/email/logo_width",
+	"design/footer/absolute_footer",
+	"design/footer/copyright",
+	"design/head/default_description",
+	"design/head/default_keywords",
+	"design/head/default_title",
+	"design/head/demonotice",
+	"design/head/includes",
+	"design/head/shortcut_icon",
+	"design/head/title_prefix",
+	"design/head/title_suffix",
+	"design/header/logo_alt",
+	"design/header/logo_height",
+	"design/header/logo_src",
+	"design/header/logo_width",
+	"design/header/welcome",
+	"design/invalid_caches/block_html",
+	"design/invalid_caches/layout",
+	"design/invalid_caches/translate",
+	"design/pagination/anchor_text_for_next",
+	"design/pagination/anchor_text_for_previous",
+	"design/pagination/pagination_frame",
+	"design/pagination/pagination_frame_skip",
+	"design/search_engine_robots/custom_instructions",
+	"design/search_engine_robots/default_custom_instructions",
+	"design/search_engine_robots/default_robots",
+	"design/search_engine_robots/reset_to_defaults",
+	"design/theme/theme_id",
+	"design/theme/ua_regexp",
+	"design/watermark/image",
+	"design/watermark/imageOpacity",
+	"design/watermark/position",
+	"design/watermark/size",
+	"dev/css/merge_css_files",
+	"dev/css/minify_files",
+	"dev/debug/template_hints_admin",
+	"dev/debug/template_hints_blocks",
+	"dev/debug/template_hints_storefront",
+	"dev/front_end_development_workflow/type",
+	"dev/grid/async_indexing",
+	"dev/image/default_adapter",
+	"dev/js/enable_js_bundling",
+	"dev/js/merge_files",
+	"dev/js/minify_files",
+	"dev/js/session_storage_key",
+	"dev/js/session_storage_logging",
+	"dev/js/translate_strategy",
+	"dev/restrict/allow_ips",
+	"dev/static/sign",
+	"dev/template/allow_symlink",
+	"dev/template/minify_html",
+	"dev/translate_inline/active",
+	"dev/translate_inline/active_admin",
+	"dev/translate_inline/invalid_caches",
+	"general/country/allow",
+	"general/country/default",
+	"general/country/destinations",
+	"general/country/eu_countries",
+	"general/country/optional_zip_countries",
+	"general/locale/code",
+	"general/locale/date_format_long",
+	"general/locale/date_format_medium",
+	"general/locale/date_format_short",
+	"general/locale/datetime_format_long",
+	"general/locale/datetime_format_medium",
+	"general/locale/datetime_format_short",
+	"general/locale/firstday",
+	"general/locale/language",
+	"general/locale/timezone",
+	"general/locale/weekend",
+	"general/locale/weight_unit",
+	"general/region/display_all",
+	"general/region/state_required",
+	"general/restriction/autocomplete_on_storefront",
+	"general/single_store_mode/enabled",
+	"general/store_information/city",
+	"general/store_information/country_id",
+	"general/store_information/hours",
+	"general/store_information/merchant_vat_number",
+	"general/store_information/name",
+	"general/store_information/phone",
+	"general/store_information/postcode",
+	"general/store_information/region_id",
+	"general/store_information/street_line1",
+	"general/store_information/street_line2",
+	"general/store_information/validate_vat_number",
+	"general/validator_data/input_types",
+	"google/adwords/active",
+	"google/adwords/conversion_color",
+	"google/adwords/conversion_format",
+	"google/adwords/conversion_id",
+	"google/adwords/conversion_img_src",
+	"google/adwords/conversion_js_src",
+	"google/adwords/conversion_label",
+	"google/adwords/conversion_language",
+	"google/adwords/conversion_value",
+	"google/adwords/conversion_value_type",
+	"google/adwords/language_convert",
+	"google/adwords/languages",
+	"google/analytics/account",
+	"google/analytics/active",
+	"google/analytics/experiments",
+	"google/optimizer/active",
+	"multishipping/options/checkout_multiple",
+	"multishipping/options/checkout_multiple_maximum_qty",
+	"newrelicreporting/cron/enable_cron",
+	"newrelicreporting/general/account_id",
+	"newrelicreporting/general/api",
+	"newrelicreporting/general/api_url",
+	"newrelicreporting/general/app_id",
+	"newrelicreporting/general/app_name",
+	"newrelicreporting/general/enable",
+	"newrelicreporting/general/insights_api_url",
+	"newrelicreporting/general/insights_insert_key",
+	"newsletter/sending/set_return_path",
+	"newsletter/subscription/allow_guest_subscribe",
+	"newsletter/subscription/confirm",
+	"newsletter/subscription/confirm_email_identity",
+	"newsletter/subscription/confirm_email_template",
+	"newsletter/subscription/success_email_identity",
+	"newsletter/subscription/success_email_template",
+	"newsletter/subscription/un_email_identity",
+	"newsletter/subscription/un_email_template",
+	"oauth/cleanup/cleanup_probability",
+	"oauth/cleanup/expiration_period",
+	"oauth/consumer/expiration_period",
+	"oauth/consumer/post_maxredirects",
+	"oauth/consumer/post_timeout",
+	"payment/authorizenet_directpost/active",
+	"payment/authorizenet_directpost/allowspecific",
+	"payment/authorizenet_directpost/ccfields",
+	"payment/authorizenet_directpost/cctypes",
+	"payment/authorizenet_directpost/cgi_url",
+	"payment/authorizenet_directpost/cgi_url_td",
+	"payment/authorizenet_directpost/cgi_url_td_test_mode",
+	"payment/authorizenet_directpost/cgi_url_test_mode",
+	"payment/authorizenet_directpost/create_order_before",
+	"payment/authorizenet_directpost/currency",
+	"payment/authorizenet_directpost/date_delim",
+	"payment/authorizenet_directpost/debug",
+	"payment/authorizenet_directpost/email_customer",
+	"payment/authorizenet_directpost/login",
+	"payment/authorizenet_directpost/max_order_total",
+	"payment/authorizenet_directpost/merchant_email",
+	"payment/authorizenet_directpost/min_order_total",
+	"payment/authorizenet_directpost/model",
+	"payment/authorizenet_directpost/order_status",
+	"payment/authorizenet_directpost/payment_action",
+	"payment/authorizenet_directpost/place_order_url",
+	"payment/authorizenet_directpost/sort_order",
+	"payment/authorizenet_directpost/specificcountry",
+	"payment/authorizenet_directpost/test",
+	"payment/authorizenet_directpost/title",
+	"payment/authorizenet_directpost/trans_key",
+	"payment/authorizenet_directpost/trans_md5",
+	"payment/authorizenet_directpost/useccv",
+	"payment/banktransfer/active",
+	"payment/banktransfer/allowspecific",
+	"payment/banktransfer/group",
+	"payment/banktransfer/instructions",
+	"payment/banktransfer/max_order_total",
+	"payment/banktransfer/min_order_total",
+	"payment/banktransfer/model",
+	"payment/banktransfer/order_status",
+	"payment/banktransfer/sort_order",
+	"payment/banktransfer/specificcountry",
+	"payment/banktransfer/title",
+	"payment/braintree/active",
+	"payment/braintree/allowspecific",
+	"payment/braintree/capture_action",
+	"payment/braintree/cctypes",
+	"payment/braintree/data_js",
+	"payment/braintree/duplicate_card",
+	"payment/braintree/enable_cc_detection",
+	"payment/braintree/environment",
+	"payment/braintree/fraudprotection",
+	"payment/braintree/masked_fields",
+	"payment/braintree/model",
+	"payment/braintree/order_status",
+	"payment/braintree/payment_action",
+	"payment/braintree/private_key",
+	"payment/braintree/public_key",
+	"payment/braintree/title",
+	"payment/braintree/usecache",
+	"payment/braintree/useccv",
+	"payment/braintree/verify_3dsecure",
+	"payment/braintree_paypal/active",
+	"payment/braintree_paypal/allowspecific",
+	"payment/braintree_paypal/dispaly_on_shopping_cart",
+	"payment/braintree_paypal/model",
+	"payment/braintree_paypal/order_status",
+	"payment/braintree_paypal/payment_action",
+	"payment/braintree_paypal/require_billing_address",
+	"payment/braintree_paypal/title",
+	"payment/braintreetwo/active",
+	"payment/braintreetwo/allowspecific",
+	"payment/braintreetwo/can_authorize",
+	"payment/braintreetwo/can_capture",
+	"payment/braintreetwo/can_capture_partial",
+	"payment/braintreetwo/can_use_checkout",
+	"payment/braintreetwo/can_use_internal",
+	"payment/braintreetwo/cctypes",
+	"payment/braintreetwo/cctypes_braintree_mapper",
+	"payment/braintreetwo/environment",
+	"payment/braintreetwo/is_gateway",
+	"payment/braintreetwo/masked_fields",
+	"payment/braintreetwo/model",
+	"payment/braintreetwo/order_status",
+	"payment/braintreetwo/paymentInfoKeys",
+	"payment/braintreetwo/payment_action",
+	"payment/braintreetwo/privateInfoKeys",
+	"payment/braintreetwo/private_key",
+	"payment/braintreetwo/public_key",
+	"payment/braintreetwo/sdk_url",
+	"payment/braintreetwo/title",
+	"payment/braintreetwo/useccv",
+	"payment/cashondelivery/active",
+	"payment/cashondelivery/allowspecific",
+	"payment/cashondelivery/group",
+	"payment/cashondelivery/instructions",
+	"payment/cashondelivery/max_order_total",
+	"payment/cashondelivery/min_order_total",
+	"payment/cashondelivery/model",
+	"payment/cashondelivery/order_status",
+	"payment/cashondelivery/sort_order",
+	"payment/cashondelivery/specificcountry",
+	"payment/cashondelivery/title",
+	"payment/checkmo/active",
+	"payment/checkmo/allowspecific",
+	"payment/checkmo/group",
+	"payment/checkmo/mailing_address",
+	"payment/checkmo/max_order_total",
+	"payment/checkmo/min_order_total",
+	"payment/checkmo/model",
+	"payment/checkmo/order_status",
+	"payment/checkmo/payable_to",
+	"payment/checkmo/sort_order",
+	"payment/checkmo/specificcountry",
+	"payment/checkmo/title",
+	"payment/free/active",
+	"payment/free/allowspecific",
+	"payment/free/group",
+	"payment/free/model",
+	"payment/free/order_status",
+	"payment/free/payment_action",
+	"payment/free/sort_order",
+	"payment/free/specificcountry",
+	"payment/free/title",
+	"payment/hosted_pro/display_ec",
+	"payment/hosted_pro/group",
+	"payment/hosted_pro/model",
+	"payment/hosted_pro/payment_action",
+	"payment/hosted_pro/title",
+	"payment/hosted_pro/verify_peer",
+	"payment/payflow_advanced/cgi_url",
+	"payment/payflow_advanced/cgi_url_test_mode",
+	"payment/payflow_advanced/csc_editable",
+	"payment/payflow_advanced/csc_required",
+	"payment/payflow_advanced/email_confirmation",
+	"payment/payflow_advanced/group",
+	"payment/payflow_advanced/model",
+	"payment/payflow_advanced/partner",
+	"payment/payflow_advanced/payment_action",
+	"payment/payflow_advanced/pwd",
+	"payment/payflow_advanced/title",
+	"payment/payflow_advanced/transaction_url",
+	"payment/payflow_advanced/transaction_url_test_mode",
+	"payment/payflow_advanced/url_method",
+	"payment/payflow_advanced/user",
+	"payment/payflow_advanced/vendor",
+	"payment/payflow_advanced/verbosity",
+	"payment/payflow_advanced/verify_peer",
+	"payment/payflow_express/group",
+	"payment/payflow_express/line_items_enabled",
+	"payment/payflow_express/model",
+	"payment/payflow_express/payment_action",
+	"payment/payflow_express/title",
+	"payment/payflow_express/verify_peer",
+	"payment/payflow_express/visible_on_cart",
+	"payment/payflow_express/visible_on_product",
+	"payment/payflow_express_bml/group",
+	"payment/payflow_express_bml/model",
+	"payment/payflow_express_bml/title",
+	"payment/payflow_link/cgi_url",
+	"payment/payflow_link/cgi_url_test_mode",
+	"payment/payflow_link/csc_editable",
+	"payment/payflow_link/csc_required",
+	"payment/payflow_link/email_confirmation",
+	"payment/payflow_link/group",
+	"payment/payflow_link/model",
+	"payment/payflow_link/partner",
+	"payment/payflow_link/payment_action",
+	"payment/payflow_link/pwd",
+	"payment/payflow_link/title",
+	"payment/payflow_link/transaction_url",
+	"payment/payflow_link/transaction_url_test_mode",
+	"payment/payflow_link/url_method",
+	"payment/payflow_link/user",
+	"payment/payflow_link/verbosity",
+	"payment/payflow_link/verify_peer",
+	"payment/payflowpro/avs_international",
+	"payment/payflowpro/avs_security_code",
+	"payment/payflowpro/avs_street",
+	"payment/payflowpro/avs_zip",
+	"payment/payflowpro/cc_year_length",
+	"payment/payflowpro/ccfields",
+	"payment/payflowpro/cctypes",
+	"payment/payflowpro/cgi_url",
+	"payment/payflowpro/cgi_url_test_mode",
+	"payment/payflowpro/date_delim",
+	"payment/payflowpro/group",
+	"payment/payflowpro/model",
+	"payment/payflowpro/payment_action",
+	"payment/payflowpro/place_order_url",
+	"payment/payflowpro/pwd",
+	"payment/payflowpro/tender",
+	"payment/payflowpro/title",
+	"payment/payflowpro/transaction_url",
+	"payment/payflowpro/transaction_url_test_mode",
+	"payment/payflowpro/useccv",
+	"payment/payflowpro/user",
+	"payment/payflowpro/verbosity",
+	"payment/payflowpro/verify_peer",
+	"payment/paypal_billing_agreement/active",
+	"payment/paypal_billing_agreement/allow_billing_agreement_wizard",
+	"payment/paypal_billing_agreement/group",
+	"payment/paypal_billing_agreement/model",
+	"payment/paypal_billing_agreement/title",
+	"payment/paypal_billing_agreement/verify_peer",
+	"payment/paypal_express/allow_ba_signup",
+	"payment/paypal_express/authorization_honor_period",
+	"payment/paypal_express/child_authorization_number",
+	"payment/paypal_express/group",
+	"payment/paypal_express/line_items_enabled",
+	"payment/paypal_express/model",
+	"payment/paypal_express/order_valid_period",
+	"payment/paypal_express/payment_action",
+	"payment/paypal_express/skip_order_review_step",
+	"payment/paypal_express/solution_type",
+	"payment/paypal_express/title",
+	"payment/paypal_express/verify_peer",
+	"payment/paypal_express/visible_on_cart",
+	"payment/paypal_express/visible_on_product",
+	"payment/paypal_express_bml/group",
+	"payment/paypal_express_bml/model",
+	"payment/paypal_express_bml/title",
+	"payment/purchaseorder/active",
+	"payment/purchaseorder/allowspecific",
+	"payment/purchaseorder/group",
+	"payment/purchaseorder/max_order_total",
+	"payment/purchaseorder/min_order_total",
+	"payment/purchaseorder/model",
+	"payment/purchaseorder/order_status",
+	"payment/purchaseorder/sort_order",
+	"payment/purchaseorder/specificcountry",
+	"payment/purchaseorder/title",
+	"payment/substitution/active",
+	"payment/substitution/allowspecific",
+	"payment/substitution/model",
+	"payment/vault/debug",
+	"payment/vault/model",
+	"payment/vault/vault_payment",
+	"paypal/fetch_reports/ftp_login",
+	"paypal/fetch_reports/ftp_password",
+	"paypal/fetch_reports/schedule",
+	"paypal/fetch_reports/time",
+	"paypal/general/merchant_country",
+	"paypal/style/logo",
+	"paypal/wpp/api_password",
+	"paypal/wpp/api_signature",
+	"paypal/wpp/api_username",
+	"paypal/wpp/button_flavor",
+	"paypal/wpuk/pwd",
+	"paypal/wpuk/user",
+	"persistent/options/enabled",
+	"persistent/options/lifetime",
+	"persistent/options/logout_clear",
+	"persistent/options/remember_default",
+	"persistent/options/remember_enabled",
+	"persistent/options/shopping_cart",
+	"promo/auto_generated_coupon_codes/dash",
+	"promo/auto_generated_coupon_codes/format",
+	"promo/auto_generated_coupon_codes/length",
+	"promo/auto_generated_coupon_codes/prefix",
+	"promo/auto_generated_coupon_codes/suffix",
+	"reports/dashboard/mtd_start",
+	"reports/dashboard/ytd_start",
+	"rss/catalog/category",
+	"rss/catalog/discounts",
+	"rss/catalog/new",
+	"rss/catalog/special",
+	"rss/config/active",
+	"rss/order/status",
+	"rss/wishlist/active",
+	"sales/dashboard/use_aggregated_data",
+	"sales/general/hide_customer_ip",
+	"sales/gift_messages/allow_items",
+	"sales/gift_messages/allow_order",
+	"sales/gift_options/allow_items",
+	"sales/gift_options/allow_order",
+	"sales/identity/address",
+	"sales/identity/logo",
+	"sales/identity/logo_html",
+	"sales/minimum_order/active",
+	"sales/minimum_order/amount",
+	"sales/minimum_order/description",
+	"sales/minimum_order/error_message",
+	"sales/minimum_order/multi_address",
+	"sales/minimum_order/multi_address_description",
+	"sales/minimum_order/multi_address_error_message",
+	"sales/minimum_order/tax_including",
+	"sales/msrp/display_price_type",
+	"sales/msrp/enabled",
+	"sales/msrp/explanation_message",
+	"sales/msrp/explanation_message_whats_this",
+	"sales/orders/delete_pending_after",
+	"sales/reorder/allow",
+	"sales/totals_sort/discount",
+	"sales/totals_sort/grand_total",
+	"sales/totals_sort/shipping",
+	"sales/totals_sort/subtotal",
+	"sales/totals_sort/tax",
+	"sales/totals_sort/weee",
+	"sales/totals_sort/weee_tax",
+	"sales_email/creditmemo/copy_method",
+	"sales_email/creditmemo/copy_to",
+	"sales_email/creditmemo/enabled",
+	"sales_email/creditmemo/guest_template",
+	"sales_email/creditmemo/identity",
+	"sales_email/creditmemo/template",
+	"sales_email/creditmemo_comment/copy_method",
+	"sales_email/creditmemo_comment/copy_to",
+	"sales_email/creditmemo_comment/enabled",
+	"sales_email/creditmemo_comment/guest_template",
+	"sales_email/creditmemo_comment/identity",
+	"sales_email/creditmemo_comment/template",
+	"sales_email/general/async_sending",
+	"sales_email/invoice/copy_method",
+	"sales_email/invoice/copy_to",
+	"sales_email/invoice/enabled",
+	"sales_email/invoice/guest_template",
+	"sales_email/invoice/identity",
+	"sales_email/invoice/template",
+	"sales_email/invoice_comment/copy_method",
+	"sales_email/invoice_comment/copy_to",
+	"sales_email/invoice_comment/enabled",
+	"sales_email/invoice_comment/guest_template",
+	"sales_email/invoice_comment/identity",
+	"sales_email/invoice_comment/template",
+	"sales_email/order/copy_method",
+	"sales_email/order/copy_to",
+	"sales_email/order/enabled",
+	"sales_email/order/guest_template",
+	"sales_email/order/identity",
+	"sales_email/order/template",
+	"sales_email/order_comment/copy_method",
+	"sales_email/order_comment/copy_to",
+	"sales_email/order_comment/enabled",
+	"sales_email/order_comment/guest_template",
+	"sales_email/order_comment/identity",
+	"sales_email/order_comment/template",
+	"sales_email/shipment/copy_method",
+	"sales_email/shipment/copy_to",
+	"sales_email/shipment/enabled",
+	"sales_email/shipment/guest_template",
+	"sales_email/shipment/identity",
+	"sales_email/shipment/template",
+	"sales_email/shipment_comment/copy_method",
+	"sales_email/shipment_comment/copy_to",
+	"sales_email/shipment_comment/enabled",
+	"sales_email/shipment_comment/guest_template",
+	"sales_email/shipment_comment/identity",
+	"sales_email/shipment_comment/template",
+	"sales_pdf/creditmemo/put_order_id",
+	"sales_pdf/invoice/put_order_id",
+	"sales_pdf/shipment/put_order_id",
+	"sendfriend/email/allow_guest",
+	"sendfriend/email/check_by",
+	"sendfriend/email/enabled",
+	"sendfriend/email/max_per_hour",
+	"sendfriend/email/max_recipients",
+	"sendfriend/email/template",
+	"shipping/origin/city",
+	"shipping/origin/country_id",
+	"shipping/origin/postcode",
+	"shipping/origin/region_id",
+	"shipping/origin/street_line1",
+	"shipping/origin/street_line2",
+	"shipping/shipping_policy/enable_shipping_policy",
+	"shipping/shipping_policy/shipping_policy_content",
+	"sitemap/category/changefreq",
+	"sitemap/category/priority",
+	"sitemap/file/valid_paths",
+	"sitemap/generate/enabled",
+	"sitemap/generate/error_email",
+	"sitemap/generate/error_email_identity",
+	"sitemap/generate/error_email_template",
+	"sitemap/generate/frequency",
+	"sitemap/generate/time",
+	"sitemap/limit/max_file_size",
+	"sitemap/limit/max_lines",
+	"sitemap/page/changefreq",
+	"sitemap/page/priority",
+	"sitemap/product/changefreq",
+	"sitemap/product/image_include",
+	"sitemap/product/priority",
+	"sitemap/search_engines/submission_robots",
+	"system/adminnotification/feed_url",
+	"system/adminnotification/frequency",
+	"system/adminnotification/last_update",
+	"system/adminnotification/popup_url",
+	"system/adminnotification/severity_icons_url",
+	"system/adminnotification/use_https",
+	"system/backup/enabled",
+	"system/backup/frequency",
+	"system/backup/maintenance",
+	"system/backup/time",
+	"system/backup/type",
+	"system/currency/installed",
+	"system/dashboard/enable_charts",
+	"system/emails/forgot_email_identity",
+	"system/emails/forgot_email_template",
+	"system/full_page_cache/caching_application",
+	"system/full_page_cache/default",
+	"system/full_page_cache/ttl",
+	"system/full_page_cache/varnish3",
+	"system/full_page_cache/varnish4",
+	"system/media_storage_configuration/allowed_resources",
+	"system/media_storage_configuration/configuration_update_time",
+	"system/media_storage_configuration/media_database",
+	"system/media_storage_configuration/media_storage",
+	"system/media_storage_configuration/synchronize",
+	"system/smtp/disable",
+	"system/smtp/host",
+	"system/smtp/port",
+	"system/smtp/return_path_email",
+	"system/smtp/set_return_path",
+	"tax/calculation/algorithm",
+	"tax/calculation/apply_after_discount",
+	"tax/calculation/apply_tax_on",
+	"tax/calculation/based_on",
+	"tax/calculation/cross_border_trade_enabled",
+	"tax/calculation/discount_tax",
+	"tax/calculation/price_includes_tax",
+	"tax/calculation/shipping_includes_tax",
+	"tax/cart_display/discount",
+	"tax/cart_display/full_summary",
+	"tax/cart_display/grandtotal",
+	"tax/cart_display/price",
+	"tax/cart_display/shipping",
+	"tax/cart_display/subtotal",
+	"tax/cart_display/zero_tax",
+	"tax/classes/default_customer_tax_class",
+	"tax/classes/default_product_tax_class",
+	"tax/classes/shipping_tax_class",
+	"tax/defaults/country",
+	"tax/defaults/postcode",
+	"tax/defaults/region",
+	"tax/display/shipping",
+	"tax/display/type",
+	"tax/notification/info_url",
+	"tax/sales_display/discount",
+	"tax/sales_display/full_summary",
+	"tax/sales_display/grandtotal",
+	"tax/sales_display/price",
+	"tax/sales_display/shipping",
+	"tax/sales_display/subtotal",
+	"tax/sales_display/zero_tax",
+	"tax/weee/apply_vat",
+	"tax/weee/display",
+	"tax/weee/display_email",
+	"tax/weee/display_list",
+	"tax/weee/display_sales",
+	"tax/weee/enable",
+	"tax/weee/include_in_subtotal",
+	"theme/customization/custom_css",
+	"trans_email/ident_custom1/email",
+	"trans_email/ident_custom1/name",
+	"trans_email/ident_custom2/email",
+	"trans_email/ident_custom2/name",
+	"trans_email/ident_general/email",
+	"trans_email/ident_general/name",
+	"trans_email/ident_sales/email",
+	"trans_email/ident_sales/name",
+	"trans_email/ident_support/email",
+	"trans_email/ident_support/name",
+	"web/browser_capabilities/cookies",
+	"web/browser_capabilities/javascript",
+	"web/browser_capabilities/local_storage",
+	"web/cookie/cookie_domain",
+	"web/cookie/cookie_httponly",
+	"web/cookie/cookie_lifetime",
+	"web/cookie/cookie_path",
+	"web/cookie/cookie_restriction",
+	"web/cookie/cookie_restriction_lifetime",
+	"web/default/cms_home_page",
+	"web/default/cms_no_cookies",
+	"web/default/cms_no_route",
+	"web/default/front",
+	"web/default/no_route",
+	"web/default/show_cms_breadcrumbs",
+	"web/secure/base_link_url",
+	"web/secure/base_media_url",
+	"web/secure/base_static_url",
+	"web/secure/base_url",
+	"web/secure/enable_hsts",
+	"web/secure/enable_upgrade_insecure",
+	"web/secure/offloader_header",
+	"web/secure/use_in_adminhtml",
+	"web/secure/use_in_frontend",
+	"web/seo/use_rewrites",
+	"web/session/use_frontend_sid",
+	"web/session/use_http_user_agent",
+	"web/session/use_http_via",
+	"web/session/use_http_x_forwarded_for",
+	"web/session/use_remote_addr",
+	"web/unsecure/base_link_url",
+	"web/unsecure/base_media_url",
+	"web/unsecure/base_static_url",
+	"web/unsecure/base_url",
+	"web/url/redirect_to_base",
+	"web/url/use_store",
+	"webapi/soap/charset",
+	"wishlist/email/email_identity",
+	"wishlist/email/email_template",
+	"wishlist/email/number_limit",
+	"wishlist/email/text_limit",
+	"wishlist/general/active",
+	"wishlist/wishlist_link/use_qty",
+}
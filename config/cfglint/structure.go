@@ -0,0 +1,242 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfglint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"strings"
+
+	"github.com/corestoreio/csfw/config/element"
+	"github.com/corestoreio/csfw/store/scope"
+	"golang.org/x/tools/go/analysis"
+)
+
+const (
+	pkgElement  = "github.com/corestoreio/csfw/config/element"
+	pkgCfgpath  = "github.com/corestoreio/csfw/config/cfgpath"
+	pkgCfgmodel = "github.com/corestoreio/csfw/config/cfgmodel"
+)
+
+// Field contains the statically known attributes of an element.Field.
+type Field struct {
+	// Scopes as defined in the Field. Zero if unknown.
+	Scopes scope.Perm
+	// Type as defined in the Field. Zero if unknown.
+	Type element.FieldType
+	// Default contains the constant default value of the Field and
+	// DefaultKind its kind: string, int, float or bool. Empty if the default
+	// is not a constant.
+	Default     string
+	DefaultKind string
+}
+
+// structureFact contains all fields of the element.SectionSlice definitions
+// of a package. Exported to all importing packages.
+type structureFact struct {
+	Fields map[string]Field
+}
+
+func (*structureFact) AFact() {}
+
+func (f *structureFact) String() string {
+	return fmt.Sprintf("%d fields", len(f.Fields))
+}
+
+// collectFields finds all element.Section composite literals and returns the
+// fields with their full route. Sections, groups or fields without a
+// constant ID are skipped.
+func collectFields(pass *analysis.Pass) map[string]Field {
+	fields := make(map[string]Field)
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			sLit, ok := n.(*ast.CompositeLit)
+			if !ok || !isNamed(pass.TypesInfo.TypeOf(sLit), pkgElement, "Section") {
+				return true
+			}
+			sID, ok := routeValue(pass, keyValue(sLit, "ID"))
+			if !ok {
+				return false
+			}
+			inspectLits(pass, sLit, "Group", func(gLit *ast.CompositeLit) {
+				gID, ok := routeValue(pass, keyValue(gLit, "ID"))
+				if !ok {
+					return
+				}
+				inspectLits(pass, gLit, "Field", func(fLit *ast.CompositeLit) {
+					route, ok := routeValue(pass, keyValue(fLit, "ConfigPath"))
+					if !ok {
+						fID, ok := routeValue(pass, keyValue(fLit, "ID"))
+						if !ok {
+							return
+						}
+						route = sID + "/" + gID + "/" + fID
+					}
+					fields[route] = newField(pass, fLit)
+				})
+			})
+			return false
+		})
+	}
+	return fields
+}
+
+func newField(pass *analysis.Pass, fLit *ast.CompositeLit) Field {
+	var f Field
+	if v := constValue(pass, keyValue(fLit, "Scopes")); v != nil && v.Kind() == constant.Int {
+		if u, ok := constant.Uint64Val(v); ok {
+			f.Scopes = scope.Perm(u)
+		}
+	}
+	if v := constValue(pass, keyValue(fLit, "Type")); v != nil && v.Kind() == constant.Int {
+		if u, ok := constant.Uint64Val(v); ok {
+			f.Type = element.FieldType(u)
+		}
+	}
+	if v := constValue(pass, keyValue(fLit, "Default")); v != nil {
+		switch v.Kind() {
+		case constant.String:
+			f.Default, f.DefaultKind = constant.StringVal(v), "string"
+		case constant.Int:
+			f.Default, f.DefaultKind = v.ExactString(), "int"
+		case constant.Float:
+			f.Default, f.DefaultKind = v.ExactString(), "float"
+		case constant.Bool:
+			f.Default, f.DefaultKind = v.ExactString(), "bool"
+		}
+	}
+	return f
+}
+
+// inspectLits calls fn for all composite literals of the element type name
+// below root.
+func inspectLits(pass *analysis.Pass, root ast.Node, name string, fn func(*ast.CompositeLit)) {
+	ast.Inspect(root, func(n ast.Node) bool {
+		lit, ok := n.(*ast.CompositeLit)
+		if !ok || lit == root || !isNamed(pass.TypesInfo.TypeOf(lit), pkgElement, name) {
+			return true
+		}
+		fn(lit)
+		return false
+	})
+}
+
+// keyValue returns the value expression of a key in a struct literal or nil.
+func keyValue(lit *ast.CompositeLit, key string) ast.Expr {
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		if id, ok := kv.Key.(*ast.Ident); ok && id.Name == key {
+			return kv.Value
+		}
+	}
+	return nil
+}
+
+// routeValue returns the constant route of an expression like
+// cfgpath.NewRoute("a/b") or of a constant string.
+func routeValue(pass *analysis.Pass, e ast.Expr) (string, bool) {
+	if e == nil {
+		return "", false
+	}
+	if s, ok := constString(pass, e); ok {
+		return s, true
+	}
+	call, ok := e.(*ast.CallExpr)
+	if !ok || !isFunc(pass, call, pkgCfgpath, "NewRoute") {
+		return "", false
+	}
+	return constParts(pass, call.Args)
+}
+
+// constParts joins the constant string arguments with a slash. Returns false
+// if an argument is not constant.
+func constParts(pass *analysis.Pass, args []ast.Expr) (string, bool) {
+	if len(args) == 0 {
+		return "", false
+	}
+	parts := make([]string, len(args))
+	for i, a := range args {
+		s, ok := constString(pass, a)
+		if !ok {
+			return "", false
+		}
+		parts[i] = s
+	}
+	return strings.Join(parts, "/"), true
+}
+
+func constValue(pass *analysis.Pass, e ast.Expr) constant.Value {
+	if e == nil {
+		return nil
+	}
+	return pass.TypesInfo.Types[e].Value
+}
+
+func constString(pass *analysis.Pass, e ast.Expr) (string, bool) {
+	v := constValue(pass, e)
+	if v == nil || v.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(v), true
+}
+
+// isNamed checks if t is the named type pkg.name.
+func isNamed(t types.Type, pkg, name string) bool {
+	nt, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := nt.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == pkg && obj.Name() == name
+}
+
+// callee returns the package level function of a call or nil.
+func callee(pass *analysis.Pass, call *ast.CallExpr) *types.Func {
+	var id *ast.Ident
+	switch fun := unparen(call.Fun).(type) {
+	case *ast.Ident:
+		id = fun
+	case *ast.SelectorExpr:
+		id = fun.Sel
+	default:
+		return nil
+	}
+	fn, ok := pass.TypesInfo.Uses[id].(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Type().(*types.Signature).Recv() != nil {
+		return nil
+	}
+	return fn
+}
+
+// unparen returns e with any enclosing parentheses stripped.
+func unparen(e ast.Expr) ast.Expr {
+	for {
+		p, ok := e.(*ast.ParenExpr)
+		if !ok {
+			return e
+		}
+		e = p.X
+	}
+}
+
+func isFunc(pass *analysis.Pass, call *ast.CallExpr, pkg, name string) bool {
+	fn := callee(pass, call)
+	return fn != nil && fn.Pkg().Path() == pkg && fn.Name() == name
+}
@@ -0,0 +1,46 @@
+package a
+
+import (
+	"github.com/corestoreio/csfw/config/cfgmodel"
+	"github.com/corestoreio/csfw/config/cfgpath"
+
+	_ "b"
+)
+
+const routeLimit = "shop/options/limit"
+
+var (
+	_ = cfgmodel.NewStr("web/unsecure/base_url")
+	_ = cfgmodel.NewStr("web/unsecure/base_ulr") // want `unknown configuration route "web/unsecure/base_ulr", did you mean "web/unsecure/base_url"\?`
+	_ = cfgmodel.NewStr("shop/options")          // want `configuration route "shop/options" must contain three parts: section/group/field`
+	_ = cfgmodel.NewStr("xyz/abc/def")           // want `unknown configuration route "xyz/abc/def"$`
+	_ = cfgmodel.NewStr("shop/legacy/url")
+	_ = cfgmodel.NewStr("shop/options/legacy") // want `unknown configuration route`
+
+	_ = cfgmodel.NewStr("payment/paypal_express/model")
+	_ = cfgmodel.NewStr("payment/paypal_express/cfgmodel") // want `unknown configuration route "payment/paypal_express/cfgmodel"`
+	_ = cfgmodel.NewStr("captcha/_value/fonts")            // want `unknown configuration route "captcha/_value/fonts"`
+
+	_ = cfgmodel.NewStr("shop/options/enabled", cfgmodel.WithScopeWebsite())
+	_ = cfgmodel.NewStr("shop/options/enabled", cfgmodel.WithScopeStore()) // want `configuration route "shop/options/enabled" does not allow the store scope, allowed: Default,Website`
+	_ = cfgmodel.NewBool("shop/options/enabled")                           // want `default value "yes" of configuration route "shop/options/enabled" cannot be converted by cfgmodel.NewBool`
+
+	_ = cfgmodel.NewObscure("shop/options/password", cfgmodel.WithScopeStore())
+	_ = cfgmodel.NewStr("shop/options/password")  // want `configuration route "shop/options/password" of type TypeObscure must be read with cfgmodel.NewObscure`
+	_ = cfgmodel.NewObscure("shop/options/limit") // want `cfgmodel.NewObscure used for configuration route "shop/options/limit" of type TypeText`
+	_ = cfgmodel.NewInt("shop/options/countries") // want `cfgmodel.NewInt cannot read the multiple values of configuration route "shop/options/countries" of type TypeMultiselect, use a CSV model`
+	_ = cfgmodel.NewStringCSV("shop/options/countries")
+	_ = cfgmodel.NewStr("shop/options/heading") // want `configuration route "shop/options/heading" of type TypeLabel cannot store a value`
+	_ = cfgmodel.NewInt(routeLimit)
+
+	_    = cfgpath.MustNewByParts("shop/options/limit")
+	_    = cfgpath.MustNewByParts("shop", "options", "enabled").BindWebsite(1)
+	_    = cfgpath.MustNewByParts("shop/options/limit").BindWebsite(1) // want `configuration route "shop/options/limit" does not allow the website scope, allowed: Default`
+	_    = cfgpath.MustNewByParts("shop/opsions/limit")                // want `unknown configuration route "shop/opsions/limit", did you mean "shop/options/limit"\?`
+	_, _ = cfgpath.NewByParts("shop/options/enabled")
+)
+
+func dynamic(route string) {
+	_ = cfgmodel.NewStr(route)
+	_ = cfgpath.MustNewByParts(route)
+}
@@ -0,0 +1,55 @@
+package b // want package:"6 fields"
+
+import (
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/config/element"
+	"github.com/corestoreio/csfw/store/scope"
+)
+
+const groupID = "options"
+
+func NewConfigStructure() element.SectionSlice {
+	return element.NewSectionSlice(
+		element.Section{
+			ID: cfgpath.NewRoute("shop"),
+			Groups: element.NewGroupSlice(
+				element.Group{
+					ID: cfgpath.NewRoute(groupID),
+					Fields: element.NewFieldSlice(
+						element.Field{
+							ID:      cfgpath.NewRoute("enabled"),
+							Type:    element.TypeSelect,
+							Scopes:  scope.PermWebsite,
+							Default: "yes",
+						},
+						element.Field{
+							ID:     cfgpath.NewRoute("password"),
+							Type:   element.TypeObscure,
+							Scopes: scope.PermStore,
+						},
+						element.Field{
+							ID:     cfgpath.NewRoute("countries"),
+							Type:   element.TypeMultiselect,
+							Scopes: scope.PermStore,
+						},
+						element.Field{
+							ID:   cfgpath.NewRoute("heading"),
+							Type: element.TypeLabel,
+						},
+						element.Field{
+							ID:      cfgpath.NewRoute("limit"),
+							Type:    element.TypeText,
+							Scopes:  scope.PermDefault,
+							Default: 10,
+						},
+						element.Field{
+							ID:         cfgpath.NewRoute("legacy"),
+							ConfigPath: "shop/legacy/url",
+							Type:       element.TypeText,
+						},
+					),
+				},
+			),
+		},
+	)
+}
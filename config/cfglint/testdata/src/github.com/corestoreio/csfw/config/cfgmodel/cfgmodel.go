@@ -0,0 +1,28 @@
+// Package cfgmodel is a stub for the tests of cfglint.
+package cfgmodel
+
+type Option func()
+
+func WithScopeWebsite() Option { return nil }
+
+func WithScopeStore() Option { return nil }
+
+type Str struct{}
+
+func NewStr(path string, opts ...Option) Str { return Str{} }
+
+type Bool struct{}
+
+func NewBool(path string, opts ...Option) Bool { return Bool{} }
+
+type Int struct{}
+
+func NewInt(path string, opts ...Option) Int { return Int{} }
+
+type Obscure struct{}
+
+func NewObscure(path string, opts ...Option) Obscure { return Obscure{} }
+
+type StringCSV struct{}
+
+func NewStringCSV(path string, opts ...Option) StringCSV { return StringCSV{} }
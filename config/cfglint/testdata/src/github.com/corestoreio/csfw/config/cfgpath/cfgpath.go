@@ -0,0 +1,16 @@
+// Package cfgpath is a stub for the tests of cfglint.
+package cfgpath
+
+type Route struct{ Chars []byte }
+
+func NewRoute(parts ...string) Route { return Route{} }
+
+type Path struct{ Route Route }
+
+func MustNewByParts(parts ...string) Path { return Path{} }
+
+func NewByParts(parts ...string) (Path, error) { return Path{}, nil }
+
+func (p Path) BindWebsite(id int64) Path { return p }
+
+func (p Path) BindStore(id int64) Path { return p }
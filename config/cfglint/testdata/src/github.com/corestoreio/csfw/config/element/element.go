@@ -0,0 +1,54 @@
+// Package element is a stub for the tests of cfglint.
+package element
+
+import (
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/store/scope"
+)
+
+type FieldType uint8
+
+const (
+	TypeButton FieldType = iota + 1
+	TypeCustom
+	TypeLabel
+	TypeHidden
+	TypeImage
+	TypeObscure
+	TypeMultiselect
+	TypeSelect
+	TypeText
+	TypeTextarea
+	TypeTime
+	TypeDuration
+)
+
+type Field struct {
+	ID         cfgpath.Route
+	ConfigPath string
+	Type       FieldType
+	Scopes     scope.Perm
+	Default    interface{}
+}
+
+type FieldSlice []Field
+
+func NewFieldSlice(fs ...Field) FieldSlice { return fs }
+
+type Group struct {
+	ID     cfgpath.Route
+	Fields FieldSlice
+}
+
+type GroupSlice []Group
+
+func NewGroupSlice(gs ...Group) GroupSlice { return gs }
+
+type Section struct {
+	ID     cfgpath.Route
+	Groups GroupSlice
+}
+
+type SectionSlice []Section
+
+func NewSectionSlice(ss ...Section) SectionSlice { return ss }
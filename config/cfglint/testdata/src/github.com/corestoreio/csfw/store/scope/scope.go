@@ -0,0 +1,20 @@
+// Package scope is a stub for the tests of cfglint.
+package scope
+
+type Type uint8
+
+const (
+	Absent Type = iota
+	Default
+	Website
+	Group
+	Store
+)
+
+type Perm uint16
+
+const PermStore Perm = 1<<Default | 1<<Website | 1<<Store
+
+const PermWebsite Perm = 1<<Default | 1<<Website
+
+const PermDefault Perm = 1 << Default
@@ -0,0 +1,45 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cfgmigrate translates configuration paths and values between the
+// core_config_data tables of Magento 1 and Magento 2.
+//
+// Between both versions some routes have been renamed, whole sections or
+// groups have been moved or removed and the encoding of some values has
+// changed, for example PHP serialized arrays became JSON in Magento 2.2. A
+// Mapper contains the Rules for these changes and works in both directions:
+//
+//		m := cfgmigrate.MustNewMapper(cfgmigrate.DefaultRules...)
+//		p2, v2, err := m.Map(cfgmigrate.M1ToM2, p1, v1)
+//
+// Routes without a matching Rule stay unchanged. A Rule with an empty target
+// route marks paths which do not exist anymore, resp. did not exist in
+// Magento 1, and Map returns a NotSupported error behaviour.
+//
+// The mapper can be used in two ways:
+//
+// A one-shot migration with Migrate copies all values from a source
+// config.Storager into a destination config.Storager. The command csconfig in
+// package config/cfgexport runs a migration on exported files:
+//
+//		csconfig migrate -to m2 m1_export.yaml m2_import.yaml
+//
+// A read-through adapter with NewStorage wraps a config.Storager which
+// contains the paths of one version and presents the paths of the other
+// version. An application with Magento 2 paths can so run against the
+// core_config_data table of a Magento 1 shop:
+//
+//		s := cfgmigrate.NewStorage(m, cfgmigrate.M1ToM2, ccd.MustNewDBStorage(db))
+//		srv := config.MustNewService(s)
+package cfgmigrate
@@ -0,0 +1,200 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgmigrate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// Direction of a migration.
+type Direction uint8
+
+// Supported directions of a migration.
+const (
+	M1ToM2 Direction = iota + 1
+	M2ToM1
+)
+
+// Reverse returns the opposite direction.
+func (d Direction) Reverse() Direction {
+	if d == M1ToM2 {
+		return M2ToM1
+	}
+	return M1ToM2
+}
+
+// String returns M1ToM2 or M2ToM1.
+func (d Direction) String() string {
+	switch d {
+	case M1ToM2:
+		return "M1ToM2"
+	case M2ToM1:
+		return "M2ToM1"
+	}
+	return fmt.Sprintf("Direction(%d)", d)
+}
+
+// Converter changes the encoding of a value. A Converter must accept nil.
+type Converter func(v interface{}) (interface{}, error)
+
+// Rule describes the change of one route between Magento 1 and Magento 2. A
+// route can contain the full path section/group/field or only the section or
+// section/group, in which case all fields below it get mapped.
+type Rule struct {
+	// M1 route in Magento 1. Empty if the route has been added in Magento 2.
+	M1 string
+	// M2 route in Magento 2. Empty if the route has been removed in Magento
+	// 2.
+	M2 string
+	// ToM2 optional Converter for the values from M1 to M2.
+	ToM2 Converter
+	// ToM1 optional Converter for the values from M2 to M1.
+	ToM1 Converter
+}
+
+// Mapper translates paths and values with a set of Rules. Mapper is safe for
+// concurrent use.
+type Mapper struct {
+	m1 map[string]*Rule
+	m2 map[string]*Rule
+}
+
+// NewMapper creates a new Mapper. Returns a NotValid error behaviour if a
+// Rule has no route at all or an AlreadyExists error behaviour if a route
+// has been defined twice.
+func NewMapper(rules ...Rule) (*Mapper, error) {
+	m := &Mapper{
+		m1: make(map[string]*Rule, len(rules)),
+		m2: make(map[string]*Rule, len(rules)),
+	}
+	for i := range rules {
+		r := &rules[i]
+		if r.M1 == "" && r.M2 == "" {
+			return nil, errors.NewNotValidf("[cfgmigrate] Rule %d: M1 and M2 routes are empty", i)
+		}
+		if err := addRule(m.m1, r.M1, r); err != nil {
+			return nil, errors.Wrapf(err, "[cfgmigrate] Rule %d", i)
+		}
+		if err := addRule(m.m2, r.M2, r); err != nil {
+			return nil, errors.Wrapf(err, "[cfgmigrate] Rule %d", i)
+		}
+	}
+	return m, nil
+}
+
+// MustNewMapper same as NewMapper but panics on error.
+func MustNewMapper(rules ...Rule) *Mapper {
+	m, err := NewMapper(rules...)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func addRule(rules map[string]*Rule, route string, r *Rule) error {
+	if route == "" {
+		return nil
+	}
+	if strings.Count(route, string(cfgpath.Separator)) > cfgpath.Levels-1 {
+		return errors.NewNotValidf("[cfgmigrate] Route %q has too many parts", route)
+	}
+	if _, ok := rules[route]; ok {
+		return errors.NewAlreadyExistsf("[cfgmigrate] Route %q already exists", route)
+	}
+	rules[route] = r
+	return nil
+}
+
+// find returns the Rule which matches the route and the route prefix of the
+// rule. Searches first the full route, then section/group and the section.
+func (m *Mapper) find(d Direction, route string) (*Rule, string) {
+	rules := m.m1
+	if d == M2ToM1 {
+		rules = m.m2
+	}
+	for prefix := route; prefix != ""; {
+		if r, ok := rules[prefix]; ok {
+			return r, prefix
+		}
+		pos := strings.LastIndexByte(prefix, cfgpath.Separator)
+		if pos < 0 {
+			break
+		}
+		prefix = prefix[:pos]
+	}
+	return nil, ""
+}
+
+// Path translates the route of a path and keeps its scope. Paths without a
+// Rule are returned unchanged. Returns a NotSupported error behaviour if the
+// path does not exist in the target version.
+func (m *Mapper) Path(d Direction, p cfgpath.Path) (cfgpath.Path, error) {
+	route := p.Route.String()
+	r, prefix := m.find(d, route)
+	if r == nil {
+		return p, nil
+	}
+	target := r.M2
+	if d == M2ToM1 {
+		target = r.M1
+	}
+	if target == "" {
+		return cfgpath.Path{}, errors.NewNotSupportedf("[cfgmigrate] Route %q does not exist in %s", route, d)
+	}
+	np, err := cfgpath.New(cfgpath.NewRoute(target + route[len(prefix):]))
+	if err != nil {
+		return cfgpath.Path{}, errors.Wrapf(err, "[cfgmigrate] Mapper.Path Route %q", route)
+	}
+	return np.Bind(p.ScopeID), nil
+}
+
+// Value converts a value of the source path p into the encoding of the
+// target version. Values without a Converter are returned unchanged.
+func (m *Mapper) Value(d Direction, p cfgpath.Path, v interface{}) (interface{}, error) {
+	r, _ := m.find(d, p.Route.String())
+	if r == nil {
+		return v, nil
+	}
+	c := r.ToM2
+	if d == M2ToM1 {
+		c = r.ToM1
+	}
+	if c == nil {
+		return v, nil
+	}
+	cv, err := c(v)
+	if err != nil {
+		return nil, errors.Wrapf(err, "[cfgmigrate] Mapper.Value %s Path %q", d, p)
+	}
+	return cv, nil
+}
+
+// Map translates the path and converts the value. The path and the value
+// belong to the source version of the direction.
+func (m *Mapper) Map(d Direction, p cfgpath.Path, v interface{}) (cfgpath.Path, interface{}, error) {
+	np, err := m.Path(d, p)
+	if err != nil {
+		return cfgpath.Path{}, nil, errors.Wrap(err, "[cfgmigrate] Mapper.Map")
+	}
+	nv, err := m.Value(d, p, v)
+	if err != nil {
+		return cfgpath.Path{}, nil, errors.Wrap(err, "[cfgmigrate] Mapper.Map")
+	}
+	return np, nv, nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgmigrate_test
+
+import (
+	"testing"
+
+	"github.com/corestoreio/csfw/config/cfgmigrate"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+var testRules = []cfgmigrate.Rule{
+	{M1: "admin/security/session_cookie_lifetime", M2: "admin/security/session_lifetime"},
+	{M1: "old_section", M2: "new_section"},
+	{M1: "payment/old_group", M2: "payment/new_group"},
+	{M1: "design/package"},
+	{M2: "design/theme/theme_id"},
+	{M1: "design/theme/ua_regexp", M2: "design/theme/ua_regexp", ToM2: cfgmigrate.SerializedToJSON, ToM1: cfgmigrate.JSONToSerialized},
+}
+
+func TestMapperPath(t *testing.T) {
+	m := cfgmigrate.MustNewMapper(testRules...)
+
+	tests := []struct {
+		d       cfgmigrate.Direction
+		have    cfgpath.Path
+		want    string
+		wantErr errors.BehaviourFunc
+	}{
+		{cfgmigrate.M1ToM2, cfgpath.MustNewByParts("admin/security/session_cookie_lifetime"), "default/0/admin/security/session_lifetime", nil},
+		{cfgmigrate.M2ToM1, cfgpath.MustNewByParts("admin/security/session_lifetime").BindWebsite(2), "websites/2/admin/security/session_cookie_lifetime", nil},
+		{cfgmigrate.M1ToM2, cfgpath.MustNewByParts("old_section/group/field").BindStore(3), "stores/3/new_section/group/field", nil},
+		{cfgmigrate.M2ToM1, cfgpath.MustNewByParts("new_section/group/field"), "default/0/old_section/group/field", nil},
+		{cfgmigrate.M1ToM2, cfgpath.MustNewByParts("payment/old_group/active"), "default/0/payment/new_group/active", nil},
+		{cfgmigrate.M1ToM2, cfgpath.MustNewByParts("payment/other_group/active"), "default/0/payment/other_group/active", nil},
+		{cfgmigrate.M1ToM2, cfgpath.MustNewByParts("web/unsecure/base_url"), "default/0/web/unsecure/base_url", nil},
+		{cfgmigrate.M1ToM2, cfgpath.MustNewByParts("design/package/name"), "", errors.IsNotSupported},
+		{cfgmigrate.M2ToM1, cfgpath.MustNewByParts("design/theme/theme_id"), "", errors.IsNotSupported},
+		{cfgmigrate.M1ToM2, cfgpath.MustNewByParts("design/theme/ua_regexp"), "default/0/design/theme/ua_regexp", nil},
+	}
+	for i, test := range tests {
+		have, err := m.Path(test.d, test.have)
+		if test.wantErr != nil {
+			assert.True(t, test.wantErr(err), "Index %d => %+v", i, err)
+			continue
+		}
+		assert.NoError(t, err, "Index %d", i)
+		assert.Exactly(t, test.want, have.String(), "Index %d", i)
+	}
+}
+
+func TestMapperMap(t *testing.T) {
+	m := cfgmigrate.MustNewMapper(testRules...)
+
+	p, v, err := m.Map(cfgmigrate.M1ToM2, cfgpath.MustNewByParts("design/theme/ua_regexp").BindStore(1), `a:1:{s:4:"_123";a:2:{s:6:"regexp";s:6:"iPhone";s:5:"value";s:6:"mobile";}}`)
+	assert.NoError(t, err)
+	assert.Exactly(t, "stores/1/design/theme/ua_regexp", p.String())
+	assert.Exactly(t, `{"_123":{"regexp":"iPhone","value":"mobile"}}`, v)
+
+	p, v, err = m.Map(cfgmigrate.M2ToM1, cfgpath.MustNewByParts("admin/security/session_lifetime"), "900")
+	assert.NoError(t, err)
+	assert.Exactly(t, "default/0/admin/security/session_cookie_lifetime", p.String())
+	assert.Exactly(t, "900", v)
+
+	_, _, err = m.Map(cfgmigrate.M1ToM2, cfgpath.MustNewByParts("design/theme/ua_regexp"), `a:1:{s:4:"_123"`)
+	assert.True(t, errors.IsNotValid(err), "%+v", err)
+}
+
+func TestNewMapperErrors(t *testing.T) {
+	_, err := cfgmigrate.NewMapper(cfgmigrate.Rule{})
+	assert.True(t, errors.IsNotValid(err), "%+v", err)
+
+	_, err = cfgmigrate.NewMapper(cfgmigrate.Rule{M1: "a/b/c/d", M2: "a/b/c"})
+	assert.True(t, errors.IsNotValid(err), "%+v", err)
+
+	_, err = cfgmigrate.NewMapper(
+		cfgmigrate.Rule{M1: "a/b/c", M2: "a/b/d"},
+		cfgmigrate.Rule{M1: "a/b/e", M2: "a/b/d"},
+	)
+	assert.True(t, errors.IsAlreadyExists(err), "%+v", err)
+
+	_, err = cfgmigrate.NewMapper(cfgmigrate.DefaultRules...)
+	assert.NoError(t, err)
+}
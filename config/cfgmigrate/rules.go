@@ -0,0 +1,34 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgmigrate
+
+// DefaultRules contains a selection of the changes between Magento 1.9 and
+// Magento 2.2 in the core_config_data table. Append your own Rules for third
+// party modules.
+var DefaultRules = []Rule{
+	// serialized arrays are stored as JSON since Magento 2.2
+	{M1: "design/theme/ua_regexp", M2: "design/theme/ua_regexp", ToM2: SerializedToJSON, ToM1: JSONToSerialized},
+	{M1: "cataloginventory/item_options/min_sale_qty", M2: "cataloginventory/item_options/min_sale_qty", ToM2: SerializedToJSON, ToM1: JSONToSerialized},
+	{M1: "currency/options/customsymbol", M2: "currency/options/customsymbol", ToM2: SerializedToJSON, ToM1: JSONToSerialized},
+
+	// renamed routes
+	{M1: "admin/security/session_cookie_lifetime", M2: "admin/security/session_lifetime"},
+
+	// the design packages and themes of Magento 1 have been replaced by the
+	// theme ID
+	{M1: "design/package"},
+	{M1: "design/theme"},
+	{M2: "design/theme/theme_id"},
+}
@@ -0,0 +1,328 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgmigrate
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// SerializedToJSON converts a PHP serialized array into JSON. Lists with the
+// keys 0 to n-1 become JSON arrays, all other arrays become JSON objects
+// with the same order of the keys. Values which are not a serialized array,
+// like an empty string or a plain number, are returned unchanged. PHP objects
+// are not supported. The argument must be a string or a byte slice.
+func SerializedToJSON(v interface{}) (interface{}, error) {
+	s, ok, err := stringValue(v)
+	if !ok || err != nil || !strings.HasPrefix(s, "a:") {
+		return v, err
+	}
+	d := &phpDecoder{s: s}
+	pv, err := d.value()
+	if err != nil {
+		return nil, errors.Wrap(err, "[cfgmigrate] SerializedToJSON")
+	}
+	if d.pos != len(s) {
+		return nil, errors.NewNotValidf("[cfgmigrate] SerializedToJSON unexpected data at position %d", d.pos)
+	}
+	var buf bytes.Buffer
+	if err := writeJSON(&buf, pv); err != nil {
+		return nil, errors.Wrap(err, "[cfgmigrate] SerializedToJSON")
+	}
+	return buf.String(), nil
+}
+
+// JSONToSerialized converts a JSON object or a JSON array into a PHP
+// serialized array. The order of the keys will be preserved. Values which
+// are not a JSON object or array are returned unchanged. The argument must be
+// a string or a byte slice.
+func JSONToSerialized(v interface{}) (interface{}, error) {
+	s, ok, err := stringValue(v)
+	if !ok || err != nil || !(strings.HasPrefix(s, "{") || strings.HasPrefix(s, "[")) {
+		return v, err
+	}
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	var buf bytes.Buffer
+	if err := writeSerialized(&buf, dec); err != nil {
+		return nil, errors.NewNotValidf("[cfgmigrate] JSONToSerialized: %s", err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.NewNotValidf("[cfgmigrate] JSONToSerialized unexpected data after the JSON value")
+	}
+	return buf.String(), nil
+}
+
+func stringValue(v interface{}) (string, bool, error) {
+	switch vt := v.(type) {
+	case nil:
+		return "", false, nil
+	case string:
+		return vt, true, nil
+	case []byte:
+		return string(vt), true, nil
+	}
+	return "", false, errors.NewNotValidf("[cfgmigrate] Unsupported type %T", v)
+}
+
+// phpArray an ordered PHP array. Keys contain the key as a string and list
+// reports whether the keys are the integers 0 to n-1.
+type phpArray struct {
+	keys []string
+	vals []interface{}
+	list bool
+}
+
+// phpDecoder parses the output of the PHP function serialize. Numbers are
+// returned as json.Number.
+type phpDecoder struct {
+	s   string
+	pos int
+}
+
+func (d *phpDecoder) errorf(format string, args ...interface{}) error {
+	return errors.NewNotValidf("[cfgmigrate] Position %d: "+format, append([]interface{}{d.pos}, args...)...)
+}
+
+// until returns the data up to the delimiter and moves behind the delimiter.
+func (d *phpDecoder) until(delim byte) (string, error) {
+	i := strings.IndexByte(d.s[d.pos:], delim)
+	if i < 0 {
+		return "", d.errorf("missing %q", delim)
+	}
+	v := d.s[d.pos : d.pos+i]
+	d.pos += i + 1
+	return v, nil
+}
+
+func (d *phpDecoder) expect(s string) error {
+	if !strings.HasPrefix(d.s[d.pos:], s) {
+		return d.errorf("expecting %q", s)
+	}
+	d.pos += len(s)
+	return nil
+}
+
+func (d *phpDecoder) value() (interface{}, error) {
+	if d.pos+1 >= len(d.s) {
+		return nil, d.errorf("unexpected end of data")
+	}
+	typ := d.s[d.pos]
+	if typ == 'N' {
+		return nil, d.expect("N;")
+	}
+	if d.s[d.pos+1] != ':' {
+		return nil, d.errorf("expecting ':'")
+	}
+	d.pos += 2
+
+	switch typ {
+	case 'b':
+		v, err := d.until(';')
+		if err != nil {
+			return nil, err
+		}
+		return v == "1", nil
+	case 'i', 'd':
+		v, err := d.until(';')
+		if err != nil {
+			return nil, err
+		}
+		if _, err := strconv.ParseFloat(v, 64); err != nil {
+			return nil, d.errorf("invalid number %q", v)
+		}
+		return json.Number(v), nil
+	case 's':
+		return d.string()
+	case 'a':
+		return d.array()
+	}
+	return nil, errors.NewNotSupportedf("[cfgmigrate] Position %d: unsupported type %q", d.pos-2, typ)
+}
+
+func (d *phpDecoder) string() (string, error) {
+	ls, err := d.until(':')
+	if err != nil {
+		return "", err
+	}
+	l, err := strconv.Atoi(ls)
+	if err != nil || l < 0 || d.pos+l+3 > len(d.s) {
+		return "", d.errorf("invalid string length %q", ls)
+	}
+	if err := d.expect(`"`); err != nil {
+		return "", err
+	}
+	v := d.s[d.pos : d.pos+l]
+	d.pos += l
+	return v, d.expect(`";`)
+}
+
+func (d *phpDecoder) array() (*phpArray, error) {
+	ns, err := d.until(':')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(ns)
+	if err != nil || n < 0 {
+		return nil, d.errorf("invalid array length %q", ns)
+	}
+	if err := d.expect("{"); err != nil {
+		return nil, err
+	}
+	a := &phpArray{
+		list: true,
+	}
+	for i := 0; i < n; i++ {
+		k, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		var key string
+		switch kt := k.(type) {
+		case json.Number:
+			key = kt.String()
+		case string:
+			key = kt
+			a.list = false
+		default:
+			return nil, d.errorf("invalid array key type %T", k)
+		}
+		if key != strconv.Itoa(i) {
+			a.list = false
+		}
+		v, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		a.keys = append(a.keys, key)
+		a.vals = append(a.vals, v)
+	}
+	return a, d.expect("}")
+}
+
+func writeJSON(buf *bytes.Buffer, v interface{}) error {
+	switch vt := v.(type) {
+	case *phpArray:
+		if vt.list {
+			buf.WriteByte('[')
+		} else {
+			buf.WriteByte('{')
+		}
+		for i, val := range vt.vals {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if !vt.list {
+				if err := writeJSON(buf, vt.keys[i]); err != nil {
+					return err
+				}
+				buf.WriteByte(':')
+			}
+			if err := writeJSON(buf, val); err != nil {
+				return err
+			}
+		}
+		if vt.list {
+			buf.WriteByte(']')
+		} else {
+			buf.WriteByte('}')
+		}
+		return nil
+	}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return errors.Wrap(err, "[cfgmigrate] json.Encode")
+	}
+	buf.Truncate(buf.Len() - 1) // remove the new line of Encode
+	return nil
+}
+
+func writeSerializedString(buf *bytes.Buffer, s string) {
+	buf.WriteString("s:")
+	buf.WriteString(strconv.Itoa(len(s)))
+	buf.WriteString(`:"`)
+	buf.WriteString(s)
+	buf.WriteString(`";`)
+}
+
+// writeSerializedKey writes integer keys as PHP does.
+func writeSerializedKey(buf *bytes.Buffer, key string) {
+	if i, err := strconv.ParseInt(key, 10, 64); err == nil && strconv.FormatInt(i, 10) == key {
+		buf.WriteString("i:")
+		buf.WriteString(key)
+		buf.WriteByte(';')
+		return
+	}
+	writeSerializedString(buf, key)
+}
+
+func writeSerialized(buf *bytes.Buffer, dec *json.Decoder) error {
+	t, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch tt := t.(type) {
+	case nil:
+		buf.WriteString("N;")
+	case bool:
+		if tt {
+			buf.WriteString("b:1;")
+		} else {
+			buf.WriteString("b:0;")
+		}
+	case json.Number:
+		if _, err := tt.Int64(); err == nil {
+			buf.WriteString("i:")
+		} else {
+			buf.WriteString("d:")
+		}
+		buf.WriteString(tt.String())
+		buf.WriteByte(';')
+	case string:
+		writeSerializedString(buf, tt)
+	case json.Delim:
+		var elems bytes.Buffer
+		n := 0
+		for ; dec.More(); n++ {
+			if tt == '{' {
+				k, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				writeSerializedKey(&elems, k.(string))
+			} else {
+				writeSerializedKey(&elems, strconv.Itoa(n))
+			}
+			if err := writeSerialized(&elems, dec); err != nil {
+				return err
+			}
+		}
+		if _, err := dec.Token(); err != nil { // closing delimiter
+			return err
+		}
+		buf.WriteString("a:")
+		buf.WriteString(strconv.Itoa(n))
+		buf.WriteString(":{")
+		buf.Write(elems.Bytes())
+		buf.WriteByte('}')
+	}
+	return nil
+}
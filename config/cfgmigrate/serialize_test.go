@@ -0,0 +1,79 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgmigrate_test
+
+import (
+	"testing"
+
+	"github.com/corestoreio/csfw/config/cfgmigrate"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSerializedJSON(t *testing.T) {
+	tests := []struct {
+		serialized string
+		json       string
+	}{
+		{`a:0:{}`, `[]`},
+		{`a:2:{i:0;s:1:"a";i:1;s:2:"bc";}`, `["a","bc"]`},
+		{`a:2:{i:1;s:1:"a";i:2;s:1:"b";}`, `{"1":"a","2":"b"}`},
+		{`a:3:{s:1:"z";i:-5;s:1:"a";d:1.5;s:1:"m";b:1;}`, `{"z":-5,"a":1.5,"m":true}`},
+		{`a:2:{s:4:"null";N;s:5:"false";b:0;}`, `{"null":null,"false":false}`},
+		{`a:1:{s:4:"html";s:12:"<a href="/">";}`, `{"html":"<a href=\"/\">"}`},
+		{`a:1:{s:4:"ümm";s:2:"ä";}`, `{"ümm":"ä"}`},
+		{`a:1:{s:4:"_123";a:2:{s:6:"regexp";s:6:"iPhone";s:5:"value";s:6:"mobile";}}`, `{"_123":{"regexp":"iPhone","value":"mobile"}}`},
+	}
+	for i, test := range tests {
+		j, err := cfgmigrate.SerializedToJSON(test.serialized)
+		assert.NoError(t, err, "Index %d", i)
+		assert.Exactly(t, test.json, j, "Index %d", i)
+
+		s, err := cfgmigrate.JSONToSerialized([]byte(test.json))
+		assert.NoError(t, err, "Index %d", i)
+		assert.Exactly(t, test.serialized, s, "Index %d", i)
+	}
+}
+
+func TestSerializedJSONUnchanged(t *testing.T) {
+	for _, v := range []interface{}{nil, "", "12", "http://corestore.io", `s:3:"abc";`} {
+		have, err := cfgmigrate.SerializedToJSON(v)
+		assert.NoError(t, err)
+		assert.Exactly(t, v, have)
+
+		have, err = cfgmigrate.JSONToSerialized(v)
+		assert.NoError(t, err)
+		assert.Exactly(t, v, have)
+	}
+}
+
+func TestSerializedJSONErrors(t *testing.T) {
+	for i, v := range []interface{}{
+		`a:1:{s:1:"a";}`,
+		`a:1:{s:5:"a";i:1;}`,
+		`a:x:{}`,
+		`a:1:{s:1:"a";i:1;}garbage`,
+		`a:1:{s:1:"a";i:one;}`,
+		`a:1:{i:0;O:8:"stdClass":0:{}}`,
+		3.14,
+	} {
+		_, err := cfgmigrate.SerializedToJSON(v)
+		assert.True(t, errors.IsNotValid(err) || errors.IsNotSupported(err), "Index %d => %+v", i, err)
+	}
+	for i, v := range []interface{}{`{"a":`, `{"a":1}}`, `[1,]`} {
+		_, err := cfgmigrate.JSONToSerialized(v)
+		assert.True(t, errors.IsNotValid(err), "Index %d => %+v", i, err)
+	}
+}
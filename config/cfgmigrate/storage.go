@@ -0,0 +1,130 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgmigrate
+
+import (
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// Storage is a read-through adapter. It wraps a config.Storager which
+// contains the paths and values of the source version of a Direction and
+// provides the paths and values of the target version. Storage is safe for
+// concurrent use if the wrapped Storager is.
+type Storage struct {
+	m *Mapper
+	d Direction
+	s config.Storager
+}
+
+// NewStorage creates a new adapter. For example with direction M1ToM2 the
+// Storager s contains the data of Magento 1 and the returned Storage
+// provides the data for Magento 2.
+func NewStorage(m *Mapper, d Direction, s config.Storager) *Storage {
+	return &Storage{
+		m: m,
+		d: d,
+		s: s,
+	}
+}
+
+// Set translates the path and the value into the source version and writes
+// them into the wrapped Storager. Returns a NotSupported error behaviour if
+// the path does not exist in the source version.
+func (s *Storage) Set(key cfgpath.Path, value interface{}) error {
+	p, v, err := s.m.Map(s.d.Reverse(), key, value)
+	if err != nil {
+		return errors.Wrapf(err, "[cfgmigrate] Storage.Set Key %q", key)
+	}
+	return s.s.Set(p, v)
+}
+
+// Get translates the path into the source version, reads the value and
+// converts it into the target version. Returns a NotFound error behaviour if
+// the path does not exist in the source version.
+func (s *Storage) Get(key cfgpath.Path) (interface{}, error) {
+	p, err := s.m.Path(s.d.Reverse(), key)
+	if errors.IsNotSupported(err) {
+		return nil, errors.NewNotFoundf("[cfgmigrate] Storage.Get Key %q does not exist in the source", key)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "[cfgmigrate] Storage.Get Key %q", key)
+	}
+	v, err := s.s.Get(p)
+	if err != nil {
+		return nil, err // keep the NotFound behaviour
+	}
+	return s.m.Value(s.d, p, v)
+}
+
+// AllKeys returns the translated keys of the wrapped Storager. Keys which do
+// not exist in the target version are skipped.
+func (s *Storage) AllKeys() (cfgpath.PathSlice, error) {
+	keys, err := s.s.AllKeys()
+	if err != nil {
+		return nil, errors.Wrap(err, "[cfgmigrate] Storage.AllKeys")
+	}
+	ret := make(cfgpath.PathSlice, 0, len(keys))
+	for _, k := range keys {
+		p, err := s.m.Path(s.d, k)
+		if errors.IsNotSupported(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "[cfgmigrate] Storage.AllKeys Key %q", k)
+		}
+		ret = append(ret, p)
+	}
+	return ret, nil
+}
+
+// Result contains the statistics of a migration.
+type Result struct {
+	// Migrated number of values written into the destination.
+	Migrated int
+	// Skipped contains the source paths which do not exist in the target
+	// version.
+	Skipped cfgpath.PathSlice
+}
+
+// Migrate copies all values from src into dst and translates the paths and
+// the values into the target version of the direction. A one-shot migration.
+func (m *Mapper) Migrate(d Direction, src, dst config.Storager) (Result, error) {
+	var res Result
+	keys, err := src.AllKeys()
+	if err != nil {
+		return res, errors.Wrap(err, "[cfgmigrate] Migrate.AllKeys")
+	}
+	for _, k := range keys {
+		v, err := src.Get(k)
+		if err != nil {
+			return res, errors.Wrapf(err, "[cfgmigrate] Migrate.Get Key %q", k)
+		}
+		p, nv, err := m.Map(d, k, v)
+		if errors.IsNotSupported(err) {
+			res.Skipped = append(res.Skipped, k)
+			continue
+		}
+		if err != nil {
+			return res, errors.Wrapf(err, "[cfgmigrate] Migrate Key %q", k)
+		}
+		if err := dst.Set(p, nv); err != nil {
+			return res, errors.Wrapf(err, "[cfgmigrate] Migrate.Set Key %q", p)
+		}
+		res.Migrated++
+	}
+	return res, nil
+}
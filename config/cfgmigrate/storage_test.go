@@ -0,0 +1,95 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgmigrate_test
+
+import (
+	"testing"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/cfgmigrate"
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ config.Storager = (*cfgmigrate.Storage)(nil)
+
+func newM1Storage() config.Storager {
+	return cfgmock.NewService(cfgmock.PathValue{
+		"default/0/admin/security/session_cookie_lifetime": "900",
+		"default/0/design/package/name":                   "rwd",
+		"stores/2/design/theme/ua_regexp":                 `a:1:{s:4:"_123";a:2:{s:6:"regexp";s:6:"iPhone";s:5:"value";s:6:"mobile";}}`,
+		"default/0/web/unsecure/base_url":                 "http://corestore.io",
+	}).Storage
+}
+
+func TestStorage(t *testing.T) {
+	m1 := newM1Storage()
+	s := cfgmigrate.NewStorage(cfgmigrate.MustNewMapper(testRules...), cfgmigrate.M1ToM2, m1)
+
+	v, err := s.Get(cfgpath.MustNewByParts("admin/security/session_lifetime"))
+	assert.NoError(t, err)
+	assert.Exactly(t, "900", v)
+
+	v, err = s.Get(cfgpath.MustNewByParts("design/theme/ua_regexp").BindStore(2))
+	assert.NoError(t, err)
+	assert.Exactly(t, `{"_123":{"regexp":"iPhone","value":"mobile"}}`, v)
+
+	_, err = s.Get(cfgpath.MustNewByParts("design/theme/theme_id"))
+	assert.True(t, errors.IsNotFound(err), "%+v", err)
+	_, err = s.Get(cfgpath.MustNewByParts("web/secure/base_url"))
+	assert.True(t, errors.IsNotFound(err), "%+v", err)
+
+	assert.NoError(t, s.Set(cfgpath.MustNewByParts("design/theme/ua_regexp").BindWebsite(1), `{"_1":{"regexp":"Android","value":"mobile"}}`))
+	v, err = m1.Get(cfgpath.MustNewByParts("design/theme/ua_regexp").BindWebsite(1))
+	assert.NoError(t, err)
+	assert.Exactly(t, `a:1:{s:2:"_1";a:2:{s:6:"regexp";s:7:"Android";s:5:"value";s:6:"mobile";}}`, v)
+
+	err = s.Set(cfgpath.MustNewByParts("design/theme/theme_id"), "3")
+	assert.True(t, errors.IsNotSupported(err), "%+v", err)
+
+	keys, err := s.AllKeys()
+	assert.NoError(t, err)
+	var have []string
+	for _, k := range keys {
+		have = append(have, k.String())
+	}
+	assert.Contains(t, have, "default/0/admin/security/session_lifetime")
+	assert.Contains(t, have, "websites/1/design/theme/ua_regexp")
+	assert.NotContains(t, have, "default/0/design/package/name")
+	assert.Len(t, have, 4)
+}
+
+func TestMapperMigrate(t *testing.T) {
+	m2 := config.NewInMemoryStore()
+	res, err := cfgmigrate.MustNewMapper(testRules...).Migrate(cfgmigrate.M1ToM2, newM1Storage(), m2)
+	assert.NoError(t, err)
+	assert.Exactly(t, 3, res.Migrated)
+	assert.Len(t, res.Skipped, 1)
+	assert.Exactly(t, "default/0/design/package/name", res.Skipped[0].String())
+
+	v, err := m2.Get(cfgpath.MustNewByParts("design/theme/ua_regexp").BindStore(2))
+	assert.NoError(t, err)
+	assert.Exactly(t, `{"_123":{"regexp":"iPhone","value":"mobile"}}`, v)
+
+	v, err = m2.Get(cfgpath.MustNewByParts("admin/security/session_lifetime"))
+	assert.NoError(t, err)
+	assert.Exactly(t, "900", v)
+
+	keys, err := m2.AllKeys()
+	assert.NoError(t, err)
+	assert.Len(t, keys, 3)
+}
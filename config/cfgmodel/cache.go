@@ -0,0 +1,57 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgmodel
+
+import "sync"
+
+// maxCacheEntries limits the number of parsed values within a valueCache.
+// Reaching the limit clears the cache.
+const maxCacheEntries = 256
+
+// valueCache stores parsed configuration values to avoid parsing the same raw
+// value again and again. A nil valueCache disables caching. valueCache is safe
+// for concurrent use.
+type valueCache struct {
+	mu sync.RWMutex
+	m  map[string]interface{}
+}
+
+func newValueCache() *valueCache {
+	return &valueCache{
+		m: make(map[string]interface{}),
+	}
+}
+
+func (vc *valueCache) get(key string) (interface{}, bool) {
+	if vc == nil {
+		return nil, false
+	}
+	vc.mu.RLock()
+	v, ok := vc.m[key]
+	vc.mu.RUnlock()
+	return v, ok
+}
+
+func (vc *valueCache) set(key string, v interface{}) {
+	if vc == nil {
+		return
+	}
+	vc.mu.Lock()
+	if len(vc.m) >= maxCacheEntries {
+		vc.m = make(map[string]interface{})
+	}
+	vc.m[key] = v
+	vc.mu.Unlock()
+}
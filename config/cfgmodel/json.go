@@ -0,0 +1,136 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgmodel
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// JSON represents a path in config.Getter which handles JSON encoded values.
+// The decoded values are getting cached per raw value and target type.
+type JSON struct {
+	Byte
+	cache *valueCache
+}
+
+// NewJSON creates a new JSON cfgmodel with a given path.
+func NewJSON(path string, opts ...Option) JSON {
+	return JSON{
+		Byte:  NewByte(path, opts...),
+		cache: newValueCache(),
+	}
+}
+
+// Get unmarshals the JSON value from ScopedGetter into v. v must be a non-nil
+// pointer. If the value is empty, v won't be touched. A cached value gets
+// deep copied into v, so v can be modified without changing the cache.
+// Unexported fields, e.g. of types implementing json.Unmarshaler, are only
+// shallow copied. Error behaviour: NotValid
+func (p JSON) Get(sg config.Scoped, v interface{}) error {
+	raw, err := p.Byte.Get(sg)
+	if err != nil {
+		return errors.Wrap(err, "[cfgmodel] JSON.Byte.Get")
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.NewNotValidf("[cfgmodel] JSON.Get requires a non-nil pointer, have %T", v)
+	}
+	if len(raw) == 0 {
+		return nil
+	}
+
+	key := rv.Type().String() + string(raw)
+	if cv, ok := p.cache.get(key); ok {
+		rv.Elem().Set(deepCopy(cv.(reflect.Value)))
+		return nil
+	}
+
+	nv := reflect.New(rv.Type().Elem())
+	if err := json.Unmarshal(raw, nv.Interface()); err != nil {
+		return errors.NewNotValidf("[cfgmodel] JSON.Get.Unmarshal Route %q: %v", p.route, err)
+	}
+	p.cache.set(key, deepCopy(nv.Elem()))
+	rv.Elem().Set(nv.Elem())
+	return nil
+}
+
+// deepCopy returns a copy of v which shares no pointers, maps and slices with
+// v. Only the exported fields of a struct getting deep copied.
+func deepCopy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type().Elem())
+		c.Elem().Set(deepCopy(v.Elem()))
+		return c
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type()).Elem()
+		c.Set(deepCopy(v.Elem()))
+		return c
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeMap(v.Type())
+		for _, k := range v.MapKeys() {
+			c.SetMapIndex(k, deepCopy(v.MapIndex(k)))
+		}
+		return c
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return c
+	case reflect.Array:
+		c := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return c
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if f := c.Field(i); f.CanSet() {
+				f.Set(deepCopy(v.Field(i)))
+			}
+		}
+		return c
+	}
+	return v
+}
+
+// Write marshals v into JSON and writes it. Error behaviour: NotValid
+func (p JSON) Write(w config.Writer, v interface{}, h scope.TypeID) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return errors.NewNotValidf("[cfgmodel] JSON.Write.Marshal Route %q: %v", p.route, err)
+	}
+	return p.Byte.Write(w, raw, h)
+}
@@ -0,0 +1,87 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgmodel_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/config/cfgmodel"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type carrierRates struct {
+	Carrier string             `json:"carrier"`
+	Rates   map[string]float64 `json:"rates"`
+}
+
+func TestJSON(t *testing.T) {
+
+	const pathRates = "carriers/custom/rates"
+	b := cfgmodel.NewJSON(pathRates, cfgmodel.WithScopeWebsite())
+	wantPath := cfgpath.MustNewByParts(pathRates).BindWebsite(2)
+
+	var cr carrierRates
+	assert.NoError(t, b.Get(cfgmock.NewService().NewScoped(2, 0), &cr))
+	assert.Exactly(t, carrierRates{}, cr)
+
+	sg := cfgmock.NewService(cfgmock.PathValue{
+		wantPath.String(): `{"carrier":"DHL","rates":{"CH":12.5,"DE":9.9}}`,
+	}).NewScoped(2, 0)
+	for i := 0; i < 2; i++ { // second run hits the cache
+		var cr carrierRates
+		assert.NoError(t, b.Get(sg, &cr), "Index %d", i)
+		assert.Exactly(t, carrierRates{Carrier: "DHL", Rates: map[string]float64{"CH": 12.5, "DE": 9.9}}, cr, "Index %d", i)
+	}
+
+	var m map[string]interface{}
+	assert.NoError(t, b.Get(sg, &m))
+	assert.Exactly(t, "DHL", m["carrier"])
+
+	// modifying a returned value must not change the cache
+	for i := 0; i < 2; i++ {
+		var cr carrierRates
+		assert.NoError(t, b.Get(sg, &cr), "Index %d", i)
+		assert.Exactly(t, 9.9, cr.Rates["DE"], "Index %d", i)
+		cr.Rates["DE"] = 0
+		delete(cr.Rates, "CH")
+
+		var m map[string]interface{}
+		assert.NoError(t, b.Get(sg, &m), "Index %d", i)
+		assert.Exactly(t, 12.5, m["rates"].(map[string]interface{})["CH"], "Index %d", i)
+		m["rates"].(map[string]interface{})["CH"] = 0.0
+		m["carrier"] = "UPS"
+	}
+
+	var null interface{}
+	assert.NoError(t, b.Get(cfgmock.NewService(cfgmock.PathValue{wantPath.String(): `null`}).NewScoped(2, 0), &null))
+	assert.Nil(t, null)
+
+	assert.True(t, errors.IsNotValid(b.Get(sg, cr)), "Non-pointer")
+	assert.True(t, errors.IsNotValid(b.Get(cfgmock.NewService(cfgmock.PathValue{
+		wantPath.String(): `{"carrier":`,
+	}).NewScoped(2, 0), &cr)))
+
+	mw := &cfgmock.Write{}
+	assert.NoError(t, b.Write(mw, carrierRates{Carrier: "UPS"}, scope.Website.Pack(2)))
+	assert.Exactly(t, wantPath.String(), mw.ArgPath)
+	assert.Exactly(t, []byte(`{"carrier":"UPS","rates":null}`), mw.ArgValue)
+
+	assert.True(t, errors.IsNotValid(b.Write(mw, math.Inf(1), scope.Website.Pack(2))))
+}
@@ -0,0 +1,66 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgmodel
+
+import (
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/storage/money"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// Money represents a path in config.Getter which handles monetary values like
+// minimum order amounts or shipping costs. The value gets stored as a decimal
+// string, e.g. 12.9900.
+type Money struct {
+	Str
+	// MoneyOptions gets applied to each newly created money.Money, for
+	// example the precision or the Swedish rounding.
+	MoneyOptions []money.Option
+}
+
+// NewMoney creates a new Money cfgmodel with a given path.
+func NewMoney(path string, opts ...Option) Money {
+	return Money{
+		Str: NewStr(path, opts...),
+	}
+}
+
+// Get returns a money type. If the underlying value is empty the returned
+// money.Money is not valid, means NULL. Error behaviour: NotValid
+func (p Money) Get(sg config.Scoped) (money.Money, error) {
+	m := money.New(p.MoneyOptions...)
+	s, err := p.Str.Get(sg)
+	if err != nil {
+		return m, errors.Wrap(err, "[cfgmodel] Money.Str.Get")
+	}
+	if s == "" {
+		return m, nil
+	}
+	if err := m.ParseFloat(s); err != nil {
+		return money.New(p.MoneyOptions...), errors.NewNotValidf("[cfgmodel] Money.ParseFloat Route %q: %v", p.route, err)
+	}
+	return m, nil
+}
+
+// Write writes a money value as a decimal string. An invalid money value, means
+// NULL, writes an empty value.
+func (p Money) Write(w config.Writer, m money.Money, h scope.TypeID) error {
+	var val string
+	if m.Valid {
+		val = string(m.Ftoa())
+	}
+	return p.Str.Write(w, val, h)
+}
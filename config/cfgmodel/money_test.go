@@ -0,0 +1,56 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgmodel_test
+
+import (
+	"testing"
+
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/config/cfgmodel"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/storage/money"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMoney(t *testing.T) {
+
+	const pathAmount = "sales/minimum_order/amount"
+	b := cfgmodel.NewMoney(pathAmount, cfgmodel.WithScopeWebsite())
+	b.MoneyOptions = []money.Option{money.WithPrecision(100)}
+	wantPath := cfgpath.MustNewByParts(pathAmount).BindWebsite(1)
+
+	m, err := b.Get(cfgmock.NewService().NewScoped(1, 1))
+	assert.NoError(t, err)
+	assert.False(t, m.Valid)
+
+	m, err = b.Get(cfgmock.NewService(cfgmock.PathValue{wantPath.String(): "49.9900"}).NewScoped(1, 1))
+	assert.NoError(t, err)
+	assert.True(t, m.Valid)
+	assert.Exactly(t, int64(4999), m.Raw())
+	assert.Exactly(t, 2, m.Precision())
+
+	m, err = b.Get(cfgmock.NewService(cfgmock.PathValue{wantPath.String(): "49,99"}).NewScoped(1, 1))
+	assert.False(t, m.Valid)
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+
+	mw := &cfgmock.Write{}
+	assert.NoError(t, b.Write(mw, money.New(money.WithPrecision(100)).Setf(12.5), scope.Website.Pack(1)))
+	assert.Exactly(t, wantPath.String(), mw.ArgPath)
+	assert.Exactly(t, "12.50", mw.ArgValue)
+	assert.NoError(t, b.Write(mw, money.New(), scope.Website.Pack(1)))
+	assert.Exactly(t, "", mw.ArgValue)
+}
@@ -0,0 +1,103 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgmodel
+
+import (
+	gonet "net"
+	"strings"
+
+	"github.com/corestoreio/csfw/config"
+	csnet "github.com/corestoreio/csfw/net"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// IPRanges represents a path in config.Getter which handles a list of IP
+// ranges. Each line contains one range, separated by \r and/or \n. A range
+// can be written as:
+//		Single IP:  74.50.153.4
+//		IPv4 range: 74.50.153.0-74.50.153.4
+//		IPv6 range: ::ffff:192.0.2.128-::ffff:192.0.2.250
+//		CIDR:       10.0.0.0/8 or 2001:db8::/32
+// Empty lines and lines starting with # are getting ignored.
+type IPRanges struct{ Str }
+
+// NewIPRanges creates a new IPRanges cfgmodel with a given path.
+func NewIPRanges(path string, opts ...Option) IPRanges {
+	return IPRanges{Str: NewStr(path, opts...)}
+}
+
+// Get returns the parsed IP ranges. If the underlying value is empty returns
+// nil,nil. Error behaviour: NotValid
+func (p IPRanges) Get(sg config.Scoped) (csnet.IPRanges, error) {
+	s, err := p.Str.Get(sg)
+	if err != nil {
+		return nil, errors.Wrap(err, "[cfgmodel] IPRanges.Str.Get")
+	}
+	rngs, err := parseIPRanges(s)
+	if err != nil {
+		return nil, errors.Wrapf(err, "[cfgmodel] IPRanges.Get Route %q", p.route)
+	}
+	return rngs, nil
+}
+
+// Write writes the IP ranges, one range per line.
+func (p IPRanges) Write(w config.Writer, rngs csnet.IPRanges, h scope.TypeID) error {
+	lines := make([]string, len(rngs))
+	for i, r := range rngs {
+		lines[i] = r.String()
+	}
+	return p.Str.Write(w, strings.Join(lines, "\n"), h)
+}
+
+func parseIPRanges(s string) (csnet.IPRanges, error) {
+	lines := strings.FieldsFunc(s, func(r rune) bool { return r == '\n' || r == '\r' })
+	var rngs csnet.IPRanges
+	for _, l := range lines {
+		l = strings.TrimSpace(l)
+		if l == "" || l[0] == '#' {
+			continue
+		}
+		r, err := parseIPRange(l)
+		if err != nil {
+			return nil, err
+		}
+		rngs = append(rngs, r)
+	}
+	return rngs, nil
+}
+
+func parseIPRange(l string) (csnet.IPRange, error) {
+	if strings.IndexByte(l, '/') > 0 {
+		_, ipn, err := gonet.ParseCIDR(l)
+		if err != nil {
+			return csnet.IPRange{}, errors.NewNotValidf("[cfgmodel] Invalid CIDR %q: %v", l, err)
+		}
+		to := make(gonet.IP, len(ipn.IP))
+		for i := range ipn.IP {
+			to[i] = ipn.IP[i] | ^ipn.Mask[i]
+		}
+		return csnet.NewIPRange(ipn.IP.String(), to.String()), nil
+	}
+
+	from, to := l, l
+	if i := strings.IndexByte(l, '-'); i >= 0 {
+		from, to = strings.TrimSpace(l[:i]), strings.TrimSpace(l[i+1:])
+	}
+	if gonet.ParseIP(from) == nil || gonet.ParseIP(to) == nil {
+		return csnet.IPRange{}, errors.NewNotValidf("[cfgmodel] IP Range %q not in expected format: IP.From-IP.To", l)
+	}
+	return csnet.NewIPRange(from, to), nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgmodel_test
+
+import (
+	"testing"
+
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/config/cfgmodel"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	csnet "github.com/corestoreio/csfw/net"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIPRanges(t *testing.T) {
+
+	const pathIPs = "dev/restrict/allow_ips"
+	b := cfgmodel.NewIPRanges(pathIPs, cfgmodel.WithScopeStore())
+	wantPath := cfgpath.MustNewByParts(pathIPs).BindStore(4)
+
+	rngs, err := b.Get(cfgmock.NewService().NewScoped(1, 4))
+	assert.NoError(t, err)
+	assert.Nil(t, rngs)
+
+	rngs, err = b.Get(cfgmock.NewService(cfgmock.PathValue{
+		wantPath.String(): "# office\r\n74.50.153.0-74.50.153.4\n\n192.168.1.10\n10.0.0.0/8\n::ffff:192.0.2.128 - ::ffff:192.0.2.250\n2001:db8::/32",
+	}).NewScoped(1, 4))
+	assert.NoError(t, err)
+	assert.Len(t, rngs, 5)
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"74.50.153.2", true},
+		{"74.50.153.5", false},
+		{"192.168.1.10", true},
+		{"192.168.1.11", false},
+		{"10.200.3.4", true},
+		{"11.0.0.1", false},
+		{"192.0.2.130", true},
+		{"2001:db8:ffff::1", true},
+		{"2001:db9::1", false},
+	}
+	for _, test := range tests {
+		assert.Exactly(t, test.want, rngs.InStr(test.ip), "IP %s", test.ip)
+	}
+
+	for _, invalid := range []string{"74.50.153.0-", "10.0.0.0/33", "localhost"} {
+		rngs, err = b.Get(cfgmock.NewService(cfgmock.PathValue{wantPath.String(): invalid}).NewScoped(1, 4))
+		assert.Nil(t, rngs, "%q", invalid)
+		assert.True(t, errors.IsNotValid(err), "%q Error: %+v", invalid, err)
+	}
+
+	mw := &cfgmock.Write{}
+	assert.NoError(t, b.Write(mw, csnet.IPRanges{
+		csnet.NewIPRange("74.50.153.0", "74.50.153.4"),
+		csnet.NewIPRange("2001:db8::", "2001:db8::ff"),
+	}, scope.Store.Pack(4)))
+	assert.Exactly(t, wantPath.String(), mw.ArgPath)
+	assert.Exactly(t, "74.50.153.0-74.50.153.4\n2001:db8::-2001:db8::ff", mw.ArgValue)
+}
@@ -22,6 +22,7 @@ import (
 
 // Encryptor functions needed for encryption and decryption of
 // string values. For example implements M1 and M2 encryption key functions.
+// Package config/cfgcrypt contains the Magento compatible implementations.
 type Encryptor interface {
 	Encrypt([]byte) ([]byte, error)
 	Decrypt([]byte) ([]byte, error)
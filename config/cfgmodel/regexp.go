@@ -0,0 +1,69 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgmodel
+
+import (
+	"regexp"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// Regexp represents a path in config.Getter which handles regular
+// expressions. The compiled expressions are getting cached.
+type Regexp struct {
+	Str
+	cache *valueCache
+}
+
+// NewRegexp creates a new Regexp cfgmodel with a given path.
+func NewRegexp(path string, opts ...Option) Regexp {
+	return Regexp{
+		Str:   NewStr(path, opts...),
+		cache: newValueCache(),
+	}
+}
+
+// Get returns a compiled regular expression. If the underlying value is empty
+// returns nil,nil. Error behaviour: NotValid
+func (p Regexp) Get(sg config.Scoped) (*regexp.Regexp, error) {
+	s, err := p.Str.Get(sg)
+	if err != nil {
+		return nil, errors.Wrap(err, "[cfgmodel] Regexp.Str.Get")
+	}
+	if s == "" {
+		return nil, nil
+	}
+	if cr, ok := p.cache.get(s); ok {
+		return cr.(*regexp.Regexp), nil
+	}
+	r, err := regexp.Compile(s)
+	if err != nil {
+		return nil, errors.NewNotValidf("[cfgmodel] Regexp.Compile Route %q: %v", p.route, err)
+	}
+	p.cache.set(s, r)
+	return r, nil
+}
+
+// Write writes the source of the regular expression. If r is nil, an empty
+// value will be written.
+func (p Regexp) Write(w config.Writer, r *regexp.Regexp, h scope.TypeID) error {
+	var val string
+	if r != nil {
+		val = r.String()
+	}
+	return p.Str.Write(w, val, h)
+}
@@ -0,0 +1,57 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgmodel_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/config/cfgmodel"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegexp(t *testing.T) {
+
+	const pathRE = "design/theme/ua_regexp"
+	b := cfgmodel.NewRegexp(pathRE)
+	wantPath := cfgpath.MustNewByParts(pathRE)
+
+	r, err := b.Get(cfgmock.NewService().NewScoped(1, 1))
+	assert.NoError(t, err)
+	assert.Nil(t, r)
+
+	sg := cfgmock.NewService(cfgmock.PathValue{wantPath.String(): `(?i)iphone|android`}).NewScoped(1, 1)
+	r, err = b.Get(sg)
+	assert.NoError(t, err)
+	assert.True(t, r.MatchString("Mozilla/5.0 (iPhone; CPU iPhone OS 10_0 like Mac OS X)"))
+	r2, err := b.Get(sg)
+	assert.NoError(t, err)
+	assert.True(t, r == r2, "Regexp must be cached")
+
+	r, err = b.Get(cfgmock.NewService(cfgmock.PathValue{wantPath.String(): `(iphone`}).NewScoped(1, 1))
+	assert.Nil(t, r)
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+
+	mw := &cfgmock.Write{}
+	assert.NoError(t, b.Write(mw, regexp.MustCompile(`^[a-z]+$`), scope.DefaultTypeID))
+	assert.Exactly(t, wantPath.String(), mw.ArgPath)
+	assert.Exactly(t, `^[a-z]+$`, mw.ArgValue)
+	assert.NoError(t, b.Write(mw, nil, scope.DefaultTypeID))
+	assert.Exactly(t, ``, mw.ArgValue)
+}
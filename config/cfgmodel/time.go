@@ -117,3 +117,52 @@ func (t Duration) Get(sg config.Scoped) (time.Duration, error) {
 func (t Duration) Write(w config.Writer, v time.Duration, h scope.TypeID) error {
 	return t.baseValue.Write(w, v.String(), h)
 }
+
+// Location represents a path in config.Getter which handles time zones, for
+// example general/locale/timezone. The loaded locations are getting cached.
+type Location struct {
+	Str
+	cache *valueCache
+}
+
+// NewLocation creates a new Location cfgmodel with a given path.
+func NewLocation(path string, opts ...Option) Location {
+	return Location{
+		Str:   NewStr(path, opts...),
+		cache: newValueCache(),
+	}
+}
+
+// Get returns a time zone location by its IANA name, e.g. Europe/Zurich. If
+// the underlying value is empty returns time.UTC. Error behaviour: NotValid
+func (p Location) Get(sg config.Scoped) (*time.Location, error) {
+	s, err := p.Str.Get(sg)
+	if err != nil {
+		return nil, errors.Wrap(err, "[cfgmodel] Location.Str.Get")
+	}
+	if s == "" {
+		return time.UTC, nil
+	}
+	if cl, ok := p.cache.get(s); ok {
+		return cl.(*time.Location), nil
+	}
+	l, err := time.LoadLocation(s)
+	if err != nil {
+		return nil, errors.NewNotValidf("[cfgmodel] Location.LoadLocation Route %q: %v", p.route, err)
+	}
+	p.cache.set(s, l)
+	return l, nil
+}
+
+// Write writes the name of the location and validates it before saving. If l
+// is nil, an empty value will be written. Error behaviour: NotValid
+func (p Location) Write(w config.Writer, l *time.Location, h scope.TypeID) error {
+	var val string
+	if l != nil {
+		val = l.String()
+		if _, err := time.LoadLocation(val); err != nil {
+			return errors.NewNotValidf("[cfgmodel] Location.Write.LoadLocation %q: %v", val, err)
+		}
+	}
+	return p.Str.Write(w, val, h)
+}
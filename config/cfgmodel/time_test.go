@@ -263,3 +263,33 @@ func TestDurationWrite(t *testing.T) {
 	assert.Exactly(t, wantPath.String(), mw.ArgPath)
 	assert.Exactly(t, haveDuration.String(), mw.ArgValue.(string))
 }
+
+func TestLocation(t *testing.T) {
+
+	const pathTZ = "general/locale/timezone"
+	b := cfgmodel.NewLocation(pathTZ, cfgmodel.WithScopeStore())
+	wantPath := cfgpath.MustNewByParts(pathTZ).BindStore(3)
+
+	l, err := b.Get(cfgmock.NewService().NewScoped(1, 3))
+	assert.NoError(t, err)
+	assert.Exactly(t, time.UTC, l)
+
+	sg := cfgmock.NewService(cfgmock.PathValue{wantPath.String(): "Europe/Zurich"}).NewScoped(1, 3)
+	l, err = b.Get(sg)
+	assert.NoError(t, err)
+	assert.Exactly(t, "Europe/Zurich", l.String())
+	l2, err := b.Get(sg)
+	assert.NoError(t, err)
+	assert.True(t, l == l2, "Location must be cached")
+
+	l, err = b.Get(cfgmock.NewService(cfgmock.PathValue{wantPath.String(): "Europe/Gotham"}).NewScoped(1, 3))
+	assert.Nil(t, l)
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+
+	mw := &cfgmock.Write{}
+	assert.NoError(t, b.Write(mw, l2, scope.Store.Pack(3)))
+	assert.Exactly(t, wantPath.String(), mw.ArgPath)
+	assert.Exactly(t, "Europe/Zurich", mw.ArgValue)
+
+	assert.True(t, errors.IsNotValid(b.Write(mw, time.FixedZone("Gotham", 3600), scope.Store.Pack(3))))
+}
@@ -0,0 +1,33 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cfgredis distributes the config pub/sub messages across all nodes of
+// a cluster via Redis PUBLISH and SUBSCRIBE.
+//
+// Each node creates a Transport with the same Redis URL and sets it as an
+// option to its config.Service. A write on one node gets published to the
+// local subscribers and to the Redis channel. All other nodes receive the path
+// and publish it to their local subscribers, which then invalidate their
+// cached values.
+//
+//		tr, err := cfgredis.NewTransport("redis://localhost:6379/0")
+//		tr.NodeID = os.Getenv("HOSTNAME") // optional, default a random ID
+//		srv, err := config.NewService(backend, config.WithPubSub(), config.WithTransport(tr))
+//		defer srv.Close() // closes also the Transport
+//
+// A message contains the NodeID of the sender and the fully qualified path.
+// The Transport ignores its own messages, so a path never echoes back to the
+// node which has written it. Values are not part of a message because all
+// nodes share the same Storager, for example the core_config_data table.
+package cfgredis
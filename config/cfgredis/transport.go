@@ -0,0 +1,265 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgredis
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/log"
+	"github.com/corestoreio/csfw/net/url"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/garyburd/redigo/redis"
+)
+
+// DefaultChannel name of the Redis channel for the config messages. Redis
+// channels are not bound to a database, so different clusters on the same
+// Redis server must use different channel names.
+const DefaultChannel = "csfw_config"
+
+// Transport implements the config.Transport interface with Redis PUBLISH and
+// SUBSCRIBE. Change the exported fields before setting the Transport to a
+// config.Service.
+type Transport struct {
+	// NodeID identifies this node within the cluster. Messages with the own
+	// NodeID are getting ignored. Cannot contain a space. Default: random hex
+	// value.
+	NodeID string
+	// Channel Redis channel name. Default: DefaultChannel.
+	Channel string
+	// RetryInterval waits between two attempts to subscribe again after the
+	// connection to Redis has been lost. Default: one second.
+	RetryInterval time.Duration
+	// Log default log.BlackHole{}
+	Log log.Logger
+
+	pool *redis.Pool
+
+	mu     sync.Mutex
+	psc    *redis.PubSubConn // current subscription
+	stop   chan struct{}     // terminates the listen goroutine
+	done   chan struct{}     // closed when the listen goroutine has terminated
+	closed bool
+}
+
+// NewTransport creates a new Transport for a Redis URL, e.g.
+// redis://localhost:6379/0. The connections are getting established lazily.
+func NewTransport(rawURL string) (*Transport, error) {
+	address, password, db, err := url.ParseRedis(rawURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "[cfgredis] url.ParseRedis")
+	}
+	nodeID, err := newNodeID()
+	if err != nil {
+		return nil, errors.Wrap(err, "[cfgredis] NewTransport.NodeID")
+	}
+	return &Transport{
+		NodeID:        nodeID,
+		Channel:       DefaultChannel,
+		RetryInterval: time.Second,
+		Log:           log.BlackHole{},
+		pool: &redis.Pool{
+			MaxIdle:     3,
+			IdleTimeout: 240 * time.Second,
+			Dial: func() (redis.Conn, error) {
+				return redis.Dial("tcp", address, redis.DialPassword(password), redis.DialDatabase(int(db)))
+			},
+		},
+	}, nil
+}
+
+func newNodeID() (string, error) {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", errors.NewFatalf("[cfgredis] rand.Read: %s", err)
+	}
+	return hex.EncodeToString(b[:]), nil
+}
+
+// Broadcast publishes the fully qualified path together with the NodeID to
+// the Redis channel.
+func (t *Transport) Broadcast(p cfgpath.Path) error {
+	fq, err := p.FQ()
+	if err != nil {
+		return errors.Wrapf(err, "[cfgredis] Transport.Broadcast.FQ %q", p.Route)
+	}
+	c := t.pool.Get()
+	defer c.Close()
+	if _, err := c.Do("PUBLISH", t.Channel, t.NodeID+" "+fq.String()); err != nil {
+		return errors.NewFatalf("[cfgredis] Transport.Broadcast PUBLISH %q: %s", fq, err)
+	}
+	return nil
+}
+
+// Listen subscribes to the Redis channel and returns after Redis has confirmed
+// the subscription. A goroutine receives the paths from the other nodes and
+// passes them to the Publisher. If the connection gets lost, the goroutine
+// subscribes again after RetryInterval.
+func (t *Transport) Listen(pub config.Publisher) error {
+	if t.NodeID == "" || strings.ContainsRune(t.NodeID, ' ') {
+		return errors.NewNotValidf("[cfgredis] Transport.Listen invalid NodeID %q", t.NodeID)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	switch {
+	case t.closed:
+		return errors.NewAlreadyClosedf("[cfgredis] Transport already closed")
+	case t.stop != nil:
+		return errors.NewAlreadyExistsf("[cfgredis] Transport is already listening")
+	}
+
+	psc, err := t.subscribe()
+	if err != nil {
+		return errors.Wrap(err, "[cfgredis] Transport.Listen")
+	}
+	t.psc = psc
+	t.stop = make(chan struct{})
+	t.done = make(chan struct{})
+	go t.listen(pub, psc)
+	return nil
+}
+
+// subscribe opens a new connection and waits for the confirmation of the
+// subscription. Otherwise messages published directly after Listen might get
+// lost.
+func (t *Transport) subscribe() (*redis.PubSubConn, error) {
+	psc := &redis.PubSubConn{Conn: t.pool.Get()}
+	if err := psc.Subscribe(t.Channel); err != nil {
+		psc.Close()
+		return nil, errors.NewFatalf("[cfgredis] SUBSCRIBE %q: %s", t.Channel, err)
+	}
+	if err, ok := psc.Receive().(error); ok {
+		psc.Close()
+		return nil, errors.NewFatalf("[cfgredis] SUBSCRIBE %q: %s", t.Channel, err)
+	}
+	return psc, nil
+}
+
+func (t *Transport) listen(pub config.Publisher, psc *redis.PubSubConn) {
+	defer close(t.done)
+	for {
+		switch v := psc.Receive().(type) {
+		case redis.Message:
+			t.receive(pub, v.Data)
+		case redis.Subscription:
+			if v.Count == 0 { // unsubscribed by Close
+				t.release(psc)
+				return
+			}
+		case error:
+			t.release(psc)
+			if psc = t.resubscribe(v); psc == nil {
+				return
+			}
+		}
+	}
+}
+
+// release closes the subscription connection. The lock prevents a concurrent
+// write of Close.
+func (t *Transport) release(psc *redis.PubSubConn) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	psc.Close()
+	t.psc = nil
+}
+
+// resubscribe tries to subscribe again until it succeeds or Close has been
+// called. Returns nil in the latter case.
+func (t *Transport) resubscribe(cause error) *redis.PubSubConn {
+	for {
+		if t.Log.IsInfo() {
+			t.Log.Info("cfgredis.Transport.resubscribe", log.Err(cause), log.String("channel", t.Channel), log.Duration("retry", t.RetryInterval))
+		}
+		select {
+		case <-t.stop:
+			return nil
+		case <-time.After(t.RetryInterval):
+		}
+
+		psc, err := t.subscribe()
+		if err != nil {
+			cause = err
+			continue
+		}
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if t.closed {
+			psc.Close()
+			return nil
+		}
+		t.psc = psc
+		return psc
+	}
+}
+
+// receive parses a message and publishes the path if the message has been
+// sent by another node.
+func (t *Transport) receive(pub config.Publisher, data []byte) {
+	msg := string(data)
+	pos := strings.IndexByte(msg, ' ')
+	if pos < 1 {
+		if t.Log.IsInfo() {
+			t.Log.Info("cfgredis.Transport.receive.malformed", log.String("message", msg))
+		}
+		return
+	}
+	if msg[:pos] == t.NodeID {
+		return // our own message
+	}
+	p, err := cfgpath.SplitFQ(msg[pos+1:])
+	if err != nil {
+		if t.Log.IsInfo() {
+			t.Log.Info("cfgredis.Transport.receive.SplitFQ", log.Err(err), log.String("message", msg))
+		}
+		return
+	}
+	if t.Log.IsDebug() {
+		t.Log.Debug("cfgredis.Transport.receive", log.String("node", msg[:pos]), log.Stringer("path", p))
+	}
+	pub.Publish(p)
+}
+
+// Close unsubscribes from the channel, waits for the goroutine to terminate
+// and closes all connections.
+func (t *Transport) Close() error {
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return errors.NewAlreadyClosedf("[cfgredis] Transport already closed")
+	}
+	t.closed = true
+	if t.stop != nil {
+		close(t.stop)
+	}
+	if t.psc != nil {
+		if err := t.psc.Unsubscribe(); err != nil && t.Log.IsDebug() {
+			t.Log.Debug("cfgredis.Transport.Close.Unsubscribe", log.Err(err))
+		}
+	}
+	done := t.done
+	t.mu.Unlock()
+
+	if done != nil {
+		<-done
+	}
+	return errors.Wrap(t.pool.Close(), "[cfgredis] Transport.Close")
+}
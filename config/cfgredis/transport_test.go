@@ -0,0 +1,165 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgredis_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis"
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/config/cfgredis"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ config.Transport = (*cfgredis.Transport)(nil)
+
+type recReceiver struct {
+	msgs chan string
+}
+
+func newRecReceiver() *recReceiver {
+	return &recReceiver{msgs: make(chan string, 10)}
+}
+
+func (rr *recReceiver) MessageConfig(p cfgpath.Path) error {
+	rr.msgs <- p.String()
+	return nil
+}
+
+func (rr *recReceiver) wait(t *testing.T) string {
+	select {
+	case p := <-rr.msgs:
+		return p
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timeout waiting for a message")
+	}
+	return ""
+}
+
+func newNode(t *testing.T, redisURL, nodeID string) (*config.Service, *recReceiver) {
+	tr, err := cfgredis.NewTransport(redisURL)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	tr.NodeID = nodeID
+	srv := config.MustNewService(config.NewInMemoryStore(), config.WithPubSub(), config.WithTransport(tr))
+	rr := newRecReceiver()
+	if _, err := srv.Subscribe(cfgpath.NewRoute("web"), rr); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	return srv, rr
+}
+
+func TestTransport(t *testing.T) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mr.Close()
+	redisURL := "redis://" + mr.Addr() + "/0"
+
+	srvA, rrA := newNode(t, redisURL, "nodeA")
+	srvB, rrB := newNode(t, redisURL, "nodeB")
+
+	p := cfgpath.MustNewByParts("web/cookie/cookie_lifetime").BindStore(5)
+	snapB := srvB.Snapshot(1, 5)
+	_, err = snapB.Int(p.Route)
+	assert.True(t, errors.IsNotFound(err), "%+v", err)
+
+	// both nodes share the same database in real life
+	assert.NoError(t, srvB.Write(p, 7200))
+	assert.Exactly(t, "stores/5/web/cookie/cookie_lifetime", rrB.wait(t))
+	assert.Exactly(t, "stores/5/web/cookie/cookie_lifetime", rrA.wait(t))
+
+	v, err := snapB.Int(p.Route)
+	assert.NoError(t, err)
+	assert.Exactly(t, 7200, v)
+
+	pA := cfgpath.MustNewByParts("web/secure/use_in_frontend")
+	assert.NoError(t, srvA.Write(pA, 1))
+	assert.Exactly(t, "default/0/web/secure/use_in_frontend", rrA.wait(t))
+	assert.Exactly(t, "default/0/web/secure/use_in_frontend", rrB.wait(t))
+
+	// malformed messages and messages with the own node ID are getting ignored
+	mr.Publish(cfgredis.DefaultChannel, "nodeC")
+	mr.Publish(cfgredis.DefaultChannel, "nodeC stores/x/web/a/b")
+	mr.Publish(cfgredis.DefaultChannel, "nodeA default/0/web/unsecure/base_url")
+	mr.Publish(cfgredis.DefaultChannel, "nodeC websites/2/web/unsecure/base_url")
+	assert.Exactly(t, "websites/2/web/unsecure/base_url", rrA.wait(t))
+	assert.Exactly(t, "default/0/web/unsecure/base_url", rrB.wait(t))
+	assert.Exactly(t, "websites/2/web/unsecure/base_url", rrB.wait(t))
+
+	assert.NoError(t, srvA.Close())
+	assert.NoError(t, srvB.Close())
+	assert.Len(t, rrA.msgs, 0, "No echo of own writes")
+	assert.Len(t, rrB.msgs, 0, "No echo of own writes")
+
+	err = srvA.Close()
+	assert.True(t, errors.IsAlreadyClosed(err), "%+v", err)
+}
+
+func TestTransportReconnect(t *testing.T) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mr.Close()
+
+	tr, err := cfgredis.NewTransport("redis://" + mr.Addr() + "/0")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	tr.RetryInterval = 10 * time.Millisecond
+	srv := config.MustNewService(config.NewInMemoryStore(), config.WithPubSub(), config.WithTransport(tr))
+	rr := newRecReceiver()
+	_, err = srv.Subscribe(cfgpath.NewRoute("web"), rr)
+	assert.NoError(t, err)
+
+	addr := mr.Addr()
+	mr.Close()
+	assert.NoError(t, mr.StartAddr(addr))
+
+	// wait until the Transport has subscribed again
+	deadline := time.Now().Add(5 * time.Second)
+	for mr.PubSubNumSub(cfgredis.DefaultChannel)[cfgredis.DefaultChannel] == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Transport did not subscribe again")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	mr.Publish(cfgredis.DefaultChannel, "nodeX default/0/web/unsecure/base_url")
+	assert.Exactly(t, "default/0/web/unsecure/base_url", rr.wait(t))
+	assert.NoError(t, srv.Close())
+}
+
+func TestTransportErrors(t *testing.T) {
+	_, err := cfgredis.NewTransport("http://localhost")
+	assert.True(t, errors.IsNotValid(err), "%+v", err)
+
+	tr, err := cfgredis.NewTransport("redis://127.0.0.1:1/0")
+	assert.NoError(t, err)
+	_, err = config.NewService(config.NewInMemoryStore(), config.WithTransport(tr))
+	assert.True(t, errors.IsFatal(err), "%+v", err)
+
+	tr.NodeID = "node A"
+	err = tr.Listen(config.MustNewService(config.NewInMemoryStore()))
+	assert.True(t, errors.IsNotValid(err), "%+v", err)
+
+	assert.NoError(t, tr.Close())
+	assert.True(t, errors.IsAlreadyClosed(tr.Close()))
+}
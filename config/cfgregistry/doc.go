@@ -0,0 +1,44 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cfgregistry collects the configuration structures and models of all
+// packages which provide configuration values.
+//
+// Each package with a configuration, for example directory,
+// net/cors/backendcors or net/jwt/backendjwt, registers in its init function a
+// Package containing the function to create its element.SectionSlice and an
+// optional function to create its cfgmodel values. Importing a package
+// registers it:
+//
+//		import (
+//			_ "github.com/corestoreio/csfw/net/cors/backendcors"
+//			_ "github.com/corestoreio/csfw/net/jwt/backendjwt"
+//		)
+//
+// At startup of the application Build merges the sections of all registered
+// packages into one element.SectionSlice, creates the models and validates
+// the whole tree. Build fails if a path has been defined twice, if a
+// constructor returns an error or if a model points to a path which does not
+// exist in the merged tree:
+//
+//		tree, err := cfgregistry.Build()
+//		if err != nil {
+//			panic(err) // contains all found errors
+//		}
+//		for _, r := range tree.Routes() {
+//			fmt.Println(r) // all known configuration paths
+//		}
+//		cors := tree.Backend("net/cors/backendcors").(*backendcors.Configuration)
+//		admin, err := cfgadmin.NewHandler(configService, tree.Sections, authService.WithAuthentication)
+package cfgregistry
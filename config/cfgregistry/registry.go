@@ -0,0 +1,250 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgregistry
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/config/element"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// Default contains all packages which have been registered via the package
+// level function Register.
+var Default = New()
+
+// Model defines a configuration value of a package. Implemented by all types
+// in package cfgmodel.
+type Model interface {
+	Route() cfgpath.Route
+}
+
+var modelType = reflect.TypeOf((*Model)(nil)).Elem()
+
+// Package describes the configuration of a package.
+type Package struct {
+	// Name identifies the package, mostly the import path without the csfw
+	// prefix, e.g. net/cors/backendcors. Required.
+	Name string
+	// Sections creates the configuration structure of the package. Required.
+	Sections func() (element.SectionSlice, error)
+	// Backend creates the configuration models of the package with the merged
+	// structure of all packages, e.g. backendcors.New. All exported struct
+	// fields of the returned pointer to a struct which implement the Model
+	// interface are getting validated. Optional.
+	Backend func(element.SectionSlice) interface{}
+}
+
+// Registry contains all registered packages. Safe for concurrent use.
+type Registry struct {
+	mu   sync.RWMutex
+	pkgs []Package
+}
+
+// New creates a new empty Registry.
+func New() *Registry {
+	return &Registry{}
+}
+
+// Register adds a package to the registry. Error behaviour: NotValid or
+// AlreadyExists.
+func (r *Registry) Register(p Package) error {
+	if p.Name == "" || p.Sections == nil {
+		return errors.NewNotValidf("[cfgregistry] Package %q requires a name and a Sections function", p.Name)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, rp := range r.pkgs {
+		if rp.Name == p.Name {
+			return errors.NewAlreadyExistsf("[cfgregistry] Package %q already registered", p.Name)
+		}
+	}
+	r.pkgs = append(r.pkgs, p)
+	return nil
+}
+
+// MustRegister same as Register but panics on error.
+func (r *Registry) MustRegister(p Package) {
+	if err := r.Register(p); err != nil {
+		panic(err)
+	}
+}
+
+// Packages returns the sorted names of all registered packages.
+func (r *Registry) Packages() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, len(r.pkgs))
+	for i, p := range r.pkgs {
+		names[i] = p.Name
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Build merges the sections of all packages, creates the backends and
+// validates the tree. The sections get merged in the sorted order of the
+// package names, so for duplicated sections and groups the attributes of the
+// last package win. A field defined by two packages, a failing constructor
+// or a model without a field in the merged tree results in an error. Only
+// invisible fields (VisibleNo) can be defined by several packages, e.g.
+// system/media_storage_configuration/allowed_resources, and the last package
+// wins. All errors are returned as an *errors.MultiErr.
+func (r *Registry) Build() (*Tree, error) {
+	r.mu.RLock()
+	pkgs := make([]Package, len(r.pkgs))
+	copy(pkgs, r.pkgs)
+	r.mu.RUnlock()
+	sort.Sort(byName(pkgs))
+
+	t := &Tree{
+		backends: make(map[string]interface{}),
+		models:   make(map[string][]Model),
+		owners:   make(map[string]string),
+	}
+	// hidden contains the routes of invisible fields which can be defined
+	// by several packages.
+	hidden := make(map[string]bool)
+	var mErr *errors.MultiErr
+	for _, p := range pkgs {
+		ss, err := p.Sections()
+		if err != nil {
+			mErr = mErr.AppendErrors(errors.Wrapf(err, "[cfgregistry] Package %q", p.Name))
+			continue
+		}
+		fields, err := routedFields(ss)
+		if err != nil {
+			mErr = mErr.AppendErrors(errors.Wrapf(err, "[cfgregistry] Package %q", p.Name))
+			continue
+		}
+		for _, rf := range fields {
+			if owner, ok := t.owners[rf.route]; ok && !(rf.Visible == element.VisibleNo && hidden[rf.route]) {
+				mErr = mErr.AppendErrors(errors.NewAlreadyExistsf("[cfgregistry] Package %q: Path %q already defined by package %q", p.Name, rf.route, owner))
+				continue
+			}
+			t.owners[rf.route] = p.Name
+			hidden[rf.route] = rf.Visible == element.VisibleNo
+		}
+		if err := t.Sections.MergeMultiple(ss); err != nil {
+			mErr = mErr.AppendErrors(errors.Wrapf(err, "[cfgregistry] Package %q", p.Name))
+		}
+	}
+	if mErr.HasErrors() {
+		return nil, mErr
+	}
+	if err := t.Sections.Validate(); err != nil {
+		return nil, errors.Wrap(err, "[cfgregistry] Registry.Build.Validate")
+	}
+	t.Sections.SortAll()
+
+	for _, p := range pkgs {
+		if p.Backend == nil {
+			continue
+		}
+		be := p.Backend(t.Sections)
+		t.backends[p.Name] = be
+		models, errs := collectModels(p.Name, be)
+		mErr = mErr.AppendErrors(errs...)
+		for _, m := range models {
+			if _, _, err := t.Sections.FindField(m.Route()); err != nil {
+				mErr = mErr.AppendErrors(errors.NewNotFoundf("[cfgregistry] Package %q: Model path %q not found in the configuration structure", p.Name, m.Route()))
+			}
+		}
+		t.models[p.Name] = models
+	}
+	if mErr.HasErrors() {
+		return nil, mErr
+	}
+	return t, nil
+}
+
+type byName []Package
+
+func (b byName) Len() int           { return len(b) }
+func (b byName) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
+func (b byName) Less(i, j int) bool { return b[i].Name < b[j].Name }
+
+type routedField struct {
+	route string
+	element.Field
+}
+
+// routedFields returns all fields with their storage route.
+func routedFields(ss element.SectionSlice) ([]routedField, error) {
+	rfs := make([]routedField, 0, ss.TotalFields())
+	for _, s := range ss {
+		for _, g := range s.Groups {
+			for _, f := range g.Fields {
+				r, err := f.Route(s.ID, g.ID)
+				if err != nil {
+					return nil, errors.Wrapf(err, "[cfgregistry] Section %q Group %q", s.ID, g.ID)
+				}
+				rfs = append(rfs, routedField{route: r.String(), Field: f})
+			}
+		}
+	}
+	return rfs, nil
+}
+
+// collectModels returns all exported struct fields of the backend which
+// implement the Model interface. Models with an empty route are skipped. A
+// non-nil exported field LastError of a model gets reported as an error.
+func collectModels(name string, be interface{}) ([]Model, []error) {
+	v := reflect.Indirect(reflect.ValueOf(be))
+	if v.Kind() != reflect.Struct {
+		return nil, []error{errors.NewNotValidf("[cfgregistry] Package %q: Backend must return a pointer to a struct, have %T", name, be)}
+	}
+	var models []Model
+	var errs []error
+	for i := 0; i < v.NumField(); i++ {
+		sf := v.Type().Field(i)
+		if sf.PkgPath != "" || !sf.Type.Implements(modelType) {
+			continue
+		}
+		fv := v.Field(i)
+		if sf.Type.Kind() == reflect.Ptr && fv.IsNil() {
+			continue
+		}
+		m := fv.Interface().(Model)
+		if m.Route().IsEmpty() {
+			continue // not yet initialized by the constructor of the package
+		}
+		if le := reflect.Indirect(fv).FieldByName("LastError"); le.IsValid() && !le.IsNil() {
+			errs = append(errs, errors.Wrapf(le.Interface().(error), "[cfgregistry] Package %q Model %q", name, sf.Name))
+		}
+		models = append(models, m)
+	}
+	return models, errs
+}
+
+// Register adds a package to the Default registry. Error behaviour:
+// NotValid or AlreadyExists.
+func Register(p Package) error {
+	return Default.Register(p)
+}
+
+// MustRegister adds a package to the Default registry and panics on error.
+// Mostly used in an init function.
+func MustRegister(p Package) {
+	Default.MustRegister(p)
+}
+
+// Build merges and validates all packages of the Default registry.
+func Build() (*Tree, error) {
+	return Default.Build()
+}
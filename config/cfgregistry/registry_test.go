@@ -0,0 +1,186 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgregistry_test
+
+import (
+	"testing"
+
+	"github.com/corestoreio/csfw/config/cfgmodel"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/config/cfgregistry"
+	"github.com/corestoreio/csfw/config/element"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ cfgregistry.Model = (*cfgmodel.Str)(nil)
+var _ cfgregistry.Model = (*cfgmodel.Bool)(nil)
+
+func newSections(section, group string, fields ...string) func() (element.SectionSlice, error) {
+	return func() (element.SectionSlice, error) {
+		fs := element.NewFieldSlice()
+		for i, f := range fields {
+			fs.Append(element.Field{
+				ID:        cfgpath.NewRoute(f),
+				SortOrder: len(fields) - i,
+				Scopes:    scope.PermStore,
+			})
+		}
+		return element.NewConfiguration(element.Section{
+			ID: cfgpath.NewRoute(section),
+			Groups: element.NewGroupSlice(element.Group{
+				ID:     cfgpath.NewRoute(group),
+				Fields: fs,
+			}),
+		})
+	}
+}
+
+type corsBackend struct {
+	unexported cfgmodel.Str
+	Origins    cfgmodel.Str
+	Enabled    cfgmodel.Bool
+	Timeout    *cfgmodel.Str
+	Missing    cfgmodel.Str
+	Name       string
+}
+
+func TestRegistryBuild(t *testing.T) {
+	r := cfgregistry.New()
+	assert.NoError(t, r.Register(cfgregistry.Package{
+		Name:     "net/cors/backendcors",
+		Sections: newSections("net", "cors", "allowed_origins", "enabled"),
+		Backend: func(ss element.SectionSlice) interface{} {
+			return &corsBackend{
+				Origins: cfgmodel.NewStr("net/cors/allowed_origins", cfgmodel.WithFieldFromSectionSlice(ss)),
+				Enabled: cfgmodel.NewBool("net/cors/enabled", cfgmodel.WithFieldFromSectionSlice(ss)),
+			}
+		},
+	}))
+	assert.NoError(t, r.Register(cfgregistry.Package{
+		Name:     "net/jwt/backendjwt",
+		Sections: newSections("net", "jwt", "expiration"),
+	}))
+	assert.NoError(t, r.Register(cfgregistry.Package{
+		Name:     "directory",
+		Sections: newSections("currency", "options", "base"),
+	}))
+
+	err := r.Register(cfgregistry.Package{Name: "directory", Sections: newSections("general", "locale", "code")})
+	assert.True(t, errors.IsAlreadyExists(err), "%+v", err)
+	err = r.Register(cfgregistry.Package{Name: "empty"})
+	assert.True(t, errors.IsNotValid(err), "%+v", err)
+
+	assert.Exactly(t, []string{"directory", "net/cors/backendcors", "net/jwt/backendjwt"}, r.Packages())
+
+	tree, err := r.Build()
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	assert.Exactly(t, []string{
+		"currency/options/base",
+		"net/cors/allowed_origins",
+		"net/cors/enabled",
+		"net/jwt/expiration",
+	}, tree.Routes())
+	assert.Exactly(t, "net/jwt/backendjwt", tree.Owner("net/jwt/expiration"))
+	assert.Exactly(t, "", tree.Owner("net/jwt/xxx"))
+
+	assert.Len(t, tree.Sections, 2)
+	net, _, err := tree.Sections.Find(cfgpath.NewRoute("net"))
+	assert.NoError(t, err)
+	assert.Len(t, net.Groups, 2, "Groups of section net must be merged")
+	cors, _, err := net.Groups.Find(cfgpath.NewRoute("cors"))
+	assert.NoError(t, err)
+	assert.Exactly(t, "enabled", cors.Fields[0].ID.String(), "Fields must be sorted")
+
+	be, ok := tree.Backend("net/cors/backendcors").(*corsBackend)
+	assert.True(t, ok)
+	assert.True(t, be.Origins.HasField())
+	assert.Len(t, tree.Models("net/cors/backendcors"), 2)
+	assert.Nil(t, tree.Backend("directory"))
+}
+
+func TestRegistryBuildErrors(t *testing.T) {
+	r := cfgregistry.New()
+	r.MustRegister(cfgregistry.Package{
+		Name:     "a",
+		Sections: newSections("net", "cors", "enabled"),
+		Backend: func(ss element.SectionSlice) interface{} {
+			return &corsBackend{
+				Origins: cfgmodel.NewStr("net/cors/allowed_origins"),
+				Enabled: cfgmodel.NewBool("net/cors/enabled", cfgmodel.WithFieldFromSectionSlice(ss)),
+			}
+		},
+	})
+	tree, err := r.Build()
+	assert.Nil(t, tree)
+	assert.True(t, errors.MultiErrContainsAll(err, errors.IsNotFound), "%+v", err)
+	assert.Contains(t, err.Error(), `Model path "net/cors/allowed_origins" not found`)
+
+	r.MustRegister(cfgregistry.Package{
+		Name:     "b",
+		Sections: newSections("net", "cors", "enabled"),
+	})
+	r.MustRegister(cfgregistry.Package{
+		Name: "c",
+		Sections: func() (element.SectionSlice, error) {
+			return nil, errors.NewFatalf("Ups")
+		},
+	})
+	r.MustRegister(cfgregistry.Package{
+		Name: "e",
+		Sections: func() (element.SectionSlice, error) {
+			ss, err := newSections("net", "cors", "hidden")()
+			ss[0].Groups[0].Fields[0].Visible = element.VisibleNo
+			return ss, err
+		},
+	})
+	r.MustRegister(cfgregistry.Package{
+		Name: "f",
+		Sections: func() (element.SectionSlice, error) {
+			ss, err := newSections("net", "cors", "hidden")()
+			ss[0].Groups[0].Fields[0].Visible = element.VisibleNo
+			return ss, err
+		},
+	})
+	_, err = r.Build()
+	me, ok := err.(*errors.MultiErr)
+	if !ok {
+		t.Fatalf("Expecting a MultiErr: %+v", err)
+	}
+	assert.Len(t, me.Errors, 2)
+	assert.True(t, errors.IsAlreadyExists(me.Errors[0]), "%+v", me.Errors[0])
+	assert.Contains(t, me.Errors[0].Error(), `Package "b": Path "net/cors/enabled" already defined by package "a"`)
+	assert.True(t, errors.IsFatal(me.Errors[1]), "%+v", me.Errors[1])
+
+	assert.Panics(t, func() {
+		r.MustRegister(cfgregistry.Package{Name: "a", Sections: newSections("general", "locale", "code")})
+	})
+}
+
+func TestRegistryBuildInvalidBackend(t *testing.T) {
+	r := cfgregistry.New()
+	r.MustRegister(cfgregistry.Package{
+		Name:     "d",
+		Sections: newSections("general", "locale", "code"),
+		Backend: func(element.SectionSlice) interface{} {
+			return "not a struct"
+		},
+	})
+	_, err := r.Build()
+	assert.True(t, errors.MultiErrContainsAll(err, errors.IsNotValid), "%+v", err)
+}
@@ -0,0 +1,58 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgregistry
+
+import (
+	"sort"
+
+	"github.com/corestoreio/csfw/config/element"
+)
+
+// Tree contains the merged and validated configuration of all registered
+// packages. Safe for concurrent reading.
+type Tree struct {
+	// Sections merged and sorted configuration structure of all packages.
+	Sections element.SectionSlice
+	backends map[string]interface{}
+	models   map[string][]Model
+	// owners maps a route to the name of the package which defines it.
+	owners map[string]string
+}
+
+// Routes returns all known configuration paths in sorted order.
+func (t *Tree) Routes() []string {
+	routes := make([]string, 0, len(t.owners))
+	for r := range t.owners {
+		routes = append(routes, r)
+	}
+	sort.Strings(routes)
+	return routes
+}
+
+// Owner returns the name of the package which defines the route. Returns an
+// empty string if the route is unknown.
+func (t *Tree) Owner(route string) string {
+	return t.owners[route]
+}
+
+// Backend returns the created backend of a package or nil.
+func (t *Tree) Backend(pkg string) interface{} {
+	return t.backends[pkg]
+}
+
+// Models returns the models of a package collected from its backend.
+func (t *Tree) Models(pkg string) []Model {
+	return t.models[pkg]
+}
@@ -0,0 +1,38 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cfgschedule applies configuration values at a given time and
+// reverts them afterwards.
+//
+// For example a sales event lowers the minimum order amount for a weekend:
+//
+//		sc := cfgschedule.MustNewScheduler(configService).Start(time.Minute)
+//		defer sc.Stop()
+//		id, err := sc.Schedule(cfgschedule.Change{
+//			Path:       cfgpath.MustNewByParts("sales/minimum_order/amount").BindWebsite(1),
+//			Value:      10,
+//			ActivateAt: time.Date(2016, 11, 25, 0, 0, 0, 0, time.UTC),
+//			ExpireAt:   time.Date(2016, 11, 28, 0, 0, 0, 0, time.UTC),
+//		})
+//
+// The Scheduler writes the values via config.Writer, mostly the
+// config.Service, which publishes each change to the subscribers. Tick
+// applies all due changes and gets called periodically by Start. Tests can
+// replace the field Clock and call Tick directly to be deterministic.
+//
+// The pending and active changes including the previous values get stored as
+// JSON in the route RouteChanges of the default scope. NewScheduler loads
+// them, so a restart neither loses a scheduled change nor the value which
+// must be restored at the expiration.
+package cfgschedule
@@ -0,0 +1,445 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgschedule
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/log"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// RouteChanges defines the route in the default scope where the Scheduler
+// stores its pending and active changes as JSON.
+const RouteChanges = "system/cfgschedule/changes"
+
+// State of a scheduled Change.
+type State uint8
+
+// State constants
+const (
+	// StatePending the change waits for its activation time.
+	StatePending State = iota
+	// StateActive the value has been written and waits for its expiration.
+	StateActive
+)
+
+// String returns the name of the state.
+func (s State) String() string {
+	if s == StateActive {
+		return "active"
+	}
+	return "pending"
+}
+
+// Change a configuration value which takes effect at ActivateAt and gets
+// reverted at ExpireAt.
+type Change struct {
+	// ID gets assigned by Scheduler.Schedule.
+	ID int64
+	// Path fully qualified path including scope and scope ID.
+	Path cfgpath.Path
+	// Value gets written at ActivateAt.
+	Value interface{}
+	// ActivateAt time when the value gets written.
+	ActivateAt time.Time
+	// ExpireAt time when the previous value gets restored. Zero means the
+	// value won't be reverted.
+	ExpireAt time.Time
+	// State current state of the change.
+	State State
+
+	// previous value before the activation. If previousValid is false the
+	// path had no value and nil gets written at the expiration.
+	previous      string
+	previousValid bool
+}
+
+// overlaps returns true if both changes have the same path and their time
+// ranges [ActivateAt,ExpireAt) intersect.
+func (c Change) overlaps(o Change) bool {
+	if c.Path.String() != o.Path.String() {
+		return false
+	}
+	cEnd, oEnd := c.ExpireAt, o.ExpireAt
+	return (oEnd.IsZero() || c.ActivateAt.Before(oEnd)) && (cEnd.IsZero() || o.ActivateAt.Before(cEnd))
+}
+
+// ReadWriter reads the current value of a path before the activation and
+// writes the scheduled values. It also stores the changes in RouteChanges.
+// The config.Service implements this interface.
+type ReadWriter interface {
+	config.Writer
+	String(cfgpath.Path) (string, error)
+}
+
+// Scheduler stores future configuration changes and applies them at the
+// right time. Scheduler is safe for concurrent use.
+type Scheduler struct {
+	// Clock returns the current time. Default time.Now. Replace it in tests.
+	Clock func() time.Time
+	// Log for debugging purposes. Errors in the background goroutine are
+	// getting logged as Info. Default log.BlackHole.
+	Log log.Logger
+
+	rw ReadWriter
+
+	mu      sync.Mutex
+	stop    chan struct{}
+	done    chan struct{}
+	lastID  int64
+	changes map[int64]*Change
+}
+
+// MustNewScheduler same as NewScheduler but panics on error.
+func MustNewScheduler(rw ReadWriter) *Scheduler {
+	s, err := NewScheduler(rw)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// NewScheduler creates a new Scheduler which writes into rw. The changes
+// stored in RouteChanges by a previous Scheduler are getting loaded, so
+// pending changes and the previous values of active changes survive a
+// restart. Loaded values are decoded from JSON, so numbers are of type
+// float64. Error behaviour: NotValid.
+func NewScheduler(rw ReadWriter) (*Scheduler, error) {
+	s := &Scheduler{
+		Clock:   time.Now,
+		Log:     log.BlackHole{}, // disabled debug and info logging
+		rw:      rw,
+		changes: make(map[int64]*Change),
+	}
+	if err := s.load(); err != nil {
+		return nil, errors.Wrap(err, "[cfgschedule] NewScheduler")
+	}
+	return s, nil
+}
+
+// storedChange represents a Change in the JSON of RouteChanges.
+type storedChange struct {
+	ID            int64       `json:"id"`
+	Path          string      `json:"path"`
+	Value         interface{} `json:"value"`
+	ActivateAt    time.Time   `json:"activate_at"`
+	ExpireAt      time.Time   `json:"expire_at"`
+	State         State       `json:"state"`
+	Previous      string      `json:"previous,omitempty"`
+	PreviousValid bool        `json:"previous_valid,omitempty"`
+}
+
+func pathChanges() cfgpath.Path {
+	return cfgpath.MustNewByParts(RouteChanges)
+}
+
+// load reads the changes from RouteChanges. A missing value means that
+// nothing has been scheduled yet.
+func (s *Scheduler) load() error {
+	raw, err := s.rw.String(pathChanges())
+	switch {
+	case errors.IsNotFound(err):
+		return nil
+	case err != nil:
+		return errors.Wrapf(err, "[cfgschedule] Scheduler.load %q", RouteChanges)
+	case raw == "":
+		return nil
+	}
+	var scs []storedChange
+	if err := json.Unmarshal([]byte(raw), &scs); err != nil {
+		return errors.NewNotValidf("[cfgschedule] Scheduler.load json.Unmarshal: %s", err)
+	}
+	for _, sc := range scs {
+		p, err := cfgpath.SplitFQ(sc.Path)
+		if err != nil {
+			return errors.Wrapf(err, "[cfgschedule] Scheduler.load change %d", sc.ID)
+		}
+		s.changes[sc.ID] = &Change{
+			ID:            sc.ID,
+			Path:          p,
+			Value:         sc.Value,
+			ActivateAt:    sc.ActivateAt,
+			ExpireAt:      sc.ExpireAt,
+			State:         sc.State,
+			previous:      sc.Previous,
+			previousValid: sc.PreviousValid,
+		}
+		if sc.ID > s.lastID {
+			s.lastID = sc.ID
+		}
+	}
+	return nil
+}
+
+// save writes all changes to RouteChanges. The caller must hold the mutex.
+func (s *Scheduler) save() error {
+	scs := make([]storedChange, 0, len(s.changes))
+	for _, c := range s.sorted() {
+		scs = append(scs, storedChange{
+			ID:            c.ID,
+			Path:          c.Path.String(),
+			Value:         c.Value,
+			ActivateAt:    c.ActivateAt,
+			ExpireAt:      c.ExpireAt,
+			State:         c.State,
+			Previous:      c.previous,
+			PreviousValid: c.previousValid,
+		})
+	}
+	data, err := json.Marshal(scs)
+	if err != nil {
+		return errors.NewNotValidf("[cfgschedule] Scheduler.save json.Marshal: %s", err)
+	}
+	return errors.Wrapf(s.rw.Write(pathChanges(), string(data)), "[cfgschedule] Scheduler.save %q", RouteChanges)
+}
+
+// sorted returns a copy of all changes ordered by their activation time. The
+// caller must hold the mutex.
+func (s *Scheduler) sorted() changesByTime {
+	cs := make(changesByTime, 0, len(s.changes))
+	for _, c := range s.changes {
+		cs = append(cs, *c)
+	}
+	sort.Sort(cs)
+	return cs
+}
+
+// Schedule adds a new change and returns its ID. A change for a path whose
+// time range overlaps with an already scheduled change for the same path
+// gets rejected. The change gets applied with the next Tick. Error
+// behaviour: NotValid, AlreadyExists or the error of the ReadWriter if the
+// changes cannot be stored.
+func (s *Scheduler) Schedule(c Change) (int64, error) {
+	if _, err := c.Path.FQ(); err != nil {
+		return 0, errors.Wrap(err, "[cfgschedule] Scheduler.Schedule")
+	}
+	if c.ActivateAt.IsZero() {
+		return 0, errors.NewNotValidf("[cfgschedule] Scheduler.Schedule Path %q ActivateAt cannot be empty", c.Path)
+	}
+	if !c.ExpireAt.IsZero() && !c.ExpireAt.After(c.ActivateAt) {
+		return 0, errors.NewNotValidf("[cfgschedule] Scheduler.Schedule Path %q ExpireAt %s must be after ActivateAt %s", c.Path, c.ExpireAt, c.ActivateAt)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, o := range s.changes {
+		if c.overlaps(*o) {
+			return 0, errors.NewAlreadyExistsf("[cfgschedule] Scheduler.Schedule Path %q overlaps with change %d", c.Path, o.ID)
+		}
+	}
+	s.lastID++
+	c.ID = s.lastID
+	c.State = StatePending
+	c.previous, c.previousValid = "", false
+	s.changes[c.ID] = &c
+	if err := s.save(); err != nil {
+		delete(s.changes, c.ID)
+		return 0, errors.Wrap(err, "[cfgschedule] Scheduler.Schedule")
+	}
+	return c.ID, nil
+}
+
+// Cancel removes a change. An active change gets reverted immediately. Error
+// behaviour: NotFound.
+func (s *Scheduler) Cancel(id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.changes[id]
+	if !ok {
+		return errors.NewNotFoundf("[cfgschedule] Scheduler.Cancel change %d not found", id)
+	}
+	if c.State == StateActive {
+		if err := s.revert(c); err != nil {
+			return errors.Wrapf(err, "[cfgschedule] Scheduler.Cancel change %d", id)
+		}
+	}
+	delete(s.changes, id)
+	return errors.Wrapf(s.save(), "[cfgschedule] Scheduler.Cancel change %d", id)
+}
+
+// Changes returns a copy of all pending and active changes ordered by their
+// activation time.
+func (s *Scheduler) Changes() []Change {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sorted()
+}
+
+type changesByTime []Change
+
+func (cs changesByTime) Len() int      { return len(cs) }
+func (cs changesByTime) Swap(i, j int) { cs[i], cs[j] = cs[j], cs[i] }
+func (cs changesByTime) Less(i, j int) bool {
+	if cs[i].ActivateAt.Equal(cs[j].ActivateAt) {
+		return cs[i].ID < cs[j].ID
+	}
+	return cs[i].ActivateAt.Before(cs[j].ActivateAt)
+}
+
+// Tick applies all changes which are due at the current time of the Clock.
+// Expirations run before activations, so a change can take over a path at
+// the moment the previous change expires. A change whose expiration time has
+// passed before its activation gets dropped without writing. Failed writes
+// are getting retried with the next Tick and the first error gets returned.
+// The new state of the changes gets stored in RouteChanges. Returns the
+// number of written values.
+func (s *Scheduler) Tick() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.Clock()
+	cs := s.sorted()
+
+	var written, dropped int
+	var firstErr error
+	setErr := func(err error) {
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	for i := range cs {
+		c := s.changes[cs[i].ID]
+		if c.State != StateActive || !isDue(c.ExpireAt, now) {
+			continue
+		}
+		if err := s.revert(c); err != nil {
+			setErr(err)
+			continue
+		}
+		written++
+		delete(s.changes, c.ID)
+	}
+
+	for i := range cs {
+		c, ok := s.changes[cs[i].ID]
+		if !ok || c.State != StatePending || !isDue(c.ActivateAt, now) {
+			continue
+		}
+		if isDue(c.ExpireAt, now) {
+			if s.Log.IsDebug() {
+				s.Log.Debug("cfgschedule.Scheduler.Tick.Expired", log.Int64("id", c.ID), log.Stringer("path", c.Path))
+			}
+			delete(s.changes, c.ID)
+			dropped++
+			continue
+		}
+		if err := s.activate(c); err != nil {
+			setErr(err)
+			continue
+		}
+		written++
+		if c.ExpireAt.IsZero() {
+			delete(s.changes, c.ID)
+		}
+	}
+	if written > 0 || dropped > 0 {
+		if err := s.save(); err != nil {
+			setErr(errors.Wrap(err, "[cfgschedule] Scheduler.Tick"))
+		}
+	}
+	return written, firstErr
+}
+
+func isDue(t, now time.Time) bool {
+	return !t.IsZero() && !now.Before(t)
+}
+
+func (s *Scheduler) activate(c *Change) error {
+	prev, err := s.rw.String(c.Path)
+	switch {
+	case err == nil:
+		c.previous, c.previousValid = prev, true
+	case errors.IsNotFound(err):
+		c.previous, c.previousValid = "", false
+	default:
+		return errors.Wrapf(err, "[cfgschedule] Scheduler.activate change %d Path %q", c.ID, c.Path)
+	}
+	if err := s.rw.Write(c.Path, c.Value); err != nil {
+		return errors.Wrapf(err, "[cfgschedule] Scheduler.activate change %d Path %q", c.ID, c.Path)
+	}
+	c.State = StateActive
+	if s.Log.IsDebug() {
+		s.Log.Debug("cfgschedule.Scheduler.activate", log.Int64("id", c.ID), log.Stringer("path", c.Path))
+	}
+	return nil
+}
+
+// revert restores the previous value. If the path had no value, nil gets
+// written which the storage engines treat like a NULL value.
+func (s *Scheduler) revert(c *Change) error {
+	var v interface{}
+	if c.previousValid {
+		v = c.previous
+	}
+	if err := s.rw.Write(c.Path, v); err != nil {
+		return errors.Wrapf(err, "[cfgschedule] Scheduler.revert change %d Path %q", c.ID, c.Path)
+	}
+	if s.Log.IsDebug() {
+		s.Log.Debug("cfgschedule.Scheduler.revert", log.Int64("id", c.ID), log.Stringer("path", c.Path))
+	}
+	return nil
+}
+
+// Start runs Tick periodically in a goroutine. Errors getting logged as Info.
+// Calling Start twice has no effect. Start and Stop are safe for concurrent
+// use.
+func (s *Scheduler) Start(interval time.Duration) *Scheduler {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop != nil {
+		return s
+	}
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.run(interval, s.stop, s.done)
+	return s
+}
+
+func (s *Scheduler) run(interval time.Duration, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := s.Tick(); err != nil {
+				s.Log.Info("cfgschedule.Scheduler.run.Tick.error", log.Err(err))
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Stop terminates the goroutine started by Start and waits until a running
+// Tick has been finished.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	s.stop, s.done = nil, nil
+	s.mu.Unlock()
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
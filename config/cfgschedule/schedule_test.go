@@ -0,0 +1,285 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgschedule_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/config/cfgschedule"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ cfgschedule.ReadWriter = (*config.Service)(nil)
+
+type recReceiver struct {
+	mu    sync.Mutex
+	paths []string
+	wg    sync.WaitGroup
+}
+
+func (rr *recReceiver) MessageConfig(p cfgpath.Path) error {
+	rr.mu.Lock()
+	rr.paths = append(rr.paths, p.String())
+	rr.mu.Unlock()
+	rr.wg.Done()
+	return nil
+}
+
+func mustString(t *testing.T, srv *config.Service, p cfgpath.Path) string {
+	s, err := srv.String(p)
+	if err != nil {
+		t.Fatalf("%s: %+v", p, err)
+	}
+	return s
+}
+
+func mustTick(t *testing.T, sc *cfgschedule.Scheduler, want int) {
+	n, err := sc.Tick()
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	assert.Exactly(t, want, n, "Number of written values")
+}
+
+func TestScheduler(t *testing.T) {
+	srv := config.MustNewService(config.NewInMemoryStore(), config.WithPubSub())
+	defer func() { assert.NoError(t, srv.Close()) }()
+	rr := new(recReceiver)
+	rr.wg.Add(7) // one initial write and six scheduled writes
+	_, err := srv.Subscribe(cfgpath.NewRoute("sales"), rr)
+	assert.NoError(t, err)
+
+	pAmount := cfgpath.MustNewByParts("sales/minimum_order/amount").BindWebsite(1)
+	pMsg := cfgpath.MustNewByParts("sales/minimum_order/description").BindWebsite(1)
+	assert.NoError(t, srv.Write(pAmount, 50))
+
+	t0 := time.Date(2016, 11, 25, 0, 0, 0, 0, time.UTC)
+	now := t0.Add(-time.Hour)
+	sc := cfgschedule.MustNewScheduler(srv)
+	sc.Clock = func() time.Time { return now }
+
+	id1, err := sc.Schedule(cfgschedule.Change{Path: pAmount, Value: 10, ActivateAt: t0, ExpireAt: t0.Add(72 * time.Hour)})
+	assert.NoError(t, err)
+	// the follow up campaign takes over the path when the first one expires
+	id2, err := sc.Schedule(cfgschedule.Change{Path: pAmount, Value: 20, ActivateAt: t0.Add(72 * time.Hour), ExpireAt: t0.Add(96 * time.Hour)})
+	assert.NoError(t, err)
+	_, err = sc.Schedule(cfgschedule.Change{Path: pMsg, Value: "Black Friday", ActivateAt: t0, ExpireAt: t0.Add(24 * time.Hour)})
+	assert.NoError(t, err)
+
+	_, err = sc.Schedule(cfgschedule.Change{Path: pAmount, Value: 5, ActivateAt: t0.Add(time.Hour)})
+	assert.True(t, errors.IsAlreadyExists(err), "%+v", err)
+
+	cs := sc.Changes()
+	assert.Len(t, cs, 3)
+	assert.Exactly(t, id1, cs[0].ID)
+	assert.Exactly(t, id2, cs[2].ID)
+
+	mustTick(t, sc, 0)
+	assert.Exactly(t, "50", mustString(t, srv, pAmount))
+
+	now = t0
+	mustTick(t, sc, 2)
+	assert.Exactly(t, "10", mustString(t, srv, pAmount))
+	assert.Exactly(t, "Black Friday", mustString(t, srv, pMsg))
+	assert.Exactly(t, cfgschedule.StateActive, sc.Changes()[0].State)
+
+	now = t0.Add(24 * time.Hour)
+	mustTick(t, sc, 1)
+	v, err := srv.String(pMsg)
+	assert.NoError(t, err)
+	assert.Empty(t, v, "Path had no value before, so NULL has been written")
+
+	now = t0.Add(72 * time.Hour)
+	mustTick(t, sc, 2)
+	assert.Exactly(t, "20", mustString(t, srv, pAmount))
+
+	now = t0.Add(100 * time.Hour)
+	mustTick(t, sc, 1)
+	assert.Exactly(t, "50", mustString(t, srv, pAmount))
+	assert.Empty(t, sc.Changes())
+
+	rr.wg.Wait()
+	assert.Len(t, rr.paths, 7)
+}
+
+func TestSchedulerCancelAndExpired(t *testing.T) {
+	srv := config.MustNewService(config.NewInMemoryStore())
+	p := cfgpath.MustNewByParts("sales/minimum_order/amount")
+	assert.NoError(t, srv.Write(p, 50))
+
+	t0 := time.Date(2016, 11, 25, 0, 0, 0, 0, time.UTC)
+	now := t0
+	sc := cfgschedule.MustNewScheduler(srv)
+	sc.Clock = func() time.Time { return now }
+
+	id, err := sc.Schedule(cfgschedule.Change{Path: p, Value: 10, ActivateAt: t0})
+	assert.NoError(t, err)
+	mustTick(t, sc, 1)
+	assert.Exactly(t, "10", mustString(t, srv, p))
+	assert.Empty(t, sc.Changes(), "Changes without expiration are done after activation")
+	assert.True(t, errors.IsNotFound(sc.Cancel(id)))
+
+	id, err = sc.Schedule(cfgschedule.Change{Path: p, Value: 5, ActivateAt: t0.Add(time.Hour), ExpireAt: t0.Add(2 * time.Hour)})
+	assert.NoError(t, err)
+	now = t0.Add(time.Hour)
+	mustTick(t, sc, 1)
+	assert.Exactly(t, "5", mustString(t, srv, p))
+	assert.NoError(t, sc.Cancel(id))
+	assert.Exactly(t, "10", mustString(t, srv, p), "Cancel reverts an active change")
+
+	// the scheduler was not running during the whole time range
+	_, err = sc.Schedule(cfgschedule.Change{Path: p, Value: 1, ActivateAt: t0.Add(2 * time.Hour), ExpireAt: t0.Add(3 * time.Hour)})
+	assert.NoError(t, err)
+	now = t0.Add(4 * time.Hour)
+	mustTick(t, sc, 0)
+	assert.Exactly(t, "10", mustString(t, srv, p))
+	assert.Empty(t, sc.Changes())
+}
+
+func TestSchedulerScheduleErrors(t *testing.T) {
+	sc := cfgschedule.MustNewScheduler(config.MustNewService(config.NewInMemoryStore()))
+	p := cfgpath.MustNewByParts("sales/minimum_order/amount")
+	t0 := time.Date(2016, 11, 25, 0, 0, 0, 0, time.UTC)
+
+	_, err := sc.Schedule(cfgschedule.Change{Path: p, Value: 1})
+	assert.True(t, errors.IsNotValid(err), "%+v", err)
+	_, err = sc.Schedule(cfgschedule.Change{Path: p, Value: 1, ActivateAt: t0, ExpireAt: t0})
+	assert.True(t, errors.IsNotValid(err), "%+v", err)
+	_, err = sc.Schedule(cfgschedule.Change{Value: 1, ActivateAt: t0})
+	assert.Error(t, err)
+}
+
+// flakyWriter fails to write the scheduled values, and if failChanges is
+// true, also the stored changes.
+type flakyWriter struct {
+	*config.Service
+	fail        bool
+	failChanges bool
+}
+
+func (fw *flakyWriter) Write(p cfgpath.Path, v interface{}) error {
+	isChanges := p.Route.String() == cfgschedule.RouteChanges
+	if (fw.fail && !isChanges) || (fw.failChanges && isChanges) {
+		return errors.NewWriteFailedf("[cfgschedule_test] flaky")
+	}
+	return fw.Service.Write(p, v)
+}
+
+func TestSchedulerRetry(t *testing.T) {
+	fw := &flakyWriter{Service: config.MustNewService(config.NewInMemoryStore()), fail: true}
+	p := cfgpath.MustNewByParts("sales/minimum_order/amount")
+	t0 := time.Date(2016, 11, 25, 0, 0, 0, 0, time.UTC)
+
+	sc := cfgschedule.MustNewScheduler(fw)
+	sc.Clock = func() time.Time { return t0 }
+	_, err := sc.Schedule(cfgschedule.Change{Path: p, Value: 7, ActivateAt: t0, ExpireAt: t0.Add(time.Hour)})
+	assert.NoError(t, err)
+
+	n, err := sc.Tick()
+	assert.True(t, errors.IsWriteFailed(err), "%+v", err)
+	assert.Exactly(t, 0, n)
+	assert.Exactly(t, cfgschedule.StatePending, sc.Changes()[0].State)
+
+	fw.fail = false
+	mustTick(t, sc, 1)
+	assert.Exactly(t, "7", mustString(t, fw.Service, p))
+}
+
+func TestSchedulerReload(t *testing.T) {
+	srv := config.MustNewService(config.NewInMemoryStore())
+	p := cfgpath.MustNewByParts("sales/minimum_order/amount").BindStore(2)
+	pPending := cfgpath.MustNewByParts("sales/minimum_order/description")
+	assert.NoError(t, srv.Write(p, 50))
+
+	t0 := time.Date(2016, 11, 25, 0, 0, 0, 0, time.UTC)
+	now := t0
+	sc := cfgschedule.MustNewScheduler(srv)
+	sc.Clock = func() time.Time { return now }
+
+	id1, err := sc.Schedule(cfgschedule.Change{Path: p, Value: 10, ActivateAt: t0, ExpireAt: t0.Add(time.Hour)})
+	assert.NoError(t, err)
+	id2, err := sc.Schedule(cfgschedule.Change{Path: pPending, Value: "Sale", ActivateAt: t0.Add(time.Hour)})
+	assert.NoError(t, err)
+	mustTick(t, sc, 1)
+	assert.Exactly(t, "10", mustString(t, srv, p))
+
+	// a restart loads the active and the pending change
+	sc = cfgschedule.MustNewScheduler(srv)
+	sc.Clock = func() time.Time { return now }
+	cs := sc.Changes()
+	assert.Len(t, cs, 2)
+	assert.Exactly(t, id1, cs[0].ID)
+	assert.Exactly(t, p.String(), cs[0].Path.String())
+	assert.Exactly(t, cfgschedule.StateActive, cs[0].State)
+	assert.Exactly(t, id2, cs[1].ID)
+	assert.Exactly(t, cfgschedule.StatePending, cs[1].State)
+
+	id3, err := sc.Schedule(cfgschedule.Change{Path: p, Value: 5, ActivateAt: t0.Add(2 * time.Hour)})
+	assert.NoError(t, err)
+	assert.True(t, id3 > id2, "IDs must continue after a restart")
+
+	now = t0.Add(time.Hour)
+	mustTick(t, sc, 2)
+	assert.Exactly(t, "50", mustString(t, srv, p), "Previous value must survive a restart")
+	assert.Exactly(t, "Sale", mustString(t, srv, pPending))
+
+	sc = cfgschedule.MustNewScheduler(srv)
+	assert.Len(t, sc.Changes(), 1)
+	assert.NoError(t, sc.Cancel(id3))
+	assert.Empty(t, cfgschedule.MustNewScheduler(srv).Changes())
+}
+
+func TestSchedulerLoadInvalid(t *testing.T) {
+	srv := config.MustNewService(config.NewInMemoryStore())
+	assert.NoError(t, srv.Write(cfgpath.MustNewByParts(cfgschedule.RouteChanges), "{"))
+	sc, err := cfgschedule.NewScheduler(srv)
+	assert.Nil(t, sc)
+	assert.True(t, errors.IsNotValid(err), "%+v", err)
+}
+
+func TestSchedulerSaveFails(t *testing.T) {
+	fw := &flakyWriter{Service: config.MustNewService(config.NewInMemoryStore()), failChanges: true}
+	p := cfgpath.MustNewByParts("sales/minimum_order/amount")
+	t0 := time.Date(2016, 11, 25, 0, 0, 0, 0, time.UTC)
+
+	sc := cfgschedule.MustNewScheduler(fw)
+	_, err := sc.Schedule(cfgschedule.Change{Path: p, Value: 7, ActivateAt: t0})
+	assert.True(t, errors.IsWriteFailed(err), "%+v", err)
+	assert.Empty(t, sc.Changes(), "A change which cannot be stored must not be scheduled")
+}
+
+func TestSchedulerStartStop(t *testing.T) {
+	sc := cfgschedule.MustNewScheduler(config.MustNewService(config.NewInMemoryStore()))
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			sc.Start(time.Millisecond)
+		}()
+		go func() {
+			defer wg.Done()
+			sc.Stop()
+		}()
+	}
+	wg.Wait()
+	sc.Stop()
+	sc.Stop()
+}
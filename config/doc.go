@@ -24,10 +24,63 @@ Scopes are default, website, group and store. Scope IDs are stored in the core_w
 core_group or core_store tables for M1 and store_website, store_group and store for M2.
 
 Underlying storage can be a simple in memory map (default), MySQL table core_config_data
-itself (package config/db) or etcd (package config/etcd) or consul (package todo) or ...
+itself (package config/storage/ccd) or etcd (package config/storage/etcd) or bolt
+(package config/storage/boltdb) or consul (package todo) or ...
 
-If you use any other configuration storage engine besides config/db package all values
-gets bi-directional automatically synchronized (todo).
+If you use any other configuration storage engine besides the config/storage/ccd package
+all values can get bi-directional synchronized with the core_config_data table via
+type ccd.Sync.
+
+Overrides
+
+Like the env.php and config.php locks in Magento 2, type Override loads values from
+environment variables (CS_CONFIG__WEB__UNSECURE__BASE_URL__STORE__2) or from a
+JSON/YAML/TOML file. Apply them via option WithOverride. Those paths are read-only and
+Service.Write rejects any change. Override.Source reports the origin of a value.
+
+Import and Export
+
+Package config/cfgexport exports and imports configuration values from any Storager
+to stable and diffable JSON or YAML files. The command config/cfgexport/csconfig
+applies it to the core_config_data table.
+
+Audit Log
+
+Package config/cfgaudit wraps a Writer and records every change with the old value, new
+value, actor and timestamp. It lists the history of a path and rolls a path back to a
+previous revision.
+
+Admin API
+
+Package config/cfgadmin provides an http.Handler to list the sections, groups
+and fields, to read the effective value of a path for a website or store and to
+write validated values. It also renders HTML forms or a JSON schema of a section
+and parses the submitted forms.
+
+Registry
+
+Packages with a configuration register their element.SectionSlice and their
+cfgmodel values in package config/cfgregistry. At startup an application merges
+the sections of all imported packages, enumerates all known paths and validates
+the whole tree with cfgregistry.Build.
+
+The static analyzer in package config/cfglint and its command cscfglint check
+the routes passed to the cfgmodel constructors and to cfgpath.MustNewByParts
+against the registered sections and the known paths of Magento 2 and report
+unknown routes, wrong scopes and type mismatches.
+
+Magento 1 Migration
+
+Package config/cfgmigrate translates paths and values between the
+core_config_data tables of Magento 1 and Magento 2, either as a one-shot
+migration or as a read-through Storager on top of the other version.
+
+Scheduled Changes
+
+Package config/cfgschedule writes values at a future point in time, for example a
+campaign from Black Friday to Cyber Monday, and restores the previous value once the
+change expires. All writes run through the Service and trigger the pub/sub system.
+The scheduled changes get stored in the configuration itself and survive a restart.
 
 Elements
 
@@ -39,6 +92,28 @@ To get a value from the configuration Service via any type method you have to pr
 path.Path. If you use the ScopedGetter via function NewScoped() you can only provide a
 path.Route to the type methods String(), Int(), Float64(), etc.
 
+Publish and Subscribe
+
+Option WithPubSub starts a goroutine which notifies all MessageReceivers about
+written paths. Subscribe listens to a route or to parts of it. SubscribePattern
+supports wildcards within the levels of a route, restricts messages to scopes and
+collects bursts of changes into one call of a BatchReceiver. Option
+WithPubSubBackPressure queues the paths and waits only a bounded time for a free
+slot, instead of blocking Service.Write until a slow subscriber has finished. A
+path which does not fit into the queue lets Service.Write return an error with
+behaviour Temporary.
+
+The pub/sub system works only in-process. Option WithTransport sends all written
+paths to the other nodes of a cluster. Package config/cfgredis provides a
+Transport via Redis PUBLISH and SUBSCRIBE.
+
+Request Snapshots
+
+A single request asks many times for the same paths. Service.Snapshot returns a Scoped
+which memoizes all resolved values and NotFound errors. Every Service.Write or
+Service.Publish invalidates the memoized values. See bm_baseline.txt for the reduced
+allocations.
+
 The examples show the overall best practices.
 */
 package config
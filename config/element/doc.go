@@ -68,4 +68,8 @@
 // The JSON encoding of the three elements Section, Group and Field are intended
 // to use on the backend REST API and for debugging and testing. Only used in
 // non performance critical parts.
+//
+// Type ValidatingWriter wraps a config.Writer and validates all writes against
+// the Field definitions of a SectionSlice: scope permissions, type coercion per
+// FieldType and source.Slice options of select and multiselect fields.
 package element
@@ -54,7 +54,8 @@ type Field struct {
 	// In Magento2 they do not have an entry in the system.xml
 	Visible Visible `json:",omitempty"`
 
-	// CanBeEmpty only used in HTML forms for multiselect fields
+	// CanBeEmpty only used for multiselect fields. The ValidatingWriter
+	// rejects an empty multiselect value if false.
 	// Use case: lib/internal/Magento/Framework/Data/Form/Element/Multiselect.php::getElementHtml()
 	CanBeEmpty bool `json:",omitempty"`
 	// Default can contain any default config value: float64, int64, string, bool
@@ -0,0 +1,273 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package element
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/config/source"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/conv"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// multiselectSeparator separates the values of a multiselect field in the
+// storage, same as in Magento.
+const multiselectSeparator = ","
+
+// ValidatingWriter checks a value against the Field definition of its path
+// before writing it to the underlying ConfigurationWriter. It rejects writes
+// in disallowed scopes, coerces the value depending on the FieldType and
+// checks for select and multiselect fields if the value is part of the
+// registered source.Slice. An empty multiselect value gets rejected unless
+// the Field sets CanBeEmpty. ValidatingWriter implements the config.Writer
+// interface and is safe for concurrent use after all sources have been set.
+type ValidatingWriter struct {
+	// AllowUnknown writes values of paths without a Field definition without
+	// validation. Default false rejects them with a NotFound error.
+	AllowUnknown bool
+	w            ConfigurationWriter
+	fields       map[string]Field
+	sources      map[string]source.Slice
+}
+
+// NewValidatingWriter creates a new validating writer for the fields of the
+// SectionSlice. The route of a Field gets calculated by Field.Route, so a
+// Field.ConfigPath is taken into account.
+func NewValidatingWriter(w ConfigurationWriter, ss SectionSlice) (*ValidatingWriter, error) {
+	vw := &ValidatingWriter{
+		w:       w,
+		fields:  make(map[string]Field, ss.TotalFields()),
+		sources: make(map[string]source.Slice),
+	}
+	for _, s := range ss {
+		for _, g := range s.Groups {
+			for _, f := range g.Fields {
+				r, err := f.Route(s.ID, g.ID)
+				if err != nil {
+					return nil, errors.Wrapf(err, "[element] NewValidatingWriter.Field.Route. Section %q Group %q", s.ID, g.ID)
+				}
+				vw.fields[r.String()] = f
+			}
+		}
+	}
+	return vw, nil
+}
+
+// SetSource registers the allowed options of a select or multiselect field.
+// Not thread safe. Error behaviour: NotFound.
+func (vw *ValidatingWriter) SetSource(r cfgpath.Route, vl source.Slice) error {
+	if _, ok := vw.fields[r.String()]; !ok {
+		return errors.NewNotFoundf("[element] ValidatingWriter.SetSource Field for Route %q not found", r)
+	}
+	vw.sources[r.String()] = vl
+	return nil
+}
+
+// Write validates the value and writes the coerced value to the underlying
+// writer. Returns the error of Validate.
+func (vw *ValidatingWriter) Write(p cfgpath.Path, v interface{}) error {
+	cv, err := vw.Validate(p, v)
+	if err != nil {
+		return err
+	}
+	return errors.Wrapf(vw.w.Write(p, cv), "[element] ValidatingWriter.Write %q", p)
+}
+
+// Validate checks the value for the path and returns the coerced value. A
+// failed validation returns an *errors.MultiErr which contains all found
+// errors. Use errors.MultiErrContainsAny to check for the behaviour:
+// Unauthorized for a disallowed scope, NotValid for a wrong value or
+// NotSupported for fields which cannot store a value. An unknown path
+// returns a NotFound error if AllowUnknown is false.
+func (vw *ValidatingWriter) Validate(p cfgpath.Path, v interface{}) (interface{}, error) {
+	route := p.Route.String()
+	f, ok := vw.fields[route]
+	if !ok {
+		if vw.AllowUnknown {
+			return v, nil
+		}
+		return nil, errors.NewNotFoundf("[element] ValidatingWriter Field for Route %q not found", p.Route)
+	}
+
+	var mErr *errors.MultiErr
+
+	perm := f.Scopes
+	if perm == 0 {
+		perm = scope.PermDefault
+	}
+	if s := p.ScopeID.Type(); !perm.Has(s) {
+		mErr = mErr.AppendErrors(errors.NewUnauthorizedf("[element] Path %q: Scope %q not allowed. Allowed: %q", p, s, perm))
+	}
+
+	cv, err := coerce(f, v)
+	if err != nil {
+		mErr = mErr.AppendErrors(errors.Wrapf(err, "[element] Path %q", p))
+	}
+	if err == nil {
+		mErr = mErr.AppendErrors(vw.validateOptions(p, f, cv)...)
+	}
+
+	if mErr.HasErrors() {
+		return nil, mErr
+	}
+	return cv, nil
+}
+
+// validateOptions checks the source membership for select and multiselect
+// fields.
+func (vw *ValidatingWriter) validateOptions(p cfgpath.Path, f Field, cv interface{}) []error {
+	var ft FieldType
+	if f.Type != nil {
+		ft = f.Type.Type()
+	}
+	var vals []string
+	switch ft {
+	case TypeSelect:
+		vals = []string{cv.(string)}
+	case TypeMultiselect:
+		if s := cv.(string); s != "" {
+			vals = strings.Split(s, multiselectSeparator)
+		}
+		if len(vals) == 0 && !f.CanBeEmpty {
+			return []error{errors.NewNotValidf("[element] Path %q: Multiselect value cannot be empty", p)}
+		}
+	default:
+		return nil
+	}
+
+	vl, ok := vw.sources[p.Route.String()]
+	if !ok {
+		return nil
+	}
+	var errs []error
+	for _, val := range vals {
+		if !containsValue(vl, val) {
+			errs = append(errs, errors.NewNotValidf("[element] Path %q: The value %q cannot be found within the allowed options: %s", p, val, vl))
+		}
+	}
+	return errs
+}
+
+func containsValue(vl source.Slice, val string) bool {
+	for _, pair := range vl {
+		if pair.Value() == val {
+			return true
+		}
+		switch pair.NotNull {
+		case source.NotNullFloat64:
+			if f, err := strconv.ParseFloat(val, 64); err == nil && vl.ContainsValFloat64(f) {
+				return true
+			}
+		case source.NotNullBool:
+			if b, err := strconv.ParseBool(val); err == nil && b == pair.Bool {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// coerce converts the value into the type which gets stored for a FieldType.
+// Error behaviour: NotValid or NotSupported.
+func coerce(f Field, v interface{}) (interface{}, error) {
+	var ft FieldType
+	if f.Type != nil {
+		ft = f.Type.Type()
+	}
+	switch ft {
+	case TypeButton, TypeLabel:
+		return nil, errors.NewNotSupportedf("[element] Field %q of type %s cannot store a value", f.ID, ft)
+
+	case TypeText, TypeTextarea, TypeObscure, TypeHidden, TypeSelect:
+		if b, ok := v.(bool); ok && ft == TypeSelect {
+			// Magento stores the yes/no options as 1 and 0.
+			if b {
+				return "1", nil
+			}
+			return "0", nil
+		}
+		s, err := conv.ToStringE(v)
+		if err != nil {
+			return nil, errors.NewNotValidf("[element] Field %q of type %s: Cannot convert %T to string: %s", f.ID, ft, v, err)
+		}
+		return s, nil
+
+	case TypeMultiselect:
+		return coerceMultiselect(f, v)
+
+	case TypeTime:
+		return coerceTime(f, v)
+
+	case TypeDuration:
+		switch vt := v.(type) {
+		case time.Duration:
+			return vt, nil
+		case string:
+			d, err := time.ParseDuration(vt)
+			if err != nil {
+				return nil, errors.NewNotValidf("[element] Field %q of type %s: %s", f.ID, ft, err)
+			}
+			return d, nil
+		}
+		return nil, errors.NewNotValidf("[element] Field %q of type %s: Unsupported type %T", f.ID, ft, v)
+	}
+	// TypeCustom, TypeImage and unset types won't be touched.
+	return v, nil
+}
+
+func coerceMultiselect(f Field, v interface{}) (interface{}, error) {
+	switch vt := v.(type) {
+	case []string:
+		return strings.Join(vt, multiselectSeparator), nil
+	case []int:
+		s := make([]string, len(vt))
+		for i, iv := range vt {
+			s[i] = strconv.Itoa(iv)
+		}
+		return strings.Join(s, multiselectSeparator), nil
+	}
+	s, err := conv.ToStringE(v)
+	if err != nil {
+		return nil, errors.NewNotValidf("[element] Field %q of type %s: Cannot convert %T to string: %s", f.ID, TypeMultiselect, v, err)
+	}
+	return s, nil
+}
+
+// coerceTime returns the Magento time format HH,MM,SS.
+func coerceTime(f Field, v interface{}) (interface{}, error) {
+	switch vt := v.(type) {
+	case time.Time:
+		return fmt.Sprintf("%02d,%02d,%02d", vt.Hour(), vt.Minute(), vt.Second()), nil
+	case string:
+		parts := strings.Split(vt, ",")
+		if len(parts) != 3 {
+			return nil, errors.NewNotValidf("[element] Field %q of type %s: Expecting format HH,MM,SS but got %q", f.ID, TypeTime, vt)
+		}
+		max := [3]int{23, 59, 59}
+		for i, part := range parts {
+			n, err := strconv.Atoi(part)
+			if err != nil || n < 0 || n > max[i] {
+				return nil, errors.NewNotValidf("[element] Field %q of type %s: Invalid value %q", f.ID, TypeTime, vt)
+			}
+		}
+		return vt, nil
+	}
+	return nil, errors.NewNotValidf("[element] Field %q of type %s: Unsupported type %T", f.ID, TypeTime, v)
+}
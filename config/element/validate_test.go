@@ -0,0 +1,182 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package element_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/config/element"
+	"github.com/corestoreio/csfw/config/source"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func newValidatingWriter(t *testing.T) (*element.ValidatingWriter, *cfgmock.Write) {
+	ss := element.MustNewConfiguration(
+		element.Section{
+			ID: cfgpath.NewRoute("general"),
+			Groups: element.NewGroupSlice(
+				element.Group{
+					ID: cfgpath.NewRoute("country"),
+					Fields: element.NewFieldSlice(
+						element.Field{
+							// Path: `general/country/default`,
+							ID:     cfgpath.NewRoute("default"),
+							Type:   element.TypeSelect,
+							Scopes: scope.PermWebsite,
+						},
+						element.Field{
+							// Path: `general/country/allow`,
+							ID:     cfgpath.NewRoute("allow"),
+							Type:   element.TypeMultiselect,
+							Scopes: scope.PermStore,
+						},
+						element.Field{
+							// Path: `general/country/destinations`,
+							ID:         cfgpath.NewRoute("destinations"),
+							Type:       element.TypeMultiselect,
+							Scopes:     scope.PermStore,
+							CanBeEmpty: true,
+						},
+						element.Field{
+							// Path: `general/country/enabled`,
+							ID:     cfgpath.NewRoute("enabled"),
+							Type:   element.TypeSelect,
+							Scopes: scope.PermStore,
+						},
+					),
+				},
+				element.Group{
+					ID: cfgpath.NewRoute("misc"),
+					Fields: element.NewFieldSlice(
+						element.Field{
+							// Path: `general/misc/title`,
+							ID:   cfgpath.NewRoute("title"),
+							Type: element.TypeText,
+						},
+						element.Field{
+							// Path: `general/misc/start`,
+							ID:     cfgpath.NewRoute("start"),
+							Type:   element.TypeTime,
+							Scopes: scope.PermStore,
+						},
+						element.Field{
+							// Path: `general/misc/timeout`,
+							ID:     cfgpath.NewRoute("timeout"),
+							Type:   element.TypeDuration,
+							Scopes: scope.PermStore,
+						},
+						element.Field{
+							// Path: `general/misc/flush`,
+							ID:     cfgpath.NewRoute("flush"),
+							Type:   element.TypeButton,
+							Scopes: scope.PermStore,
+						},
+						element.Field{
+							// Path: `general/misc/custom`,
+							ID:         cfgpath.NewRoute("custom"),
+							ConfigPath: cfgpath.NewRoute("general/other/custom"),
+							Type:       element.TypeCustom,
+							Scopes:     scope.PermStore,
+						},
+					),
+				},
+			),
+		},
+	)
+	w := new(cfgmock.Write)
+	vw, err := element.NewValidatingWriter(w, ss)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	countries := source.MustNewByString("CH", "Switzerland", "DE", "Germany", "AT", "Austria")
+	assert.NoError(t, vw.SetSource(cfgpath.NewRoute("general/country/default"), countries))
+	assert.NoError(t, vw.SetSource(cfgpath.NewRoute("general/country/allow"), countries))
+	assert.NoError(t, vw.SetSource(cfgpath.NewRoute("general/country/enabled"), source.YesNo))
+	err = vw.SetSource(cfgpath.NewRoute("general/country/unknown"), countries)
+	assert.True(t, errors.IsNotFound(err), "Error: %+v", err)
+	return vw, w
+}
+
+func TestValidatingWriter(t *testing.T) {
+	vw, w := newValidatingWriter(t)
+
+	tests := []struct {
+		path     cfgpath.Path
+		value    interface{}
+		wantVal  interface{}
+		wantBhfs []errors.BehaviourFunc
+	}{
+		{cfgpath.MustNewByParts("general/country/default").BindWebsite(1), "CH", "CH", nil},
+		{cfgpath.MustNewByParts("general/country/default").BindWebsite(1), "FR", nil, []errors.BehaviourFunc{errors.IsNotValid}},
+		{cfgpath.MustNewByParts("general/country/default").BindStore(1), "XX", nil, []errors.BehaviourFunc{errors.IsUnauthorized, errors.IsNotValid}},
+		{cfgpath.MustNewByParts("general/country/allow").BindStore(1), []string{"CH", "AT"}, "CH,AT", nil},
+		{cfgpath.MustNewByParts("general/country/allow").BindStore(1), "DE,AT", "DE,AT", nil},
+		{cfgpath.MustNewByParts("general/country/allow").BindStore(1), "DE,IT", nil, []errors.BehaviourFunc{errors.IsNotValid}},
+		{cfgpath.MustNewByParts("general/country/allow").BindStore(1), []string{}, nil, []errors.BehaviourFunc{errors.IsNotValid}},
+		{cfgpath.MustNewByParts("general/country/destinations").BindStore(1), []string{}, "", nil},
+		{cfgpath.MustNewByParts("general/country/destinations").BindStore(1), []int{3, 4}, "3,4", nil},
+		{cfgpath.MustNewByParts("general/country/default").BindWebsite(1), "", nil, []errors.BehaviourFunc{errors.IsNotValid}},
+		{cfgpath.MustNewByParts("general/country/enabled").BindStore(1), true, "1", nil},
+		{cfgpath.MustNewByParts("general/country/enabled").BindStore(1), false, "0", nil},
+		{cfgpath.MustNewByParts("general/country/enabled").BindStore(1), "1", "1", nil},
+		{cfgpath.MustNewByParts("general/country/enabled").BindStore(1), "yes", nil, []errors.BehaviourFunc{errors.IsNotValid}},
+		{cfgpath.MustNewByParts("general/misc/title"), 42, "42", nil},
+		{cfgpath.MustNewByParts("general/misc/title"), "", "", nil},
+		{cfgpath.MustNewByParts("general/misc/title").BindWebsite(2), "T", nil, []errors.BehaviourFunc{errors.IsUnauthorized}},
+		{cfgpath.MustNewByParts("general/misc/title"), struct{}{}, nil, []errors.BehaviourFunc{errors.IsNotValid}},
+		{cfgpath.MustNewByParts("general/misc/start"), "08,30,00", "08,30,00", nil},
+		{cfgpath.MustNewByParts("general/misc/start"), time.Date(2016, 1, 1, 7, 5, 3, 0, time.UTC), "07,05,03", nil},
+		{cfgpath.MustNewByParts("general/misc/start"), "25,00,00", nil, []errors.BehaviourFunc{errors.IsNotValid}},
+		{cfgpath.MustNewByParts("general/misc/timeout"), "1m30s", time.Minute + 30*time.Second, nil},
+		{cfgpath.MustNewByParts("general/misc/timeout"), time.Second, time.Second, nil},
+		{cfgpath.MustNewByParts("general/misc/timeout"), "1 minute", nil, []errors.BehaviourFunc{errors.IsNotValid}},
+		{cfgpath.MustNewByParts("general/misc/flush"), "x", nil, []errors.BehaviourFunc{errors.IsNotSupported}},
+		{cfgpath.MustNewByParts("general/other/custom").BindStore(3), 3.14, 3.14, nil},
+		{cfgpath.MustNewByParts("general/other/custom").BindStore(3), nil, nil, nil},
+	}
+	for i, test := range tests {
+		w.ArgPath, w.ArgValue = "", nil
+		err := vw.Write(test.path, test.value)
+		if test.wantBhfs != nil {
+			assert.True(t, errors.MultiErrContainsAll(err, test.wantBhfs...), "Index %d => %+v", i, err)
+			assert.Empty(t, w.ArgPath, "Index %d", i)
+			continue
+		}
+		assert.NoError(t, err, "Index %d", i)
+		assert.Exactly(t, test.path.String(), w.ArgPath, "Index %d", i)
+		assert.Exactly(t, test.wantVal, w.ArgValue, "Index %d", i)
+	}
+}
+
+func TestValidatingWriterUnknown(t *testing.T) {
+	vw, w := newValidatingWriter(t)
+	p := cfgpath.MustNewByParts("general/misc/custom")
+
+	err := vw.Write(p, "x")
+	assert.True(t, errors.IsNotFound(err), "Error: %+v", err)
+
+	vw.AllowUnknown = true
+	assert.NoError(t, vw.Write(p, "x"))
+	assert.Exactly(t, "default/0/general/misc/custom", w.ArgPath)
+
+	w.WriteError = errors.NewFatalf("DB gone")
+	err = vw.Write(cfgpath.MustNewByParts("general/misc/title"), "Title")
+	assert.True(t, errors.IsFatal(err), "Error: %+v", err)
+}
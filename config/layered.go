@@ -0,0 +1,206 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// Layer a named Storager within a Layered storage.
+type Layer struct {
+	// Name identifies the layer in a Provenance, e.g. "env", "memory",
+	// "bigcache" or "ccd".
+	Name string
+	Storager
+	// ReadOnly layers are skipped by Layered.Set.
+	ReadOnly bool
+}
+
+// Provenance describes which layer and which scope has answered a request to
+// Layered.Resolve.
+type Provenance struct {
+	// Path contains the route and the scope which has been found.
+	Path cfgpath.Path
+	// Layer name of the layer which returned the value.
+	Layer string
+	// LayerIndex position of the layer within the Layered chain.
+	LayerIndex int
+	// Value the raw value as returned by the Storager of the layer.
+	Value interface{}
+}
+
+// String returns a human readable description for debugging purposes.
+func (p Provenance) String() string {
+	return fmt.Sprintf("%s found in layer %q (#%d)", p.Path, p.Layer, p.LayerIndex)
+}
+
+// Layered chains multiple Storager in a fixed order. The first layer has the
+// highest priority, for example: env-var overrides -> in-memory -> bigcache ->
+// core_config_data. Layered implements the Storager interface and can be used
+// as backend for the Service. Layered is safe for concurrent use if all layers
+// are.
+type Layered struct {
+	layers []Layer
+}
+
+// NewLayered creates a new chain of storage layers. The first layer has the
+// highest priority.
+func NewLayered(layers ...Layer) *Layered {
+	return &Layered{
+		layers: layers,
+	}
+}
+
+// Layers returns a copy of the layers.
+func (l *Layered) Layers() []Layer {
+	ls := make([]Layer, len(l.layers))
+	copy(ls, l.layers)
+	return ls
+}
+
+// Set writes the value into all non read-only layers. Returns the first
+// error.
+func (l *Layered) Set(key cfgpath.Path, value interface{}) error {
+	for _, ly := range l.layers {
+		if ly.ReadOnly {
+			continue
+		}
+		if err := ly.Set(key, value); err != nil {
+			return errors.Wrapf(err, "[config] Layered.Set Layer %q Key %q", ly.Name, key)
+		}
+	}
+	return nil
+}
+
+// Get returns the value of the first layer which contains the key. Error
+// behaviour: NotFound.
+func (l *Layered) Get(key cfgpath.Path) (interface{}, error) {
+	pv, err := l.get(key)
+	if err != nil {
+		return nil, err
+	}
+	return pv.Value, nil
+}
+
+func (l *Layered) get(key cfgpath.Path) (Provenance, error) {
+	for i, ly := range l.layers {
+		v, err := ly.Get(key)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return Provenance{}, errors.Wrapf(err, "[config] Layered.Get Layer %q Key %q", ly.Name, key)
+		}
+		return Provenance{
+			Path:       key,
+			Layer:      ly.Name,
+			LayerIndex: i,
+			Value:      v,
+		}, nil
+	}
+	return Provenance{}, errors.NewNotFoundf("[config] Layered Key %q not found in %d layers", key, len(l.layers))
+}
+
+// AllKeys returns the de-duplicated keys of all layers.
+func (l *Layered) AllKeys() (cfgpath.PathSlice, error) {
+	var ret cfgpath.PathSlice
+	seen := make(map[uint32]bool)
+	for _, ly := range l.layers {
+		keys, err := ly.AllKeys()
+		if err != nil {
+			return nil, errors.Wrapf(err, "[config] Layered.AllKeys Layer %q", ly.Name)
+		}
+		for _, k := range keys {
+			h, err := k.Hash(-1)
+			if err != nil {
+				return nil, errors.Wrapf(err, "[config] Layered.AllKeys Layer %q Key %q", ly.Name, k)
+			}
+			if seen[h] {
+				continue
+			}
+			seen[h] = true
+			ret = append(ret, k)
+		}
+	}
+	return ret, nil
+}
+
+// resolveScopeIDs returns the scopes in the order store -> website -> default.
+func resolveScopeIDs(websiteID, storeID int64) scope.TypeIDs {
+	ids := make(scope.TypeIDs, 0, 3)
+	if storeID > 0 {
+		ids = append(ids, scope.Store.Pack(storeID))
+	}
+	if websiteID > 0 {
+		ids = append(ids, scope.Website.Pack(websiteID))
+	}
+	return append(ids, scope.DefaultTypeID)
+}
+
+// Resolve looks up the route for a store in one call and traverses the scopes
+// store -> website -> default. Within each scope all layers are getting
+// queried in their order. The returned Provenance reports which layer and
+// which scope has answered. An empty storeID starts at the website scope and
+// an empty websiteID and storeID query only the default scope. Error
+// behaviour: NotFound.
+func (l *Layered) Resolve(r cfgpath.Route, websiteID, storeID int64) (Provenance, error) {
+	p, err := cfgpath.New(r)
+	if err != nil {
+		return Provenance{}, errors.Wrapf(err, "[config] Layered.Resolve Route %q", r)
+	}
+	for _, id := range resolveScopeIDs(websiteID, storeID) {
+		pv, err := l.get(p.Bind(id))
+		if errors.IsNotFound(err) {
+			continue
+		}
+		return pv, err
+	}
+	return Provenance{}, errors.NewNotFoundf("[config] Layered.Resolve Route %q not found for Website %d and Store %d", r, websiteID, storeID)
+}
+
+// Trace returns all values which are available for the route in all scopes
+// and all layers. The first entry is equal to the result of Resolve. Trace
+// helps to debug misconfigured stores because it shows which values are
+// shadowed by a higher priority layer or a more specific scope.
+func (l *Layered) Trace(r cfgpath.Route, websiteID, storeID int64) ([]Provenance, error) {
+	p, err := cfgpath.New(r)
+	if err != nil {
+		return nil, errors.Wrapf(err, "[config] Layered.Trace Route %q", r)
+	}
+	var ret []Provenance
+	for _, id := range resolveScopeIDs(websiteID, storeID) {
+		key := p.Bind(id)
+		for i, ly := range l.layers {
+			v, err := ly.Get(key)
+			if errors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return nil, errors.Wrapf(err, "[config] Layered.Trace Layer %q Key %q", ly.Name, key)
+			}
+			ret = append(ret, Provenance{
+				Path:       key,
+				Layer:      ly.Name,
+				LayerIndex: i,
+				Value:      v,
+			})
+		}
+	}
+	return ret, nil
+}
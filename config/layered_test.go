@@ -0,0 +1,127 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_test
+
+import (
+	"testing"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ config.Storager = (*config.Layered)(nil)
+
+func newTestLayered() (*config.Layered, config.Storager, config.Storager) {
+	env := cfgmock.NewService(cfgmock.PathValue{
+		"default/0/web/unsecure/base_url": "http://env.corestore.io",
+	}).Storage
+	mem := cfgmock.NewService(cfgmock.PathValue{
+		"default/0/web/unsecure/base_url": "http://mem.corestore.io",
+		"websites/1/web/secure/base_url":  "https://website1.corestore.io",
+		"default/0/web/secure/base_url":   "https://corestore.io",
+	}).Storage
+	db := cfgmock.NewService(cfgmock.PathValue{
+		"stores/2/web/unsecure/base_url": "http://store2.corestore.io",
+		"default/0/web/secure/base_url":  "https://db.corestore.io",
+	}).Storage
+	return config.NewLayered(
+		config.Layer{Name: "env", Storager: env, ReadOnly: true},
+		config.Layer{Name: "memory", Storager: mem},
+		config.Layer{Name: "ccd", Storager: db},
+	), mem, db
+}
+
+func TestLayeredResolve(t *testing.T) {
+	l, _, _ := newTestLayered()
+
+	tests := []struct {
+		route     string
+		websiteID int64
+		storeID   int64
+		wantPath  string
+		wantLayer string
+		wantIdx   int
+		wantValue interface{}
+	}{
+		{"web/unsecure/base_url", 1, 2, "stores/2/web/unsecure/base_url", "ccd", 2, "http://store2.corestore.io"},
+		{"web/unsecure/base_url", 1, 3, "default/0/web/unsecure/base_url", "env", 0, "http://env.corestore.io"},
+		{"web/secure/base_url", 1, 2, "websites/1/web/secure/base_url", "memory", 1, "https://website1.corestore.io"},
+		{"web/secure/base_url", 2, 4, "default/0/web/secure/base_url", "memory", 1, "https://corestore.io"},
+		{"web/secure/base_url", 0, 0, "default/0/web/secure/base_url", "memory", 1, "https://corestore.io"},
+	}
+	for i, test := range tests {
+		pv, err := l.Resolve(cfgpath.NewRoute(test.route), test.websiteID, test.storeID)
+		assert.NoError(t, err, "Index %d", i)
+		assert.Exactly(t, test.wantPath, pv.Path.String(), "Index %d", i)
+		assert.Exactly(t, test.wantLayer, pv.Layer, "Index %d", i)
+		assert.Exactly(t, test.wantIdx, pv.LayerIndex, "Index %d", i)
+		assert.Exactly(t, test.wantValue, pv.Value, "Index %d", i)
+	}
+
+	pv, err := l.Resolve(cfgpath.NewRoute("aa/bb/cc"), 1, 2)
+	assert.True(t, errors.IsNotFound(err), "Error: %+v", err)
+	assert.Empty(t, pv.Layer)
+
+	_, err = l.Resolve(cfgpath.NewRoute("a/b"), 1, 2)
+	assert.True(t, errors.IsNotValid(errors.Cause(err)), "Error: %+v", err)
+}
+
+func TestLayeredTrace(t *testing.T) {
+	l, _, _ := newTestLayered()
+
+	pvs, err := l.Trace(cfgpath.NewRoute("web/unsecure/base_url"), 1, 2)
+	assert.NoError(t, err)
+	var have []string
+	for _, pv := range pvs {
+		have = append(have, pv.String())
+	}
+	assert.Exactly(t, []string{
+		`stores/2/web/unsecure/base_url found in layer "ccd" (#2)`,
+		`default/0/web/unsecure/base_url found in layer "env" (#0)`,
+		`default/0/web/unsecure/base_url found in layer "memory" (#1)`,
+	}, have)
+}
+
+func TestLayeredStorager(t *testing.T) {
+	l, mem, db := newTestLayered()
+
+	p := cfgpath.MustNewByParts("web/unsecure/base_url")
+	v, err := l.Get(p)
+	assert.NoError(t, err)
+	assert.Exactly(t, "http://env.corestore.io", v)
+
+	p2 := cfgpath.MustNewByParts("web/cookie/cookie_path").BindStore(5)
+	assert.NoError(t, l.Set(p2, "/shop"))
+	for _, s := range []config.Storager{mem, db, l} {
+		v, err = s.Get(p2)
+		assert.NoError(t, err)
+		assert.Exactly(t, "/shop", v)
+	}
+
+	keys, err := l.AllKeys()
+	assert.NoError(t, err)
+	assert.Len(t, keys, 5)
+
+	_, err = l.Get(cfgpath.MustNewByParts("aa/bb/cc"))
+	assert.True(t, errors.IsNotFound(err), "Error: %+v", err)
+
+	srv := config.MustNewService(l)
+	str, err := srv.NewScoped(1, 5).String(cfgpath.NewRoute("web/cookie/cookie_path"))
+	assert.NoError(t, err)
+	assert.Exactly(t, "/shop", str)
+}
@@ -15,6 +15,8 @@
 package config
 
 import (
+	"time"
+
 	"github.com/corestoreio/csfw/log"
 	"github.com/corestoreio/csfw/util/errors"
 )
@@ -44,7 +46,7 @@ func WithPubSub() Option {
 			return errors.NewAlreadyExistsf("[config] PubSub Service already exists and is running.")
 		}
 
-		s.pubSub = newPubSub(s.Log)
+		s.pubSub = newPubSub(s.Log, 0)
 
 		// todo: remove this go ... and the programmer must call it. like Serve() function in http.
 		go s.publish() // yes we know how to quit this goroutine, just call Service.Close()
@@ -52,3 +54,63 @@ func WithPubSub() Option {
 		return nil
 	}
 }
+
+// WithPubSubBackPressure starts the internal publish and subscribe service
+// like WithPubSub but with a queue for bufferSize paths. Slow subscribers do
+// not block Service.Write forever: If the queue is full, a write waits at most
+// maxWait for a free slot. Then the path gets dropped, counted in
+// Service.DroppedMessages and Service.Write returns an error with behaviour
+// Temporary: the value has been stored but the subscribers still have the old
+// value, so the caller can retry the write. A maxWait of zero fails
+// immediately.
+func WithPubSubBackPressure(bufferSize int, maxWait time.Duration) Option {
+	return func(s *Service) error {
+		if s.pubSub != nil && !s.pubSub.closed {
+			return errors.NewAlreadyExistsf("[config] PubSub Service already exists and is running.")
+		}
+		if bufferSize < 1 {
+			return errors.NewNotValidf("[config] PubSub bufferSize %d must be greater than zero", bufferSize)
+		}
+
+		s.pubSub = newPubSub(s.Log, bufferSize)
+		s.pubSub.blocking = false
+		s.pubSub.maxWait = maxWait
+
+		go s.publish()
+
+		return nil
+	}
+}
+
+// WithOverride applies environment variable and file based overrides to the
+// Service. The Override gets placed as a read-only layer in front of the
+// current backend, see Layered, and all paths of the Override are rejected by
+// Service.Write. The Override gets applied after all other options of the
+// same NewService or Service.Options call, so the order of the options does
+// not matter. Apply this option only once.
+func WithOverride(o *Override) Option {
+	return func(s *Service) error {
+		if s.override != nil || s.pendingOverride != nil {
+			return errors.NewAlreadyExistsf("[config] Override already applied")
+		}
+		s.pendingOverride = o
+		return nil
+	}
+}
+
+// WithTransport distributes all paths written via Service.Write to the other
+// nodes of a cluster. Paths received from other nodes are getting published
+// to the local subscribers via Service.Publish and invalidate all Snapshots.
+// Service.Close closes the Transport.
+func WithTransport(t Transport) Option {
+	return func(s *Service) error {
+		if s.transport != nil {
+			return errors.NewAlreadyExistsf("[config] Transport already set")
+		}
+		if err := t.Listen(s); err != nil {
+			return errors.Wrap(err, "[config] WithTransport.Listen")
+		}
+		s.transport = t
+		return nil
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// EnvPrefix defines the prefix of the environment variables which gets
+// loaded by Override.LoadEnvironment. Parts of the route are separated by a
+// double underscore and an optional scope can be appended with
+// __STORE__<ID>, __WEBSITE__<ID> or __DEFAULT__0. For example:
+//		CS_CONFIG__WEB__UNSECURE__BASE_URL=http://corestore.io
+//		CS_CONFIG__WEB__UNSECURE__BASE_URL__STORE__2=http://store2.corestore.io
+const EnvPrefix = "CS_CONFIG__"
+
+const envSeparator = "__"
+
+type overrideVal struct {
+	path   cfgpath.Path
+	value  interface{}
+	source string
+}
+
+// Override contains configuration values which have been provided by the
+// environment or by a file and which cannot be changed during runtime. It's
+// the equivalent of Magentos env.php and config.php locks. Override implements
+// the Storager interface but all calls to Set are getting rejected. Use the
+// option function WithOverride to apply the Override to a Service. Override is
+// safe for concurrent use.
+type Override struct {
+	mu sync.RWMutex
+	kv map[uint32]overrideVal
+}
+
+// NewOverride creates a new empty Override. Call LoadEnvironment and/or
+// LoadFile to populate it. Values loaded later overwrite earlier ones.
+func NewOverride() *Override {
+	return &Override{
+		kv: make(map[uint32]overrideVal),
+	}
+}
+
+func (o *Override) add(p cfgpath.Path, v interface{}, source string) error {
+	h32, err := p.Hash(-1)
+	if err != nil {
+		return errors.Wrapf(err, "[config] Override Path %q from %q", p, source)
+	}
+	o.mu.Lock()
+	o.kv[h32] = overrideVal{path: p, value: v, source: source}
+	o.mu.Unlock()
+	return nil
+}
+
+// LoadEnvironment parses the environment variables which start with the
+// prefix. An empty prefix falls back to EnvPrefix and a nil environ to
+// os.Environ(). The variable names get converted to lower case routes. The
+// source of an entry will be "env:" plus the variable name. Error behaviour:
+// NotValid.
+func (o *Override) LoadEnvironment(prefix string, environ []string) error {
+	if prefix == "" {
+		prefix = EnvPrefix
+	}
+	if environ == nil {
+		environ = os.Environ()
+	}
+	for _, kv := range environ {
+		if !strings.HasPrefix(kv, prefix) {
+			continue
+		}
+		eq := strings.IndexByte(kv, '=')
+		if eq < 0 {
+			continue
+		}
+		name, value := kv[:eq], kv[eq+1:]
+		p, err := envToPath(name[len(prefix):])
+		if err != nil {
+			return errors.Wrapf(err, "[config] Override.LoadEnvironment Variable %q", name)
+		}
+		if err := o.add(p, value, "env:"+name); err != nil {
+			return errors.Wrap(err, "[config] Override.LoadEnvironment")
+		}
+	}
+	return nil
+}
+
+// envToPath converts WEB__UNSECURE__BASE_URL__STORE__2 into a path. The last
+// two parts are only treated as scope and scope ID if the last part is an
+// integer and at least three parts for the route remain, so routes like
+// WEB__DEFAULT__FRONT keep all their parts.
+func envToPath(name string) (cfgpath.Path, error) {
+	parts := strings.Split(strings.ToLower(name), envSeparator)
+	scp := scope.DefaultTypeID
+	if l := len(parts); l > 4 {
+		var st scope.Type
+		switch parts[l-2] {
+		case "default":
+			st = scope.Default
+		case "website", "websites":
+			st = scope.Website
+		case "store", "stores":
+			st = scope.Store
+		}
+		if id, err := strconv.ParseInt(parts[l-1], 10, 64); err == nil && st > scope.Absent {
+			scp = scope.MakeTypeID(st, id)
+			parts = parts[:l-2]
+		}
+	}
+	p, err := cfgpath.NewByParts(parts...)
+	if err != nil {
+		return cfgpath.Path{}, errors.Wrap(err, "[config] cfgpath.NewByParts")
+	}
+	return p.Bind(scp), nil
+}
+
+// LoadFile reads a file and decodes it with the unmarshal function. If
+// unmarshal is nil, encoding/json will be used. YAML or TOML files can be
+// loaded by passing for example yaml.Unmarshal or toml.Unmarshal. The file
+// must contain a flat map where the keys are either a route, which applies to
+// the default scope, or a fully qualified path like:
+//		{
+//			"web/unsecure/base_url": "http://corestore.io",
+//			"stores/2/web/unsecure/base_url": "http://store2.corestore.io"
+//		}
+// The source of an entry will be "file:" plus the file name. Error behaviour:
+// NotValid, NotFound or any other error from the file system.
+func (o *Override) LoadFile(filename string, unmarshal func([]byte, interface{}) error) error {
+	data, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return errors.NewNotFound(err, "[config] Override.LoadFile")
+	}
+	if err != nil {
+		return errors.Wrapf(err, "[config] Override.LoadFile %q", filename)
+	}
+	if unmarshal == nil {
+		unmarshal = json.Unmarshal
+	}
+	var kv map[string]interface{}
+	if err := unmarshal(data, &kv); err != nil {
+		return errors.NewNotValid(err, "[config] Override.LoadFile.Unmarshal")
+	}
+	for key, v := range kv {
+		p, err := overrideKeyToPath(key)
+		if err != nil {
+			return errors.Wrapf(err, "[config] Override.LoadFile %q Key %q", filename, key)
+		}
+		if err := o.add(p, v, "file:"+filename); err != nil {
+			return errors.Wrap(err, "[config] Override.LoadFile")
+		}
+	}
+	return nil
+}
+
+// overrideKeyToPath accepts a route or a fully qualified path.
+func overrideKeyToPath(key string) (cfgpath.Path, error) {
+	if i := strings.IndexByte(key, '/'); i > 0 && scope.Valid(key[:i]) {
+		return cfgpath.SplitFQ(key)
+	}
+	return cfgpath.NewByParts(key)
+}
+
+// Source returns the origin of an overridden path, e.g.
+// "env:CS_CONFIG__WEB__UNSECURE__BASE_URL__STORE__2" or
+// "file:/etc/corestore/config.json". The boolean is false if the path has not
+// been overridden.
+func (o *Override) Source(p cfgpath.Path) (string, bool) {
+	h32, err := p.Hash(-1)
+	if err != nil {
+		return "", false
+	}
+	o.mu.RLock()
+	ov, ok := o.kv[h32]
+	o.mu.RUnlock()
+	return ov.source, ok
+}
+
+// Set implements Storager interface and returns always an error because
+// overridden paths are read-only. Error behaviour: NotSupported.
+func (o *Override) Set(key cfgpath.Path, _ interface{}) error {
+	return errors.NewNotSupportedf("[config] Override Key %q is read-only", key)
+}
+
+// Get implements Storager interface. Error behaviour: NotFound.
+func (o *Override) Get(key cfgpath.Path) (interface{}, error) {
+	h32, err := key.Hash(-1)
+	if err != nil {
+		return nil, errors.Wrap(err, "[config] Override.Get.Hash")
+	}
+	o.mu.RLock()
+	ov, ok := o.kv[h32]
+	o.mu.RUnlock()
+	if !ok {
+		return nil, errors.NewNotFoundf("[config] Override Key %q not found", key)
+	}
+	return ov.value, nil
+}
+
+// AllKeys implements Storager interface
+func (o *Override) AllKeys() (cfgpath.PathSlice, error) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	ret := make(cfgpath.PathSlice, 0, len(o.kv))
+	for _, ov := range o.kv {
+		ret = append(ret, ov.path)
+	}
+	return ret, nil
+}
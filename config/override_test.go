@@ -0,0 +1,181 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ config.Storager = (*config.Override)(nil)
+
+func TestOverrideLoadEnvironment(t *testing.T) {
+	o := config.NewOverride()
+	assert.NoError(t, o.LoadEnvironment("", []string{
+		"PATH=/usr/bin",
+		"CS_CONFIG__WEB__UNSECURE__BASE_URL=http://corestore.io",
+		"CS_CONFIG__WEB__UNSECURE__BASE_URL__STORE__2=http://store2.corestore.io",
+		"CS_CONFIG__GENERAL__LOCALE__CODE__WEBSITES__1=de_CH",
+		"CS_CONFIG__WEB__DEFAULT__FRONT=cms",
+		"CS_CONFIG__WEB__DEFAULT__CMS_HOME_PAGE__STORES__2=home-ch",
+		"CS_CONFIG__WEB__UNSECURE__BASE_URL__STORE__X=http://x.corestore.io",
+	}))
+
+	tests := []struct {
+		p          cfgpath.Path
+		wantVal    string
+		wantSource string
+	}{
+		{cfgpath.MustNewByParts("web/unsecure/base_url"), "http://corestore.io", "env:CS_CONFIG__WEB__UNSECURE__BASE_URL"},
+		{cfgpath.MustNewByParts("web/unsecure/base_url").BindStore(2), "http://store2.corestore.io", "env:CS_CONFIG__WEB__UNSECURE__BASE_URL__STORE__2"},
+		{cfgpath.MustNewByParts("general/locale/code").BindWebsite(1), "de_CH", "env:CS_CONFIG__GENERAL__LOCALE__CODE__WEBSITES__1"},
+		{cfgpath.MustNewByParts("web/default/front"), "cms", "env:CS_CONFIG__WEB__DEFAULT__FRONT"},
+		{cfgpath.MustNewByParts("web/default/cms_home_page").BindStore(2), "home-ch", "env:CS_CONFIG__WEB__DEFAULT__CMS_HOME_PAGE__STORES__2"},
+		// no integer scope ID, so the whole name is the route
+		{cfgpath.MustNewByParts("web/unsecure/base_url/store/x"), "http://x.corestore.io", "env:CS_CONFIG__WEB__UNSECURE__BASE_URL__STORE__X"},
+	}
+	for i, test := range tests {
+		v, err := o.Get(test.p)
+		assert.NoError(t, err, "Index %d", i)
+		assert.Exactly(t, test.wantVal, v, "Index %d", i)
+		src, ok := o.Source(test.p)
+		assert.True(t, ok, "Index %d", i)
+		assert.Exactly(t, test.wantSource, src, "Index %d", i)
+	}
+
+	keys, err := o.AllKeys()
+	assert.NoError(t, err)
+	assert.Len(t, keys, 6)
+
+	_, ok := o.Source(cfgpath.MustNewByParts("web/unsecure/base_url").BindStore(3))
+	assert.False(t, ok)
+	_, err = o.Get(cfgpath.MustNewByParts("web/unsecure/base_url").BindStore(3))
+	assert.True(t, errors.IsNotFound(err), "Error: %+v", err)
+	assert.True(t, errors.IsNotSupported(o.Set(tests[0].p, "x")))
+}
+
+func TestOverrideLoadEnvironmentInvalid(t *testing.T) {
+	o := config.NewOverride()
+	err := o.LoadEnvironment("", []string{"CS_CONFIG__WEB__UNSECURE=a"})
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+
+	err = o.LoadEnvironment("", []string{"CS_CONFIG__WEB=a"})
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+}
+
+func TestOverrideLoadFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cs_override")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	fn := filepath.Join(dir, "config.json")
+	assert.NoError(t, ioutil.WriteFile(fn, []byte(`{
+		"web/unsecure/base_url": "http://corestore.io",
+		"stores/2/web/unsecure/base_url": "http://store2.corestore.io",
+		"web/cookie/cookie_lifetime": 3600
+	}`), 0600))
+
+	o := config.NewOverride()
+	assert.NoError(t, o.LoadFile(fn, nil))
+
+	v, err := o.Get(cfgpath.MustNewByParts("web/unsecure/base_url").BindStore(2))
+	assert.NoError(t, err)
+	assert.Exactly(t, "http://store2.corestore.io", v)
+	v, err = o.Get(cfgpath.MustNewByParts("web/cookie/cookie_lifetime"))
+	assert.NoError(t, err)
+	assert.Exactly(t, 3600.0, v)
+
+	src, ok := o.Source(cfgpath.MustNewByParts("web/unsecure/base_url"))
+	assert.True(t, ok)
+	assert.Exactly(t, "file:"+fn, src)
+
+	err = o.LoadFile(filepath.Join(dir, "missing.json"), nil)
+	assert.True(t, errors.IsNotFound(err), "Error: %+v", err)
+
+	assert.NoError(t, ioutil.WriteFile(fn, []byte(`{"web/unsecure`), 0600))
+	err = o.LoadFile(fn, nil)
+	assert.True(t, errors.IsNotValid(err), "Error: %+v", err)
+}
+
+func TestServiceWithOverride(t *testing.T) {
+	o := config.NewOverride()
+	assert.NoError(t, o.LoadEnvironment("", []string{
+		"CS_CONFIG__WEB__UNSECURE__BASE_URL__STORE__2=http://store2.corestore.io",
+	}))
+	s := config.MustNewService(config.NewInMemoryStore(), config.WithOverride(o))
+
+	pLocked := cfgpath.MustNewByParts("web/unsecure/base_url").BindStore(2)
+	err := s.Write(pLocked, "http://hacked.io")
+	assert.True(t, errors.IsNotSupported(err), "Error: %+v", err)
+	assert.Contains(t, err.Error(), "env:CS_CONFIG__WEB__UNSECURE__BASE_URL__STORE__2")
+
+	pFree := cfgpath.MustNewByParts("web/unsecure/base_url").BindStore(3)
+	assert.NoError(t, s.Write(pFree, "http://store3.corestore.io"))
+
+	sg := s.NewScoped(1, 2)
+	v, err := sg.String(cfgpath.NewRoute("web/unsecure/base_url"))
+	assert.NoError(t, err)
+	assert.Exactly(t, "http://store2.corestore.io", v)
+
+	sg = s.NewScoped(1, 3)
+	v, err = sg.String(cfgpath.NewRoute("web/unsecure/base_url"))
+	assert.NoError(t, err)
+	assert.Exactly(t, "http://store3.corestore.io", v)
+
+	err = s.Options(config.WithOverride(o))
+	assert.True(t, errors.IsAlreadyExists(err), "Error: %+v", err)
+}
+
+func TestServiceWithOverrideOrder(t *testing.T) {
+	o := config.NewOverride()
+	assert.NoError(t, o.LoadEnvironment("", []string{
+		"CS_CONFIG__WEB__UNSECURE__BASE_URL__STORE__2=http://store2.corestore.io",
+	}))
+	pLocked := cfgpath.MustNewByParts("web/unsecure/base_url").BindStore(2)
+	// loads the values from a database, like ccd.WithCoreConfigData
+	withDBValues := func(s *config.Service) error {
+		return s.Write(pLocked, "http://db.corestore.io")
+	}
+
+	for i, opts := range [][]config.Option{
+		{config.WithOverride(o), withDBValues},
+		{withDBValues, config.WithOverride(o)},
+	} {
+		s, err := config.NewService(config.NewInMemoryStore(), opts...)
+		if err != nil {
+			t.Fatalf("Index %d: %+v", i, err)
+		}
+		v, err := s.NewScoped(1, 2).String(cfgpath.NewRoute("web/unsecure/base_url"))
+		assert.NoError(t, err, "Index %d", i)
+		assert.Exactly(t, "http://store2.corestore.io", v, "Index %d", i)
+
+		err = s.Write(pLocked, "http://hacked.io")
+		assert.True(t, errors.IsNotSupported(err), "Index %d: %+v", i, err)
+	}
+
+	s := config.MustNewService(config.NewInMemoryStore())
+	err := s.Options(config.WithOverride(o), config.WithOverride(o))
+	assert.True(t, errors.IsAlreadyExists(err), "Error: %+v", err)
+	assert.NoError(t, s.Options(config.WithOverride(o)), "A failed Options call must not apply the Override")
+}
@@ -15,6 +15,7 @@
 package config
 
 import (
+	"sync/atomic"
 	"time"
 
 	"github.com/corestoreio/csfw/config/cfgpath"
@@ -82,14 +83,29 @@ type Storager interface {
 
 // Service main configuration provider. Please use the NewService() function
 type Service struct {
+	// generation gets increased atomically by each call to Publish and
+	// invalidates all Snapshots. Must be the first field to guarantee the 64
+	// bit alignment for atomic operations.
+	generation uint64
+
 	// backend is the underlying data holding provider. Only access it if you
 	// know exactly what you are doing.
 	backend Storager
 
+	// override contains read-only paths which cannot be changed via Write.
+	// Set by option WithOverride.
+	override *Override
+	// pendingOverride gets applied after all options have been run.
+	pendingOverride *Override
+
 	// internal service to provide async pub/sub features while reading/writing
 	// config values.
 	*pubSub
 
+	// transport distributes written paths to other nodes. Set by option
+	// WithTransport.
+	transport Transport
+
 	// Log can be set for debugging purpose. If nil, it panics. Default
 	// log.Blackhole with disabled debug and info logging. You should use the
 	// option function WithLogger because the logger gets also set to the
@@ -140,6 +156,7 @@ func MustNewService(backend Storager, opts ...Option) *Service {
 
 // Options applies service options.
 func (s *Service) Options(opts ...Option) error {
+	defer func() { s.pendingOverride = nil }()
 	for _, opt := range opts {
 		if opt != nil {
 			if err := opt(s); err != nil {
@@ -147,6 +164,13 @@ func (s *Service) Options(opts ...Option) error {
 			}
 		}
 	}
+	if o := s.pendingOverride; o != nil {
+		s.override = o
+		s.backend = NewLayered(
+			Layer{Name: "override", Storager: o, ReadOnly: true},
+			Layer{Name: "backend", Storager: s.backend},
+		)
+	}
 	return nil
 }
 
@@ -155,7 +179,13 @@ func (s *Service) NewScoped(websiteID, storeID int64) Scoped {
 	return NewScoped(s, websiteID, storeID)
 }
 
-// Write puts a value back into the Service. Example usage:
+// Write puts a value back into the Service. Paths which have been locked via
+// option WithOverride cannot be written and return an error with behaviour
+// NotSupported. With option WithTransport the path gets also sent to all other
+// nodes of the cluster; a failed broadcast gets logged with level info. If
+// the queue of option WithPubSubBackPressure is full, the value has been
+// stored but the subscribers have not been notified and an error with
+// behaviour Temporary gets returned. Example usage:
 //		// Default Scope
 //		p, err := cfgpath.NewByParts("currency/option/base") // or use cfgpath.MustNewByParts( ... )
 // 		err := Write(p, "USD")
@@ -172,11 +202,49 @@ func (s *Service) Write(p cfgpath.Path, v interface{}) error {
 		s.Log.Debug("config.Service.Write", log.Stringer("path", p), log.Object("val", v))
 	}
 
+	if s.override != nil {
+		if src, ok := s.override.Source(p); ok {
+			return errors.NewNotSupportedf("[config] Service.Write Path %q is read-only and locked by %q", p, src)
+		}
+	}
+
 	if err := s.backend.Set(p, v); err != nil {
 		return errors.Wrap(err, "[config] sStorage.Set")
 	}
+	pubErr := s.notify(p)
+	if s.transport != nil {
+		// The value has already been stored, so a failed broadcast must not
+		// fail the write. The other nodes see the value once their caches
+		// expire.
+		if err := s.transport.Broadcast(p); err != nil && s.Log.IsInfo() {
+			s.Log.Info("config.Service.Write.Transport.Broadcast", log.Err(err), log.Stringer("path", p))
+		}
+	}
+	if pubErr != nil {
+		return errors.Wrap(pubErr, "[config] Service.Write.Publish")
+	}
+	return nil
+}
+
+// Publish notifies all subscribers about a changed path without writing the
+// value into the backend storage. Storage engines which receive changes from
+// a remote source, for example etcd watchers, use this function to let the
+// MessageReceivers know about remote edits. Publish is a no-op if the pub/sub
+// service has not been started via option WithPubSub. All Snapshots created
+// by this Service are getting invalidated. Publish never forwards the path to
+// the Transport. A path which gets dropped by a full queue, see option
+// WithPubSubBackPressure, gets logged with level info.
+func (s *Service) Publish(p cfgpath.Path) {
+	if err := s.notify(p); err != nil && s.Log.IsInfo() {
+		s.Log.Info("config.Service.Publish.dropped", log.Err(err), log.Stringer("path", p))
+	}
+}
+
+// notify invalidates the Snapshots and sends the path to the pub/sub service.
+func (s *Service) notify(p cfgpath.Path) error {
+	atomic.AddUint64(&s.generation, 1)
 	if s.pubSub != nil {
-		s.sendMsg(p)
+		return s.sendMsg(p)
 	}
 	return nil
 }
@@ -16,6 +16,8 @@ package config
 
 import (
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/corestoreio/csfw/config/cfgpath"
 	"github.com/corestoreio/csfw/log"
@@ -51,19 +53,36 @@ type Subscriber interface {
 	Subscribe(cfgpath.Route, MessageReceiver) (subscriptionID int, err error)
 }
 
+// Publisher sends a changed path to all subscribed MessageReceivers. This
+// interface is implemented by the config.Service and mainly used by storage
+// engines which detect changes of the underlying data without calling
+// Service.Write.
+type Publisher interface {
+	Publish(cfgpath.Path)
+}
+
 // pubSub embedded pointer struct into the Service
 type pubSub struct {
+	// dropped counts the paths which could not be queued, see option
+	// WithPubSubBackPressure. Must be the first field for atomic access.
+	dropped uint64
 	// subMap, subscribed writers are getting called when a write event
 	// will happen. uint64 is the path/route (aka topic) and int the Subscriber ID for later
 	// removal.
 	subMap     map[uint32]map[int]MessageReceiver
 	subAutoInc int // subAutoInc increased whenever a Subscriber has been added
-	mu         sync.RWMutex
-	pubPath    chan cfgpath.Path
-	stop       chan struct{} // terminates the goroutine
-	closeErr   chan error    // this one tells us that the go routine has really been terminated
-	closed     bool          // if Close() has been called the config.Service can still Write() without panic
-	log        log.Logger
+	// patterns contains the subscriptions added via SubscribePattern.
+	patterns map[int]*patternSub
+	mu       sync.RWMutex
+	pubPath  chan cfgpath.Path
+	stop     chan struct{} // terminates the goroutine
+	closeErr chan error    // this one tells us that the go routine has really been terminated
+	closed   bool          // if Close() has been called the config.Service can still Write() without panic
+	// maxWait how long sendMsg waits for a free slot in pubPath. Only used
+	// when blocking is false.
+	maxWait  time.Duration
+	blocking bool
+	log      log.Logger
 }
 
 // Close closes the internal channel for the pubsub Goroutine. Prevents a leaking
@@ -81,7 +100,9 @@ func (s *pubSub) Close() error {
 	close(s.pubPath)
 	close(s.stop)
 	//close(s.closeErr)
-	return <-s.closeErr
+	err := <-s.closeErr
+	s.flushPatterns()
+	return err
 }
 
 // Subscribe adds a Subscriber to be called when a write event happens. See
@@ -117,6 +138,12 @@ func (s *pubSub) Unsubscribe(subscriptionID int) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if ps, ok := s.patterns[subscriptionID]; ok {
+		ps.take()
+		delete(s.patterns, subscriptionID)
+		return nil
+	}
+
 	for path, subs := range s.subMap {
 		if _, ok := subs[subscriptionID]; ok {
 			delete(s.subMap[path], subscriptionID) // mem leaks?
@@ -129,11 +156,47 @@ func (s *pubSub) Unsubscribe(subscriptionID int) error {
 	return nil
 }
 
-// sendMsg sends the arg into the channel
-func (s *pubSub) sendMsg(p cfgpath.Path) {
-	if false == s.closed {
+// DroppedMessages returns the number of paths which have not been delivered to
+// the subscribers because the queue of the pub/sub service was full. See
+// option WithPubSubBackPressure.
+func (s *pubSub) DroppedMessages() uint64 {
+	if s == nil {
+		return 0
+	}
+	return atomic.LoadUint64(&s.dropped)
+}
+
+// sendMsg sends the arg into the channel. Without back-pressure sendMsg blocks
+// until the publish goroutine receives the path. With back-pressure sendMsg
+// waits at most maxWait for a free slot in the queue, then the path gets
+// dropped and counted and an error with behaviour Temporary gets returned.
+func (s *pubSub) sendMsg(p cfgpath.Path) error {
+	if s.closed {
+		return nil
+	}
+	if s.blocking {
 		s.pubPath <- p
+		return nil
+	}
+
+	select {
+	case s.pubPath <- p:
+		return nil
+	default:
 	}
+
+	if s.maxWait > 0 {
+		t := time.NewTimer(s.maxWait)
+		defer t.Stop()
+		select {
+		case s.pubPath <- p:
+			return nil
+		case <-t.C:
+		}
+	}
+
+	d := atomic.AddUint64(&s.dropped, 1)
+	return errors.NewTemporaryf("[config] PubSub queue full: Path %q not delivered to the subscribers after %s. Dropped %d paths", p, s.maxWait, d)
 }
 
 // publish runs in a Goroutine and listens on the channel publishArg. Every time
@@ -155,7 +218,10 @@ func (s *pubSub) publish() {
 				return
 			}
 
-			if len(s.subMap) == 0 {
+			s.mu.RLock()
+			noSubs := len(s.subMap) == 0 && len(s.patterns) == 0
+			s.mu.RUnlock()
+			if noSubs {
 				break
 			}
 
@@ -164,6 +230,7 @@ func (s *pubSub) publish() {
 			evict = append(evict, s.readMapAndSend(p, 1)...)  // e.g.: system and StrScope/ID/system
 			evict = append(evict, s.readMapAndSend(p, 2)...)  // e.g.: system/smtp and StrScope/ID/system/smtp
 			evict = append(evict, s.readMapAndSend(p, -1)...) // e.g.: system/smtp/host/... and StrScope/ID/system/smtp/host/...
+			evict = append(evict, s.sendPatterns(p)...)       // e.g.: payment/*/active
 
 			// remove all failed Subscribers
 			if len(evict) > 0 {
@@ -230,10 +297,12 @@ func (s *pubSub) sendMsgRecoverable(id int, sl MessageReceiver, p cfgpath.Path)
 	return
 }
 
-func newPubSub(l log.Logger) *pubSub {
+func newPubSub(l log.Logger, bufferSize int) *pubSub {
 	return &pubSub{
 		subMap:   make(map[uint32]map[int]MessageReceiver),
-		pubPath:  make(chan cfgpath.Path),
+		patterns: make(map[int]*patternSub),
+		pubPath:  make(chan cfgpath.Path, bufferSize),
+		blocking: true,
 		stop:     make(chan struct{}),
 		closeErr: make(chan error),
 		log:      l,
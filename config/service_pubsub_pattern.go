@@ -0,0 +1,276 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/log"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// BatchReceiver receives all paths which have been changed within the debounce
+// window of a Subscription. Paths are unique within a batch and sorted in the
+// order of their first change. If an error will be returned, the subscriber
+// gets unsubscribed/removed.
+type BatchReceiver interface {
+	MessageConfigBatch(cfgpath.PathSlice) error
+}
+
+// PatternSubscriber subscribes a Subscription with wildcards, scope filters and
+// batching. Implemented by the config.Service.
+type PatternSubscriber interface {
+	// SubscribePattern returns a unique identifier for the Subscription for
+	// later removal via Unsubscribe, or an error.
+	SubscribePattern(Subscription) (subscriptionID int, err error)
+}
+
+// Subscription describes a subscription to many routes at once. Either
+// Receiver or BatchReceiver must be set.
+type Subscription struct {
+	// Pattern a route with wildcards. Each level gets matched with the rules
+	// of path.Match, e.g. "payment/*/active" or "carriers/[ut]ps/*". A pattern
+	// with less than three levels matches all routes which begin with it, the
+	// same as a route in Subscribe does.
+	Pattern string
+	// Scopes restricts the subscription to the listed scopes, e.g.
+	// scope.Store.Pack(5). Empty Scopes match all scopes.
+	Scopes scope.TypeIDs
+	// Receiver gets called for each changed path. Cannot be used together with
+	// a Debounce duration.
+	Receiver MessageReceiver
+	// BatchReceiver gets called with all changed paths. Without a Debounce
+	// duration each batch contains only one path.
+	BatchReceiver BatchReceiver
+	// Debounce collects all changes until no new change happened for this
+	// duration. Afterwards the BatchReceiver gets called once.
+	Debounce time.Duration
+	// MaxWait limits the delay of a batch when changes do not stop, for
+	// example during a large import. Zero means no limit.
+	MaxWait time.Duration
+}
+
+// patternSub a validated Subscription with its pending batch.
+type patternSub struct {
+	Subscription
+	levels []string
+
+	mu      sync.Mutex
+	pending cfgpath.PathSlice
+	first   time.Time
+	timer   *time.Timer
+}
+
+func newPatternSub(sub Subscription) (*patternSub, error) {
+	switch {
+	case sub.Receiver == nil && sub.BatchReceiver == nil:
+		return nil, errors.NewEmptyf("[config] Subscription %q without receiver", sub.Pattern)
+	case sub.Receiver != nil && sub.BatchReceiver != nil:
+		return nil, errors.NewNotValidf("[config] Subscription %q contains a Receiver and a BatchReceiver", sub.Pattern)
+	case sub.Receiver != nil && sub.Debounce > 0:
+		return nil, errors.NewNotValidf("[config] Subscription %q Debounce requires a BatchReceiver", sub.Pattern)
+	case sub.Pattern == "":
+		return nil, errors.NewEmptyf("[config] Subscription Pattern cannot be empty")
+	}
+
+	levels := strings.Split(sub.Pattern, string(cfgpath.Separator))
+	if len(levels) > cfgpath.Levels {
+		return nil, errors.NewNotValidf("[config] Subscription Pattern %q contains more than %d levels", sub.Pattern, cfgpath.Levels)
+	}
+	for _, l := range levels {
+		if l == "" {
+			return nil, errors.NewNotValidf("[config] Subscription Pattern %q contains an empty level", sub.Pattern)
+		}
+		if _, err := path.Match(l, ""); err != nil {
+			return nil, errors.NewNotValidf("[config] Subscription Pattern %q: %s", sub.Pattern, err)
+		}
+	}
+	return &patternSub{
+		Subscription: sub,
+		levels:       levels,
+	}, nil
+}
+
+// match checks the scope and the route of a path against the subscription.
+func (ps *patternSub) match(p cfgpath.Path) bool {
+	if len(ps.Scopes) > 0 {
+		found := false
+		for _, id := range ps.Scopes {
+			if id == p.ScopeID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	route := p.Route.String()
+	for _, pl := range ps.levels {
+		if route == "" {
+			return false // route has less levels than the pattern
+		}
+		var rl string
+		if pos := strings.IndexByte(route, cfgpath.Separator); pos >= 0 {
+			rl, route = route[:pos], route[pos+1:]
+		} else {
+			rl, route = route, ""
+		}
+		if ok, _ := path.Match(pl, rl); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// add appends the path to the pending batch and (re)starts the timer. flush
+// gets called when the timer fires.
+func (ps *patternSub) add(p cfgpath.Path, flush func()) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	now := time.Now()
+	if len(ps.pending) == 0 {
+		ps.first = now
+	}
+	if !ps.pending.Contains(p) {
+		ps.pending = append(ps.pending, p)
+	}
+
+	delay := ps.Debounce
+	if ps.MaxWait > 0 {
+		if left := ps.first.Add(ps.MaxWait).Sub(now); left < delay {
+			delay = left
+		}
+	}
+	if ps.timer != nil {
+		ps.timer.Stop()
+	}
+	ps.timer = time.AfterFunc(delay, flush)
+}
+
+// take returns and resets the pending batch.
+func (ps *patternSub) take() cfgpath.PathSlice {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	if ps.timer != nil {
+		ps.timer.Stop()
+		ps.timer = nil
+	}
+	b := ps.pending
+	ps.pending = nil
+	return b
+}
+
+// batchCall adapts a BatchReceiver to a MessageReceiver to reuse the panic
+// protection of sendMsgRecoverable.
+type batchCall struct {
+	BatchReceiver
+	paths cfgpath.PathSlice
+}
+
+func (bc batchCall) MessageConfig(cfgpath.Path) error {
+	return bc.MessageConfigBatch(bc.paths)
+}
+
+// SubscribePattern adds a Subscription which supports wildcards, filtering by
+// scope and batching of changes. For example to receive all changes of the
+// active flag of all payment methods in store 5 in one call, once the import
+// has been finished:
+//
+//		id, err := srv.SubscribePattern(config.Subscription{
+//			Pattern:       "payment/*/active",
+//			Scopes:        scope.TypeIDs{scope.Store.Pack(5)},
+//			BatchReceiver: myReceiver,
+//			Debounce:      time.Second,
+//		})
+//
+// Unsubscribe removes the Subscription and discards its pending batch.
+func (s *pubSub) SubscribePattern(sub Subscription) (subscriptionID int, err error) {
+	ps, err := newPatternSub(sub)
+	if err != nil {
+		return 0, errors.Wrap(err, "[config] pubSub.SubscribePattern")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subAutoInc++
+	subscriptionID = s.subAutoInc
+	s.patterns[subscriptionID] = ps
+	return subscriptionID, nil
+}
+
+// sendPatterns delivers the path to all matching pattern subscriptions and
+// returns the IDs of the failed subscribers. Debounced subscriptions get
+// called later in their own goroutine.
+func (s *pubSub) sendPatterns(p cfgpath.Path) (evict []int) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for id, ps := range s.patterns {
+		if !ps.match(p) {
+			continue
+		}
+		var err error
+		switch {
+		case ps.Debounce > 0:
+			id := id
+			ps.add(p, func() { s.flushPattern(id, ps) })
+		case ps.Receiver != nil:
+			err = s.sendMsgRecoverable(id, ps.Receiver, p)
+		default:
+			err = s.sendMsgRecoverable(id, batchCall{BatchReceiver: ps.BatchReceiver, paths: cfgpath.PathSlice{p}}, p)
+		}
+		if err != nil {
+			if s.log.IsDebug() {
+				s.log.Debug("config.pubSub.publish.sendPatterns", log.Err(err), log.Int("id", id), log.Stringer("path", p))
+			}
+			evict = append(evict, id)
+		}
+	}
+	return
+}
+
+// flushPattern sends the pending batch of a debounced subscription.
+func (s *pubSub) flushPattern(id int, ps *patternSub) {
+	b := ps.take()
+	if len(b) == 0 {
+		return
+	}
+	if err := s.sendMsgRecoverable(id, batchCall{BatchReceiver: ps.BatchReceiver, paths: b}, b[0]); err != nil {
+		if err := s.Unsubscribe(id); err != nil && s.log.IsDebug() {
+			s.log.Debug("config.pubSub.flushPattern.Unsubscribe.err", log.Err(err), log.Int("subscriptionID", id))
+		}
+	}
+}
+
+// flushPatterns sends all pending batches, used when closing the pub/sub
+// service.
+func (s *pubSub) flushPatterns() {
+	s.mu.RLock()
+	pss := make(map[int]*patternSub, len(s.patterns))
+	for id, ps := range s.patterns {
+		pss[id] = ps
+	}
+	s.mu.RUnlock()
+	for id, ps := range pss {
+		s.flushPattern(id, ps)
+	}
+}
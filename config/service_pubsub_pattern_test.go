@@ -0,0 +1,258 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ config.PatternSubscriber = (*config.Service)(nil)
+var _ config.BatchReceiver = (*testBatchReceiver)(nil)
+
+type testBatchReceiver struct {
+	f func(ps cfgpath.PathSlice) error
+}
+
+func (tb *testBatchReceiver) MessageConfigBatch(ps cfgpath.PathSlice) error {
+	return tb.f(ps)
+}
+
+func TestPubSubPatternMatch(t *testing.T) {
+	s := config.MustNewService(config.NewInMemoryStore(), config.WithPubSub())
+
+	received := make(chan string, 10)
+	rec := &testSubscriber{
+		t: t,
+		f: func(p cfgpath.Path) error {
+			received <- p.String()
+			return nil
+		},
+	}
+	_, err := s.SubscribePattern(config.Subscription{
+		Pattern:  "payment/*/active",
+		Scopes:   scope.TypeIDs{scope.Store.Pack(5)},
+		Receiver: rec,
+	})
+	assert.NoError(t, err)
+	_, err = s.SubscribePattern(config.Subscription{
+		Pattern:  "carriers/[ut]ps",
+		Receiver: rec,
+	})
+	assert.NoError(t, err)
+
+	writes := []cfgpath.Path{
+		cfgpath.MustNewByParts("payment/checkmo/active").BindStore(5),
+		cfgpath.MustNewByParts("payment/checkmo/title").BindStore(5),
+		cfgpath.MustNewByParts("payment/ccsave/active").BindStore(4),
+		cfgpath.MustNewByParts("payment/ccsave/active").BindWebsite(5),
+		cfgpath.MustNewByParts("payment/ccsave/active").BindStore(5),
+		cfgpath.MustNewByParts("carriers/dhl/active"),
+		cfgpath.MustNewByParts("carriers/ups/active").BindWebsite(2),
+		cfgpath.MustNewByParts("carriers/ups/title"),
+	}
+	for _, p := range writes {
+		assert.NoError(t, s.Write(p, 1))
+	}
+	assert.NoError(t, s.Close())
+	close(received)
+
+	var have []string
+	for p := range received {
+		have = append(have, p)
+	}
+	assert.Exactly(t, []string{
+		"stores/5/payment/checkmo/active",
+		"stores/5/payment/ccsave/active",
+		"websites/2/carriers/ups/active",
+		"default/0/carriers/ups/title",
+	}, have)
+}
+
+func TestPubSubPatternErrors(t *testing.T) {
+	s := config.MustNewService(config.NewInMemoryStore(), config.WithPubSub())
+	defer func() { assert.NoError(t, s.Close()) }()
+	rec := &testSubscriber{t: t}
+	brec := &testBatchReceiver{}
+
+	tests := []struct {
+		sub     config.Subscription
+		errBhvf errors.BehaviourFunc
+	}{
+		{config.Subscription{Pattern: "payment/*/active"}, errors.IsEmpty},
+		{config.Subscription{Pattern: "", Receiver: rec}, errors.IsEmpty},
+		{config.Subscription{Pattern: "payment/*/active", Receiver: rec, BatchReceiver: brec}, errors.IsNotValid},
+		{config.Subscription{Pattern: "payment/*/active", Receiver: rec, Debounce: time.Second}, errors.IsNotValid},
+		{config.Subscription{Pattern: "payment//active", Receiver: rec}, errors.IsNotValid},
+		{config.Subscription{Pattern: "payment/[/active", Receiver: rec}, errors.IsNotValid},
+		{config.Subscription{Pattern: "a/b/c/d", Receiver: rec}, errors.IsNotValid},
+	}
+	for i, test := range tests {
+		_, err := s.SubscribePattern(test.sub)
+		assert.True(t, test.errBhvf(err), "Index %d => %+v", i, err)
+	}
+}
+
+func TestPubSubPatternDebounce(t *testing.T) {
+	s := config.MustNewService(config.NewInMemoryStore(), config.WithPubSub())
+
+	batches := make(chan cfgpath.PathSlice, 10)
+	subID, err := s.SubscribePattern(config.Subscription{
+		Pattern: "catalog",
+		BatchReceiver: &testBatchReceiver{
+			f: func(ps cfgpath.PathSlice) error {
+				batches <- ps
+				return nil
+			},
+		},
+		Debounce: 50 * time.Millisecond,
+	})
+	assert.NoError(t, err)
+
+	p1 := cfgpath.MustNewByParts("catalog/search/engine")
+	p2 := cfgpath.MustNewByParts("catalog/search/min_query_length").BindWebsite(1)
+	for _, p := range []cfgpath.Path{p1, p2, p1, p2, p1} {
+		assert.NoError(t, s.Write(p, 1))
+	}
+
+	select {
+	case b := <-batches:
+		assert.Exactly(t, cfgpath.PathSlice{p1, p2}, b)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timeout waiting for the batch")
+	}
+
+	// pending batches getting discarded on Unsubscribe
+	assert.NoError(t, s.Write(p1, 2))
+	assert.NoError(t, s.Unsubscribe(subID))
+	assert.NoError(t, s.Close())
+	assert.Len(t, batches, 0)
+}
+
+func TestPubSubPatternMaxWaitAndClose(t *testing.T) {
+	s := config.MustNewService(config.NewInMemoryStore(), config.WithPubSub())
+
+	batches := make(chan cfgpath.PathSlice, 10)
+	newSub := func(maxWait time.Duration) config.Subscription {
+		return config.Subscription{
+			Pattern: "catalog/search",
+			BatchReceiver: &testBatchReceiver{
+				f: func(ps cfgpath.PathSlice) error {
+					batches <- ps
+					return nil
+				},
+			},
+			Debounce: time.Hour,
+			MaxWait:  maxWait,
+		}
+	}
+	_, err := s.SubscribePattern(newSub(20 * time.Millisecond))
+	assert.NoError(t, err)
+
+	p1 := cfgpath.MustNewByParts("catalog/search/engine")
+	assert.NoError(t, s.Write(p1, 1))
+	select {
+	case b := <-batches:
+		assert.Exactly(t, cfgpath.PathSlice{p1}, b)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timeout waiting for the batch")
+	}
+
+	_, err = s.SubscribePattern(newSub(0))
+	assert.NoError(t, err)
+	assert.NoError(t, s.Write(p1, 2))
+	assert.NoError(t, s.Close())
+
+	// the first subscription might have already sent its batch
+	var n int
+	for len(batches) > 0 {
+		assert.Exactly(t, cfgpath.PathSlice{p1}, <-batches)
+		n++
+	}
+	assert.True(t, n >= 1, "Close must flush the pending batch, got %d", n)
+}
+
+func TestPubSubPatternEvict(t *testing.T) {
+	debugBuf, logger := initLogger()
+	s := config.MustNewService(config.NewInMemoryStore(), config.WithLogger(logger), config.WithPubSub())
+
+	var calls int
+	_, err := s.SubscribePattern(config.Subscription{
+		Pattern: "aa/*/cc",
+		BatchReceiver: &testBatchReceiver{
+			f: func(ps cfgpath.PathSlice) error {
+				calls++
+				return errors.NewFatalf("Batch failed")
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, s.Write(cfgpath.MustNewByParts("aa/bb/cc"), 1))
+	assert.NoError(t, s.Write(cfgpath.MustNewByParts("aa/dd/cc"), 1))
+	assert.NoError(t, s.Close())
+	assert.Exactly(t, 1, calls)
+	assert.Contains(t, debugBuf.String(), `config.pubSub.publish.sendPatterns error: "Batch failed"`)
+}
+
+func TestPubSubBackPressure(t *testing.T) {
+	_, err := config.NewService(config.NewInMemoryStore(), config.WithPubSubBackPressure(0, 0))
+	assert.True(t, errors.IsNotValid(err), "%+v", err)
+
+	tests := []time.Duration{0, 10 * time.Millisecond}
+	for _, maxWait := range tests {
+		s := config.MustNewService(config.NewInMemoryStore(), config.WithPubSubBackPressure(1, maxWait))
+
+		entered := make(chan struct{}, 5)
+		release := make(chan struct{})
+		_, err = s.Subscribe(cfgpath.NewRoute("aa"), &testSubscriber{
+			t: t,
+			f: func(_ cfgpath.Path) error {
+				entered <- struct{}{}
+				<-release
+				return nil
+			},
+		})
+		assert.NoError(t, err)
+
+		p := cfgpath.MustNewByParts("aa/bb/cc")
+		assert.NoError(t, s.Write(p, 1))
+		<-entered                        // first path blocks the subscriber
+		assert.NoError(t, s.Write(p, 2)) // second path waits in the queue
+		err := s.Write(p, 3)             // third path gets dropped
+		assert.True(t, errors.IsTemporary(err), "maxWait %s: %+v", maxWait, err)
+		assert.Exactly(t, uint64(1), s.DroppedMessages(), "maxWait %s", maxWait)
+
+		release <- struct{}{}
+		<-entered
+		release <- struct{}{}
+		assert.NoError(t, s.Close())
+		assert.Exactly(t, "3", mustServiceString(t, s, p), "Values are still written")
+	}
+}
+
+func mustServiceString(t *testing.T, s *config.Service, p cfgpath.Path) string {
+	v, err := s.String(p)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	return v
+}
@@ -0,0 +1,215 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+type snapshotKind uint8
+
+const (
+	kindByte snapshotKind = iota + 1
+	kindString
+	kindBool
+	kindFloat64
+	kindInt
+	kindTime
+	kindDuration
+)
+
+type snapshotKey struct {
+	hash uint32
+	kind snapshotKind
+}
+
+type snapshotVal struct {
+	v   interface{}
+	err error
+}
+
+// Snapshot memoizes the values of a root Getter. A Snapshot is meant to live
+// only as long as a request and reduces the repeated scope traversal,
+// storage access and type conversion when a request asks dozens of times for
+// the same paths. NotFound errors are getting memoized too.
+//
+// A Snapshot created by Service.Snapshot gets invalidated by every
+// Service.Write or Service.Publish call, means each changed path clears the
+// memoized values before the next read. Snapshot also implements the
+// MessageReceiver interface and can be subscribed to routes of any other
+// Subscriber to clear the memoized values. Snapshot is safe for concurrent
+// use.
+type Snapshot struct {
+	root Getter
+	// generation points to the generation counter of the Service. Can be
+	// nil.
+	generation *uint64
+
+	mu      sync.RWMutex
+	lastGen uint64
+	cache   map[snapshotKey]snapshotVal
+}
+
+// NewSnapshot creates a new memoizing Getter for the root Getter. Changes in
+// the root Getter will only be visible after calling Reset or MessageConfig.
+func NewSnapshot(root Getter) *Snapshot {
+	return &Snapshot{
+		root:  root,
+		cache: make(map[snapshotKey]snapshotVal),
+	}
+}
+
+// Snapshot creates a new request scoped configuration reader bound to the
+// website and store ID. All values retrieved via the returned Scoped are
+// getting memoized until the next call to Write or Publish. The underlying
+// *Snapshot is accessible via the field Scoped.Root.
+func (s *Service) Snapshot(websiteID, storeID int64) Scoped {
+	sn := NewSnapshot(s)
+	sn.generation = &s.generation
+	sn.lastGen = atomic.LoadUint64(&s.generation)
+	return NewScoped(sn, websiteID, storeID)
+}
+
+// NewScoped creates a new scope base configuration reader which shares the
+// memoized values of the Snapshot.
+func (sn *Snapshot) NewScoped(websiteID, storeID int64) Scoped {
+	return NewScoped(sn, websiteID, storeID)
+}
+
+// Reset clears all memoized values.
+func (sn *Snapshot) Reset() {
+	sn.mu.Lock()
+	sn.cache = make(map[snapshotKey]snapshotVal)
+	sn.mu.Unlock()
+}
+
+// Len returns the number of memoized values.
+func (sn *Snapshot) Len() int {
+	sn.mu.RLock()
+	defer sn.mu.RUnlock()
+	return len(sn.cache)
+}
+
+// MessageConfig implements the MessageReceiver interface and clears all
+// memoized values. A path can affect a value in any child scope, so the whole
+// Snapshot gets cleared.
+func (sn *Snapshot) MessageConfig(_ cfgpath.Path) error {
+	sn.Reset()
+	return nil
+}
+
+func (sn *Snapshot) get(p cfgpath.Path, k snapshotKind) (interface{}, error) {
+	h, err := p.Hash(-1)
+	if err != nil {
+		return nil, errors.Wrapf(err, "[config] Snapshot.Hash Path %q", p)
+	}
+	key := snapshotKey{hash: h, kind: k}
+
+	sn.mu.RLock()
+	stale := sn.generation != nil && atomic.LoadUint64(sn.generation) != sn.lastGen
+	val, ok := sn.cache[key]
+	sn.mu.RUnlock()
+	if ok && !stale {
+		return val.v, val.err
+	}
+
+	val.v, val.err = sn.fetch(p, k)
+
+	sn.mu.Lock()
+	if sn.generation != nil {
+		if gen := atomic.LoadUint64(sn.generation); gen != sn.lastGen {
+			sn.cache = make(map[snapshotKey]snapshotVal)
+			sn.lastGen = gen
+		}
+	}
+	sn.cache[key] = val
+	sn.mu.Unlock()
+	return val.v, val.err
+}
+
+func (sn *Snapshot) fetch(p cfgpath.Path, k snapshotKind) (interface{}, error) {
+	switch k {
+	case kindByte:
+		return sn.root.Byte(p)
+	case kindString:
+		return sn.root.String(p)
+	case kindBool:
+		return sn.root.Bool(p)
+	case kindFloat64:
+		return sn.root.Float64(p)
+	case kindInt:
+		return sn.root.Int(p)
+	case kindTime:
+		return sn.root.Time(p)
+	case kindDuration:
+		return sn.root.Duration(p)
+	}
+	return nil, errors.NewNotSupportedf("[config] Snapshot unknown kind %d", k)
+}
+
+// Byte returns a memoized byte slice. The returned slice is shared and must
+// not be modified.
+func (sn *Snapshot) Byte(p cfgpath.Path) ([]byte, error) {
+	v, err := sn.get(p, kindByte)
+	b, _ := v.([]byte)
+	return b, err
+}
+
+// String returns a memoized string.
+func (sn *Snapshot) String(p cfgpath.Path) (string, error) {
+	v, err := sn.get(p, kindString)
+	s, _ := v.(string)
+	return s, err
+}
+
+// Bool returns a memoized bool.
+func (sn *Snapshot) Bool(p cfgpath.Path) (bool, error) {
+	v, err := sn.get(p, kindBool)
+	b, _ := v.(bool)
+	return b, err
+}
+
+// Float64 returns a memoized float64.
+func (sn *Snapshot) Float64(p cfgpath.Path) (float64, error) {
+	v, err := sn.get(p, kindFloat64)
+	f, _ := v.(float64)
+	return f, err
+}
+
+// Int returns a memoized int.
+func (sn *Snapshot) Int(p cfgpath.Path) (int, error) {
+	v, err := sn.get(p, kindInt)
+	i, _ := v.(int)
+	return i, err
+}
+
+// Time returns a memoized time.Time.
+func (sn *Snapshot) Time(p cfgpath.Path) (time.Time, error) {
+	v, err := sn.get(p, kindTime)
+	t, _ := v.(time.Time)
+	return t, err
+}
+
+// Duration returns a memoized time.Duration.
+func (sn *Snapshot) Duration(p cfgpath.Path) (time.Duration, error) {
+	v, err := sn.get(p, kindDuration)
+	d, _ := v.(time.Duration)
+	return d, err
+}
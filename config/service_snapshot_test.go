@@ -0,0 +1,189 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_test
+
+import (
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	_ config.Getter          = (*config.Snapshot)(nil)
+	_ config.MessageReceiver = (*config.Snapshot)(nil)
+)
+
+func TestSnapshotMemoizes(t *testing.T) {
+
+	p := cfgpath.MustNewByParts("aa/bb/cc")
+	sm := cfgmock.NewService(cfgmock.PathValue{
+		p.String():                "default",
+		p.BindWebsite(1).String(): 33,
+		cfgpath.MustNewByParts("aa/bb/dd").BindStore(2).String(): time.Hour,
+	})
+	sn := config.NewSnapshot(sm)
+	sg := sn.NewScoped(1, 2)
+
+	for i := 0; i < 3; i++ {
+		s, err := sg.String(p.Route)
+		assert.NoError(t, err, "Index %d", i)
+		assert.Exactly(t, "33", s, "Index %d", i)
+
+		n, err := sg.Int(p.Route)
+		assert.NoError(t, err, "Index %d", i)
+		assert.Exactly(t, 33, n, "Index %d", i)
+
+		d, err := sg.Duration(cfgpath.NewRoute("aa/bb/dd"))
+		assert.NoError(t, err, "Index %d", i)
+		assert.Exactly(t, time.Hour, d, "Index %d", i)
+
+		_, err = sg.Bool(cfgpath.NewRoute("xx/yy/zz"))
+		assert.True(t, errors.IsNotFound(err), "Index %d => %+v", i, err)
+	}
+	// store scope not found and website scope found, each only called once
+	assert.Exactly(t, 2, sm.StringInvokes().Sum())
+	assert.Exactly(t, 2, sm.IntInvokes().Sum())
+	assert.Exactly(t, 1, sm.DurationInvokes().Sum())
+	assert.Exactly(t, 3, sm.BoolInvokes().Sum())
+	assert.Exactly(t, 8, sn.Len())
+
+	// website and default scope share the memoized values of the Snapshot
+	s, err := sn.NewScoped(0, 0).String(p.Route)
+	assert.NoError(t, err)
+	assert.Exactly(t, "default", s)
+	assert.Exactly(t, 3, sm.StringInvokes().Sum())
+
+	sm.UpdateValues(cfgmock.PathValue{p.BindWebsite(1).String(): 44})
+	n, err := sg.Int(p.Route)
+	assert.NoError(t, err)
+	assert.Exactly(t, 33, n, "Memoized value")
+
+	assert.NoError(t, sn.MessageConfig(p.BindWebsite(1)))
+	assert.Exactly(t, 0, sn.Len())
+	n, err = sg.Int(p.Route)
+	assert.NoError(t, err)
+	assert.Exactly(t, 44, n)
+}
+
+func TestServiceSnapshotInvalidation(t *testing.T) {
+
+	st := config.NewInMemoryStore()
+	srv := config.MustNewService(st, config.WithPubSub())
+	defer func() { assert.NoError(t, srv.Close()) }()
+
+	p := cfgpath.MustNewByParts("web/cookie/lifetime")
+	assert.NoError(t, srv.Write(p, 3600))
+
+	sg := srv.Snapshot(1, 2)
+	assert.IsType(t, (*config.Snapshot)(nil), sg.Root)
+
+	have, err := sg.Int(p.Route)
+	assert.NoError(t, err)
+	assert.Exactly(t, 3600, have)
+
+	// a write into the store scope must be visible immediately
+	assert.NoError(t, srv.Write(p.BindStore(2), 60))
+	have, err = sg.Int(p.Route)
+	assert.NoError(t, err)
+	assert.Exactly(t, 60, have)
+
+	// a remote change in the storage stays invisible until a storage engine
+	// publishes it.
+	assert.NoError(t, st.Set(p.BindStore(2), 1))
+	have, err = sg.Int(p.Route)
+	assert.NoError(t, err)
+	assert.Exactly(t, 60, have)
+
+	srv.Publish(p.BindStore(2))
+	have, err = sg.Int(p.Route)
+	assert.NoError(t, err)
+	assert.Exactly(t, 1, have)
+}
+
+func TestSnapshotConcurrent(t *testing.T) {
+
+	srv := config.MustNewService(config.NewInMemoryStore())
+	p := cfgpath.MustNewByParts("aa/bb/cc")
+	assert.NoError(t, srv.Write(p, "a"))
+	sg := srv.Snapshot(1, 1)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if _, err := sg.String(p.Route); err != nil {
+					t.Error(err)
+				}
+				if i == 0 && j%10 == 0 {
+					if err := srv.Write(p.BindWebsite(1), "b"); err != nil {
+						t.Error(err)
+					}
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	have, err := sg.String(p.Route)
+	assert.NoError(t, err)
+	assert.Exactly(t, "b", have)
+}
+
+var benchmarkSnapshotString string
+
+func BenchmarkSnapshotStringStore(b *testing.B) {
+	benchmarkSnapshotStringRun(b, 1, 1)
+}
+
+func BenchmarkSnapshotStringWebsite(b *testing.B) {
+	benchmarkSnapshotStringRun(b, 1, 0)
+}
+
+func BenchmarkSnapshotStringDefault(b *testing.B) {
+	benchmarkSnapshotStringRun(b, 0, 0)
+}
+
+// benchmarkSnapshotStringRun uses the same setup as
+// benchmarkScopedServiceStringRun to allow a comparison.
+func benchmarkSnapshotStringRun(b *testing.B, websiteID, storeID int64) {
+	route := cfgpath.NewRoute("aa/bb/cc")
+	want := strings.Repeat("Gopher", 100)
+	sg := config.NewSnapshot(cfgmock.NewService(cfgmock.PathValue{
+		cfgpath.MustNew(route).String(): want,
+	})).NewScoped(websiteID, storeID)
+
+	runtime.GC()
+	b.ResetTimer()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var err error
+		benchmarkSnapshotString, err = sg.String(route)
+		if err != nil {
+			b.Error(err)
+		}
+		if benchmarkSnapshotString != want {
+			b.Errorf("Want %s Have %s", want, benchmarkSnapshotString)
+		}
+	}
+}
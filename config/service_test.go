@@ -21,6 +21,8 @@ import (
 	"github.com/corestoreio/csfw/config"
 	"github.com/corestoreio/csfw/config/cfgpath"
 	"github.com/corestoreio/csfw/config/element"
+	"github.com/corestoreio/csfw/log"
+	"github.com/corestoreio/csfw/log/logw"
 	"github.com/corestoreio/csfw/util/errors"
 	"github.com/stretchr/testify/assert"
 )
@@ -87,6 +89,42 @@ func TestService_Write(t *testing.T) {
 	assert.True(t, errors.IsNotValid(err), "Error: %s", err)
 }
 
+type failingTransport struct{}
+
+func (failingTransport) Broadcast(cfgpath.Path) error  { return errors.NewFatalf("network down") }
+func (failingTransport) Listen(config.Publisher) error { return nil }
+func (failingTransport) Close() error                  { return nil }
+
+func TestService_WriteBroadcastFails(t *testing.T) {
+	infoBuf := new(log.MutexBuffer)
+	lg := logw.NewLog(logw.WithInfo(infoBuf, "testInfo: ", 0))
+	lg.SetLevel(logw.LevelInfo)
+
+	srv := config.MustNewService(config.NewInMemoryStore(), config.WithLogger(lg), config.WithTransport(failingTransport{}))
+	p := cfgpath.MustNewByParts("aa/bb/cc")
+	assert.NoError(t, srv.Write(p, 4711), "Write must succeed because the value has been stored")
+	v, err := srv.Int(p)
+	assert.NoError(t, err)
+	assert.Exactly(t, 4711, v)
+	assert.Contains(t, infoBuf.String(), "config.Service.Write.Transport.Broadcast")
+	assert.Contains(t, infoBuf.String(), "network down")
+}
+
+type closeFailingTransport struct{ failingTransport }
+
+func (closeFailingTransport) Close() error { return errors.NewFatalf("connection reset") }
+
+func TestService_CloseTransportFails(t *testing.T) {
+	srv := config.MustNewService(config.NewInMemoryStore(), config.WithPubSub(), config.WithTransport(closeFailingTransport{}))
+
+	err := srv.Close()
+	assert.True(t, errors.IsFatal(err), "Error: %s", err)
+
+	// the pub/sub service must have been closed despite the failing Transport
+	err = srv.Close()
+	assert.True(t, errors.MultiErrContainsAll(err, errors.IsFatal, errors.IsAlreadyClosed), "Error: %s", err)
+}
+
 func TestService_Types(t *testing.T) {
 
 	basePath := cfgpath.MustNewByParts("aa/bb/cc")
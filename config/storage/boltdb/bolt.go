@@ -0,0 +1,208 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package boltdb
+
+import (
+	"os"
+
+	"github.com/boltdb/bolt"
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/log"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// BucketName global bucket name for all configuration entries.
+var BucketName = []byte("config")
+
+var errKeyNotFound = errors.NewNotFoundf(`[boltdb] Key not found`)
+
+// Storage connects a bolt database with the config.Service type. Implements
+// interface config.Storager.
+type Storage struct {
+	// DB the underlying bolt database. Only access it if you know exactly
+	// what you are doing.
+	DB      *bolt.DB
+	backend config.Storager
+	log     log.Logger
+}
+
+// Open creates and opens a bolt database at the given path. If the file does
+// not exist then it will be created automatically. If the third argument
+// Options doesn't get applied bolt.DefaultOptions will be used.
+func Open(path string, mode os.FileMode, options ...*bolt.Options) (*Storage, error) {
+	var opt = bolt.DefaultOptions
+	if len(options) == 1 {
+		opt = options[0]
+	}
+	db, err := bolt.Open(path, mode, opt)
+	if err != nil {
+		return nil, errors.NewFatalf("[boltdb] bolt.Open: %s", err)
+	}
+	return New(db)
+}
+
+// New uses an existing DB and creates a new bucket from variable BucketName
+// if that bucket does not exists.
+func New(db *bolt.DB) (*Storage, error) {
+	err := db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(BucketName); err != nil {
+			return errors.NewFatalf("[boltdb] bolt.CreateBucketIfNotExists: %s", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "[boltdb] db.Update")
+	}
+	return &Storage{
+		DB:  db,
+		log: log.BlackHole{}, // skip debug and info level via init with empty fields
+	}, nil
+}
+
+// SetLogger applies your custom logger.
+func (s *Storage) SetLogger(l log.Logger) *Storage {
+	s.log = l
+	return s
+}
+
+// SetBackend turns the Storage into a persistent warm cache in front of the
+// backend b. Set writes first into the backend and then into bolt. Get falls
+// back to the backend on a cache miss and stores the found value in bolt.
+func (s *Storage) SetBackend(b config.Storager) *Storage {
+	s.backend = b
+	return s
+}
+
+// Close closes the bolt database.
+func (s *Storage) Close() error {
+	return errors.Wrap(s.DB.Close(), "[boltdb] DB.Close")
+}
+
+// Set writes a key with its value into the bolt database. If a backend has
+// been set, the value gets written first into the backend.
+func (s *Storage) Set(key cfgpath.Path, value interface{}) error {
+	if s.backend != nil {
+		if err := s.backend.Set(key, value); err != nil {
+			return errors.Wrapf(err, "[boltdb] Set.backend.Set. Key: %q", key)
+		}
+	}
+	return errors.Wrap(s.set(key, value), "[boltdb] Set")
+}
+
+func (s *Storage) set(key cfgpath.Path, value interface{}) error {
+	fq, err := key.FQ()
+	if err != nil {
+		return errors.Wrap(err, "[boltdb] Path.FQ")
+	}
+	v, err := encode(value)
+	if err != nil {
+		return errors.Wrapf(err, "[boltdb] encode. Key: %q Value: %v", fq, value)
+	}
+	return s.DB.Update(func(tx *bolt.Tx) error {
+		if err := tx.Bucket(BucketName).Put(fq.Chars, v); err != nil {
+			return errors.NewFatalf("[boltdb] Bucket.Put: %s", err)
+		}
+		return nil
+	})
+}
+
+// Get returns the value with the same type as it has been written. On a cache
+// miss the backend gets queried, if set. Error behaviour: NotFound.
+func (s *Storage) Get(key cfgpath.Path) (interface{}, error) {
+	fq, err := key.FQ()
+	if err != nil {
+		return nil, errors.Wrap(err, "[boltdb] Get.Path.FQ")
+	}
+
+	var v interface{}
+	var found bool
+	if err := s.DB.View(func(tx *bolt.Tx) error {
+		raw := tx.Bucket(BucketName).Get(fq.Chars)
+		if raw == nil {
+			return nil
+		}
+		found = true
+		var err error
+		v, err = decode(raw) // decode copies the data, raw is only valid within the transaction.
+		return err
+	}); err != nil {
+		return nil, errors.Wrapf(err, "[boltdb] Get.View. Key: %q", fq)
+	}
+	if found {
+		return v, nil
+	}
+	if s.backend == nil {
+		return nil, errKeyNotFound
+	}
+
+	v, err = s.backend.Get(key)
+	if err != nil {
+		return nil, errors.Wrapf(err, "[boltdb] Get.backend.Get. Key: %q", fq)
+	}
+	if err := s.set(key, v); err != nil {
+		return nil, errors.Wrapf(err, "[boltdb] Get.set. Key: %q", fq)
+	}
+	return v, nil
+}
+
+// AllKeys returns all keys stored in the bolt bucket, iterated via a cursor
+// in byte-sorted order.
+func (s *Storage) AllKeys() (cfgpath.PathSlice, error) {
+	var ret cfgpath.PathSlice
+	err := s.DB.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(BucketName).Cursor()
+		for k, _ := c.First(); k != nil; k, _ = c.Next() {
+			p, err := cfgpath.SplitFQ(string(k))
+			if err != nil {
+				return errors.Wrapf(err, "[boltdb] cfgpath.SplitFQ: %q", k)
+			}
+			ret = append(ret, p)
+		}
+		return nil
+	})
+	return ret, errors.Wrap(err, "[boltdb] AllKeys.View")
+}
+
+// Warmup copies all keys and values from the backend into the bolt database.
+// Returns the number of copied entries. Keys which return a NotFound error in
+// the backend getting skipped.
+func (s *Storage) Warmup() (int, error) {
+	if s.backend == nil {
+		return 0, errors.NewEmptyf("[boltdb] Warmup: Backend not set")
+	}
+	keys, err := s.backend.AllKeys()
+	if err != nil {
+		return 0, errors.Wrap(err, "[boltdb] Warmup.backend.AllKeys")
+	}
+	var n int
+	for _, k := range keys {
+		v, err := s.backend.Get(k)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return n, errors.Wrapf(err, "[boltdb] Warmup.backend.Get. Key: %q", k)
+		}
+		if err := s.set(k, v); err != nil {
+			return n, errors.Wrapf(err, "[boltdb] Warmup.set. Key: %q", k)
+		}
+		n++
+	}
+	if s.log.IsDebug() {
+		s.log.Debug("boltdb.Storage.Warmup", log.Int("keys", len(keys)), log.Int("written", n))
+	}
+	return n, nil
+}
@@ -0,0 +1,157 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package boltdb_test
+
+import (
+	"io/ioutil"
+	"math"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/cfgmock"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/config/storage/boltdb"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ config.Storager = (*boltdb.Storage)(nil)
+
+func openTemp(t *testing.T) (*boltdb.Storage, func()) {
+	f, err := ioutil.TempFile("", "cfgboltdb_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := boltdb.Open(f.Name(), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, func() {
+		if err := s.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Remove(f.Name()); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestStorageTypes(t *testing.T) {
+	s, closer := openTemp(t)
+	defer closer()
+
+	now := time.Date(2016, 11, 5, 16, 17, 18, 19, time.UTC)
+	tests := []struct {
+		key  cfgpath.Path
+		val  interface{}
+		want interface{}
+	}{
+		{cfgpath.MustNewByParts("aa/bb/str"), "Gopher", "Gopher"},
+		{cfgpath.MustNewByParts("aa/bb/bytes"), []byte(`Gopher`), []byte(`Gopher`)},
+		{cfgpath.MustNewByParts("aa/bb/bool").BindStore(3), true, true},
+		{cfgpath.MustNewByParts("aa/bb/bool").BindStore(4), false, false},
+		{cfgpath.MustNewByParts("aa/bb/int").BindWebsite(1), -4711, -4711},
+		{cfgpath.MustNewByParts("aa/bb/int64"), int64(math.MaxInt64), int64(math.MaxInt64)},
+		{cfgpath.MustNewByParts("aa/bb/float"), math.Pi, math.Pi},
+		{cfgpath.MustNewByParts("aa/bb/time"), now, now},
+		{cfgpath.MustNewByParts("aa/bb/duration"), time.Second * 33, time.Second * 33},
+		{cfgpath.MustNewByParts("aa/bb/nil"), nil, nil},
+		{cfgpath.MustNewByParts("aa/bb/route"), cfgpath.NewRoute("xx/yy/zz"), "xx/yy/zz"},
+	}
+	for i, test := range tests {
+		assert.NoError(t, s.Set(test.key, test.val), "Index %d", i)
+	}
+	for i, test := range tests {
+		have, err := s.Get(test.key)
+		assert.NoError(t, err, "Index %d", i)
+		assert.Exactly(t, test.want, have, "Index %d", i)
+	}
+
+	keys, err := s.AllKeys()
+	assert.NoError(t, err)
+	assert.Len(t, keys, len(tests))
+	assert.Exactly(t, "default/0/aa/bb/bytes", keys[0].String())
+	assert.Exactly(t, "websites/1/aa/bb/int", keys[len(keys)-1].String())
+}
+
+func TestStorageNotFound(t *testing.T) {
+	s, closer := openTemp(t)
+	defer closer()
+
+	v, err := s.Get(cfgpath.MustNewByParts("aa/bb/cc"))
+	assert.True(t, errors.IsNotFound(err), "Error: %+v", err)
+	assert.Nil(t, v)
+
+	err = s.Set(cfgpath.Path{Route: cfgpath.NewRoute("a/b")}, 1)
+	assert.True(t, errors.IsNotValid(errors.Cause(err)), "Error: %+v", err)
+}
+
+func TestStorageBackend(t *testing.T) {
+	s, closer := openTemp(t)
+	defer closer()
+
+	ccd := cfgmock.NewService(cfgmock.PathValue{
+		"default/0/web/unsecure/base_url": "http://corestore.io",
+		"stores/2/web/unsecure/base_url":  "http://store2.corestore.io",
+	}).Storage
+	s.SetBackend(ccd)
+
+	n, err := s.Warmup()
+	assert.NoError(t, err)
+	assert.Exactly(t, 2, n)
+
+	// write through
+	p := cfgpath.MustNewByParts("web/secure/base_url").BindWebsite(1)
+	assert.NoError(t, s.Set(p, "https://corestore.io"))
+	v, err := ccd.Get(p)
+	assert.NoError(t, err)
+	assert.Exactly(t, "https://corestore.io", v)
+
+	// cache miss: reads from the backend and stores into bolt
+	p2 := cfgpath.MustNewByParts("web/secure/offloader_header")
+	assert.NoError(t, ccd.Set(p2, "SSL_OFFLOADED"))
+
+	// simulate a booting node without MySQL connection
+	s.SetBackend(nil)
+	v, err = s.Get(cfgpath.MustNewByParts("web/unsecure/base_url").BindStore(2))
+	assert.NoError(t, err)
+	assert.Exactly(t, "http://store2.corestore.io", v)
+
+	_, err = s.Get(p2)
+	assert.True(t, errors.IsNotFound(err), "Error: %+v", err)
+
+	s.SetBackend(ccd)
+	v, err = s.Get(p2)
+	assert.NoError(t, err)
+	assert.Exactly(t, "SSL_OFFLOADED", v)
+	s.SetBackend(nil)
+	v, err = s.Get(p2)
+	assert.NoError(t, err)
+	assert.Exactly(t, "SSL_OFFLOADED", v)
+
+	keys, err := s.AllKeys()
+	assert.NoError(t, err)
+	assert.Len(t, keys, 4)
+}
+
+func TestStorageWarmupWithoutBackend(t *testing.T) {
+	s, closer := openTemp(t)
+	defer closer()
+	n, err := s.Warmup()
+	assert.True(t, errors.IsEmpty(err), "Error: %+v", err)
+	assert.Exactly(t, 0, n)
+}
@@ -0,0 +1,127 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package boltdb
+
+import (
+	"encoding/binary"
+	"math"
+	"time"
+
+	"github.com/corestoreio/csfw/util/conv"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// Type prefixes. The first byte of each stored value defines the Go type.
+const (
+	typeNil byte = iota + 'a'
+	typeString
+	typeBytes
+	typeBool
+	typeInt
+	typeInt64
+	typeFloat64
+	typeTime
+	typeDuration
+)
+
+// encode converts a value into a byte slice with a leading type prefix.
+// Unsupported types getting converted to a string.
+func encode(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case nil:
+		return []byte{typeNil}, nil
+	case string:
+		return append([]byte{typeString}, v...), nil
+	case []byte:
+		return append([]byte{typeBytes}, v...), nil
+	case bool:
+		if v {
+			return []byte{typeBool, 1}, nil
+		}
+		return []byte{typeBool, 0}, nil
+	case int:
+		return encodeUint64(typeInt, uint64(v)), nil
+	case int64:
+		return encodeUint64(typeInt64, uint64(v)), nil
+	case float64:
+		return encodeUint64(typeFloat64, math.Float64bits(v)), nil
+	case time.Duration:
+		return encodeUint64(typeDuration, uint64(v)), nil
+	case time.Time:
+		b, err := v.MarshalBinary()
+		if err != nil {
+			return nil, errors.NewNotValid(err, "[boltdb] Time.MarshalBinary")
+		}
+		return append([]byte{typeTime}, b...), nil
+	}
+	s, err := conv.ToStringE(value)
+	if err != nil {
+		return nil, errors.NewNotSupported(err, "[boltdb] conv.ToStringE")
+	}
+	return append([]byte{typeString}, s...), nil
+}
+
+func encodeUint64(typ byte, v uint64) []byte {
+	var buf [9]byte
+	buf[0] = typ
+	binary.BigEndian.PutUint64(buf[1:], v)
+	return buf[:]
+}
+
+// decode converts the stored byte slice back into its Go type. The returned
+// value does not share memory with raw.
+func decode(raw []byte) (interface{}, error) {
+	if len(raw) == 0 {
+		return nil, errors.NewNotValidf("[boltdb] Empty value")
+	}
+	typ, data := raw[0], raw[1:]
+	switch typ {
+	case typeNil:
+		return nil, nil
+	case typeString:
+		return string(data), nil
+	case typeBytes:
+		b := make([]byte, len(data))
+		copy(b, data)
+		return b, nil
+	case typeBool:
+		if len(data) != 1 {
+			return nil, errors.NewNotValidf("[boltdb] Invalid bool value: %v", data)
+		}
+		return data[0] == 1, nil
+	case typeTime:
+		var t time.Time
+		if err := t.UnmarshalBinary(data); err != nil {
+			return nil, errors.NewNotValid(err, "[boltdb] Time.UnmarshalBinary")
+		}
+		return t, nil
+	}
+
+	if len(data) != 8 {
+		return nil, errors.NewNotValidf("[boltdb] Invalid length %d for type %q", len(data), typ)
+	}
+	u := binary.BigEndian.Uint64(data)
+	switch typ {
+	case typeInt:
+		return int(u), nil
+	case typeInt64:
+		return int64(u), nil
+	case typeFloat64:
+		return math.Float64frombits(u), nil
+	case typeDuration:
+		return time.Duration(u), nil
+	}
+	return nil, errors.NewNotSupportedf("[boltdb] Unknown type prefix %q", typ)
+}
@@ -15,6 +15,15 @@
 // Package boltdb uses the bolt database for reading and writing
 // configuration paths.
 //
-// Maybe implements synchronization with MySQL core_config_data table.
-// Converts all values to byte slices.
+// The fully qualified path (cfgpath.Path.FQ) acts as the key within the
+// bucket BucketName. Values getting stored with a type prefix so that
+// strings, byte slices, bools, integers, floats, time.Time and time.Duration
+// are returned in the same type as they have been set. All other types
+// getting converted to strings.
+//
+// A Storage can act as a persistent warm cache in front of another
+// config.Storager, mostly the ccd.DBStorage. Set a backend via SetBackend:
+// Set writes through to the backend, Get reads from bolt and falls back to
+// the backend on a cache miss. Warmup copies all values from the backend
+// into bolt, so a node can boot next time even if MySQL is unavailable.
 package boltdb
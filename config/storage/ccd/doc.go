@@ -16,5 +16,6 @@
 // for reading and writing configuration paths, scopes and values.
 //
 // It also provides an option function to load data from core_config_data into
-// a storage service.
+// a storage service and a Sync type to mirror core_config_data bi-directional
+// into any other config.Storager.
 package ccd
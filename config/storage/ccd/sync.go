@@ -0,0 +1,376 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccd
+
+import (
+	"sync"
+	"time"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/log"
+	"github.com/corestoreio/csfw/storage/dbr"
+	"github.com/corestoreio/csfw/store/scope"
+	"github.com/corestoreio/csfw/util/conv"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// ConflictPolicy defines how a Sync resolves a path which has been changed in
+// table core_config_data and in the secondary storage since the last
+// synchronization.
+type ConflictPolicy uint8
+
+const (
+	// PolicyDBWins applies the value from core_config_data and discards the
+	// local change. Default policy.
+	PolicyDBWins ConflictPolicy = iota
+	// PolicyNewestWins compares the updated_at column with the time of the
+	// local change and applies the newer value.
+	PolicyNewestWins
+)
+
+// ColumnUpdatedAt name of the column in core_config_data which contains the
+// time of the last modification. Available since Magento 2.2. Magento 1 and
+// Magento 2 before 2.2 do not have this column.
+const ColumnUpdatedAt = "updated_at"
+
+// syncRow represents a row in table core_config_data including the
+// modification time.
+type syncRow struct {
+	ConfigID  int64          `db:"config_id"`
+	Scope     string         `db:"scope"`
+	ScopeID   int64          `db:"scope_id"`
+	Path      string         `db:"path"`
+	Value     dbr.NullString `db:"value"`
+	UpdatedAt dbr.NullTime   `db:"updated_at"`
+}
+
+// localChange a value written via Sync.Set which has not yet been
+// transferred to core_config_data.
+type localChange struct {
+	path    cfgpath.Path
+	value   string
+	changed time.Time
+}
+
+// Sync mirrors the table core_config_data into a secondary config.Storager
+// and transfers the changes of the secondary storage back into the table.
+// Sync implements the config.Storager interface and must be used as the
+// backend of the config.Service to track local changes. Get and AllKeys are
+// served by the secondary storage.
+//
+// Load performs the initial bulk load. Synchronize runs one diff cycle: rows
+// from core_config_data with a higher config_id or a newer updated_at than
+// the last cycle are compared with the local changes and the ConflictPolicy
+// decides which value wins. Start runs Synchronize periodically. Each
+// change applied from core_config_data gets published to the Publisher.
+// Local changes are not published again because the config.Service has
+// already published them while writing.
+//
+// Deleted rows in core_config_data are not detected.
+type Sync struct {
+	// Policy decides which value wins in case of a conflict. Default
+	// PolicyDBWins.
+	Policy ConflictPolicy
+	// Publisher gets notified about every change applied from
+	// core_config_data. Mostly the config.Service. Optional.
+	Publisher config.Publisher
+	// UpdatedAtColumn defines the column name containing the modification
+	// time. If empty each cycle loads all rows and compares their values,
+	// which works with all Magento versions. Set it to ColumnUpdatedAt to
+	// load only the changed rows from Magento 2.2 and later. Default empty.
+	UpdatedAtColumn string
+	// Clock returns the current time. Default time.Now. Mainly used for
+	// testing.
+	Clock func() time.Time
+	// Log for debugging purposes. Errors in the background goroutine are
+	// getting logged as Info. Default log.BlackHole.
+	Log log.Logger
+
+	db        dbr.SessionRunner
+	primary   config.Storager
+	secondary config.Storager
+
+	mu          sync.Mutex
+	stop        chan struct{}
+	done        chan struct{}
+	lastID      int64
+	lastUpdated time.Time
+	synced      map[string]string      // fully qualified path => last synchronized value
+	pending     map[string]localChange // fully qualified path => change via Set
+}
+
+// NewSync creates a new synchronization between the table core_config_data
+// and the secondary storage. The argument db loads the rows and primary
+// writes back the local changes, mostly a *DBStorage.
+func NewSync(db dbr.SessionRunner, primary, secondary config.Storager) *Sync {
+	return &Sync{
+		Clock:     time.Now,
+		Log:       log.BlackHole{}, // disabled debug and info logging
+		db:        db,
+		primary:   primary,
+		secondary: secondary,
+		synced:    make(map[string]string),
+		pending:   make(map[string]localChange),
+	}
+}
+
+// Set writes the value into the secondary storage and remembers the change
+// for the next synchronization cycle.
+func (s *Sync) Set(key cfgpath.Path, value interface{}) error {
+	fq, err := key.FQ()
+	if err != nil {
+		return errors.Wrap(err, "[ccd] Sync.Set.FQ")
+	}
+	v, err := conv.ToStringE(value)
+	if err != nil {
+		return errors.Wrapf(err, "[ccd] Sync.Set.conv.ToStringE. Key: %q", fq)
+	}
+	if err := s.secondary.Set(key, value); err != nil {
+		return errors.Wrapf(err, "[ccd] Sync.Set.secondary.Set. Key: %q", fq)
+	}
+	s.mu.Lock()
+	s.pending[fq.String()] = localChange{path: key, value: v, changed: s.Clock()}
+	s.mu.Unlock()
+	return nil
+}
+
+// Get returns the value from the secondary storage.
+func (s *Sync) Get(key cfgpath.Path) (interface{}, error) {
+	return s.secondary.Get(key)
+}
+
+// AllKeys returns all keys from the secondary storage.
+func (s *Sync) AllKeys() (cfgpath.PathSlice, error) {
+	return s.secondary.AllKeys()
+}
+
+func (s *Sync) selectRows(all bool) ([]*syncRow, error) {
+	cols := []string{"config_id", "scope", "scope_id", "path", "value"}
+	if s.UpdatedAtColumn != "" {
+		cols = append(cols, dbr.Quoter.QuoteAs(s.UpdatedAtColumn, "updated_at"))
+	}
+	sb := s.db.Select(cols...).From(TableCollection.Name(TableIndexCoreConfigData))
+	if !all && s.UpdatedAtColumn != "" {
+		sb.Where(dbr.ConditionRaw(
+			"`config_id` > ? OR "+dbr.Quoter.QuoteAs(s.UpdatedAtColumn)+" >= ?",
+			s.lastID, s.lastUpdated,
+		))
+	}
+	sb.OrderBy("config_id")
+
+	var rows []*syncRow
+	if _, err := sb.LoadStructs(&rows); err != nil {
+		return nil, errors.Wrap(err, "[ccd] Sync.LoadStructs")
+	}
+	return rows, nil
+}
+
+func (s *Sync) publish(p cfgpath.Path) {
+	if s.Publisher != nil {
+		s.Publisher.Publish(p)
+	}
+}
+
+func (r *syncRow) toPath() (cfgpath.Path, error) {
+	p, err := cfgpath.NewByParts(r.Path)
+	if err != nil {
+		return cfgpath.Path{}, errors.Wrapf(err, "[ccd] cfgpath.NewByParts Path %q", r.Path)
+	}
+	return p.Bind(scope.FromString(r.Scope).Pack(r.ScopeID)), nil
+}
+
+func (s *Sync) trackRow(r *syncRow) {
+	if r.ConfigID > s.lastID {
+		s.lastID = r.ConfigID
+	}
+	if r.UpdatedAt.Valid && r.UpdatedAt.Time.After(s.lastUpdated) {
+		s.lastUpdated = r.UpdatedAt.Time
+	}
+}
+
+// Load performs the initial bulk load of all rows from core_config_data into
+// the secondary storage. Rows with a NULL value getting skipped.
+func (s *Sync) Load() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rows, err := s.selectRows(true)
+	if err != nil {
+		return errors.Wrap(err, "[ccd] Sync.Load")
+	}
+	for _, r := range rows {
+		s.trackRow(r)
+		if !r.Value.Valid {
+			continue
+		}
+		if _, err := s.applyRow(r); err != nil {
+			return errors.Wrap(err, "[ccd] Sync.Load")
+		}
+	}
+	if s.Log.IsDebug() {
+		s.Log.Debug("ccd.Sync.Load", log.Int("rows", len(rows)), log.Int64("lastID", s.lastID), log.Time("lastUpdated", s.lastUpdated))
+	}
+	return nil
+}
+
+// applyRow writes the row into the secondary storage if the value differs
+// from the last synchronized value.
+func (s *Sync) applyRow(r *syncRow) (bool, error) {
+	p, err := r.toPath()
+	if err != nil {
+		return false, errors.Wrap(err, "[ccd] Sync.applyRow")
+	}
+	fq := p.String()
+	if v, ok := s.synced[fq]; ok && v == r.Value.String {
+		return false, nil
+	}
+	if err := s.secondary.Set(p, r.Value.String); err != nil {
+		return false, errors.Wrapf(err, "[ccd] Sync.secondary.Set. Key: %q", fq)
+	}
+	s.synced[fq] = r.Value.String
+	s.publish(p)
+	return true, nil
+}
+
+func (s *Sync) applyLocal(fq string, lc localChange) (bool, error) {
+	if v, ok := s.synced[fq]; ok && v == lc.value {
+		return false, nil
+	}
+	if err := s.primary.Set(lc.path, lc.value); err != nil {
+		return false, errors.Wrapf(err, "[ccd] Sync.primary.Set. Key: %q", fq)
+	}
+	s.synced[fq] = lc.value
+	return true, nil
+}
+
+// Synchronize runs one diff cycle between core_config_data and the secondary
+// storage. Returns the number of applied changes in both directions.
+func (s *Sync) Synchronize() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rows, err := s.selectRows(false)
+	if err != nil {
+		return 0, errors.Wrap(err, "[ccd] Sync.Synchronize")
+	}
+
+	var applied int
+	for _, r := range rows {
+		s.trackRow(r)
+		if !r.Value.Valid {
+			continue
+		}
+		p, err := r.toPath()
+		if err != nil {
+			return applied, errors.Wrap(err, "[ccd] Sync.Synchronize")
+		}
+		fq := p.String()
+
+		if lc, ok := s.pending[fq]; ok {
+			delete(s.pending, fq)
+			if lc.value != r.Value.String && s.localWins(r, lc) {
+				ok, err := s.applyLocal(fq, lc)
+				if err != nil {
+					return applied, errors.Wrap(err, "[ccd] Sync.Synchronize")
+				}
+				if ok {
+					applied++
+				}
+				continue
+			}
+			if s.Log.IsDebug() && lc.value != r.Value.String {
+				s.Log.Debug("ccd.Sync.Synchronize.Conflict.DBWins", log.String("path", fq), log.String("local", lc.value), log.String("db", r.Value.String))
+			}
+			s.synced[fq] = lc.value // force applyRow to write the DB value
+		}
+
+		ok, err := s.applyRow(r)
+		if err != nil {
+			return applied, errors.Wrap(err, "[ccd] Sync.Synchronize")
+		}
+		if ok {
+			applied++
+		}
+	}
+
+	for fq, lc := range s.pending {
+		delete(s.pending, fq)
+		ok, err := s.applyLocal(fq, lc)
+		if err != nil {
+			return applied, errors.Wrap(err, "[ccd] Sync.Synchronize")
+		}
+		if ok {
+			applied++
+		}
+	}
+
+	if s.Log.IsDebug() {
+		s.Log.Debug("ccd.Sync.Synchronize", log.Int("rows", len(rows)), log.Int("applied", applied), log.Int64("lastID", s.lastID), log.Time("lastUpdated", s.lastUpdated))
+	}
+	return applied, nil
+}
+
+func (s *Sync) localWins(r *syncRow, lc localChange) bool {
+	if s.Policy != PolicyNewestWins {
+		return false
+	}
+	return !r.UpdatedAt.Valid || lc.changed.After(r.UpdatedAt.Time)
+}
+
+// Start runs Synchronize periodically in a goroutine. Errors getting logged
+// as Info. Calling Start twice has no effect. Start and Stop are safe for
+// concurrent use.
+func (s *Sync) Start(interval time.Duration) *Sync {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop != nil {
+		return s
+	}
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.run(interval, s.stop, s.done)
+	return s
+}
+
+func (s *Sync) run(interval time.Duration, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := s.Synchronize(); err != nil {
+				s.Log.Info("ccd.Sync.run.Synchronize.error", log.Err(err))
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Stop terminates the goroutine started by Start and waits until a running
+// Synchronize has been finished.
+func (s *Sync) Stop() {
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	s.stop, s.done = nil, nil
+	s.mu.Unlock()
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
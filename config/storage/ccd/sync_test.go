@@ -0,0 +1,202 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccd_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/config/storage/ccd"
+	"github.com/corestoreio/csfw/util/cstesting"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ config.Storager = (*ccd.Sync)(nil)
+
+var syncColumns = []string{"config_id", "scope", "scope_id", "path", "value", "updated_at"}
+
+type recPublisher struct {
+	mu    sync.Mutex
+	paths []string
+}
+
+func (rp *recPublisher) Publish(p cfgpath.Path) {
+	rp.mu.Lock()
+	rp.paths = append(rp.paths, p.String())
+	rp.mu.Unlock()
+}
+
+func (rp *recPublisher) reset() []string {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	p := rp.paths
+	rp.paths = nil
+	return p
+}
+
+func mustGet(t *testing.T, s config.Storager, p cfgpath.Path) interface{} {
+	v, err := s.Get(p)
+	if err != nil {
+		t.Fatalf("%s: %+v", p, err)
+	}
+	return v
+}
+
+func TestSync(t *testing.T) {
+	tests := []struct {
+		policy       ccd.ConflictPolicy
+		wantConflict string
+	}{
+		{ccd.PolicyDBWins, "db"},
+		{ccd.PolicyNewestWins, "local"},
+	}
+	for _, test := range tests {
+		t.Run(test.wantConflict, func(t *testing.T) {
+			testSync(t, test.policy, test.wantConflict)
+		})
+	}
+}
+
+func testSync(t *testing.T, policy ccd.ConflictPolicy, wantConflict string) {
+	dbc, dbMock := cstesting.MockDB(t)
+	defer func() {
+		dbMock.ExpectClose()
+		assert.NoError(t, dbc.Close())
+		if err := dbMock.ExpectationsWereMet(); err != nil {
+			t.Error("there were unfulfilled expections", err)
+		}
+	}()
+
+	t0 := time.Date(2016, 11, 5, 10, 0, 0, 0, time.UTC)
+	now := t0
+	primary := config.NewInMemoryStore()
+	secondary := config.NewInMemoryStore()
+	pub := new(recPublisher)
+
+	sy := ccd.NewSync(dbc.NewSession(nil), primary, secondary)
+	sy.Policy = policy
+	sy.UpdatedAtColumn = ccd.ColumnUpdatedAt
+	sy.Publisher = pub
+	sy.Clock = func() time.Time { return now }
+
+	dbMock.ExpectQuery("SELECT (.+) FROM `core_config_data` ORDER BY config_id").WillReturnRows(
+		sqlmock.NewRows(syncColumns).
+			AddRow(1, "default", 0, "web/unsecure/base_url", "http://corestore.io", t0).
+			AddRow(2, "stores", 2, "web/unsecure/base_url", "http://store2.corestore.io", t0).
+			AddRow(3, "default", 0, "general/locale/code", nil, t0),
+	)
+	assert.NoError(t, sy.Load())
+	assert.Exactly(t, []string{"default/0/web/unsecure/base_url", "stores/2/web/unsecure/base_url"}, pub.reset())
+	assert.Exactly(t, "http://store2.corestore.io", mustGet(t, secondary, cfgpath.MustNewByParts("web/unsecure/base_url").BindStore(2)))
+
+	// local changes
+	now = t0.Add(time.Minute)
+	pLocal := cfgpath.MustNewByParts("web/secure/base_url").BindWebsite(1)
+	pConflict := cfgpath.MustNewByParts("web/unsecure/base_url")
+	assert.NoError(t, sy.Set(pLocal, "https://corestore.io"))
+	assert.NoError(t, sy.Set(pConflict, "http://local.corestore.io"))
+
+	// remote changes: one new row, one conflicting update and one unchanged row
+	dbMock.ExpectQuery("SELECT (.+) FROM `core_config_data` WHERE \\(`config_id` > 3 OR `updated_at` >= '2016-11-05 10:00:00'\\) ORDER BY config_id").WillReturnRows(
+		sqlmock.NewRows(syncColumns).
+			AddRow(1, "default", 0, "web/unsecure/base_url", "http://remote.corestore.io", t0.Add(30*time.Second)).
+			AddRow(2, "stores", 2, "web/unsecure/base_url", "http://store2.corestore.io", t0).
+			AddRow(4, "websites", 1, "general/locale/code", "de_CH", t0.Add(30*time.Second)),
+	)
+	n, err := sy.Synchronize()
+	assert.NoError(t, err)
+	assert.Exactly(t, 3, n)
+
+	assert.Exactly(t, "de_CH", mustGet(t, secondary, cfgpath.MustNewByParts("general/locale/code").BindWebsite(1)))
+	assert.Exactly(t, "https://corestore.io", mustGet(t, primary, pLocal))
+
+	// local changes have already been published by the config.Service.
+	switch wantConflict {
+	case "db":
+		assert.Exactly(t, "http://remote.corestore.io", mustGet(t, secondary, pConflict))
+		_, err := primary.Get(pConflict)
+		assert.Error(t, err, "Local value must not be written to the DB")
+		assert.Exactly(t, []string{"default/0/web/unsecure/base_url", "websites/1/general/locale/code"}, pub.reset())
+	case "local":
+		assert.Exactly(t, "http://local.corestore.io", mustGet(t, secondary, pConflict))
+		assert.Exactly(t, "http://local.corestore.io", mustGet(t, primary, pConflict))
+		assert.Exactly(t, []string{"websites/1/general/locale/code"}, pub.reset())
+	}
+
+	// next cycle receives our own writes back from the DB which must not
+	// trigger any changes.
+	dbMock.ExpectQuery("SELECT (.+) FROM `core_config_data` WHERE \\(`config_id` > 4 OR `updated_at` >= '2016-11-05 10:00:30'\\) ORDER BY config_id").WillReturnRows(
+		sqlmock.NewRows(syncColumns).
+			AddRow(5, "websites", 1, "web/secure/base_url", "https://corestore.io", t0.Add(time.Minute)),
+	)
+	n, err = sy.Synchronize()
+	assert.NoError(t, err)
+	assert.Exactly(t, 0, n)
+	assert.Empty(t, pub.reset())
+}
+
+func TestSyncWithoutUpdatedAt(t *testing.T) {
+	dbc, dbMock := cstesting.MockDB(t)
+	defer func() {
+		dbMock.ExpectClose()
+		assert.NoError(t, dbc.Close())
+		if err := dbMock.ExpectationsWereMet(); err != nil {
+			t.Error("there were unfulfilled expections", err)
+		}
+	}()
+
+	secondary := config.NewInMemoryStore()
+	sy := ccd.NewSync(dbc.NewSession(nil), config.NewInMemoryStore(), secondary)
+
+	cols := syncColumns[:5]
+	dbMock.ExpectQuery("SELECT config_id, scope, scope_id, path, value FROM `core_config_data` ORDER BY config_id").WillReturnRows(
+		sqlmock.NewRows(cols).AddRow(1, "default", 0, "web/unsecure/base_url", "http://corestore.io"),
+	)
+	assert.NoError(t, sy.Load())
+
+	dbMock.ExpectQuery("SELECT config_id, scope, scope_id, path, value FROM `core_config_data` ORDER BY config_id").WillReturnRows(
+		sqlmock.NewRows(cols).AddRow(1, "default", 0, "web/unsecure/base_url", "http://m1.corestore.io"),
+	)
+	n, err := sy.Synchronize()
+	assert.NoError(t, err)
+	assert.Exactly(t, 1, n)
+	assert.Exactly(t, "http://m1.corestore.io", mustGet(t, secondary, cfgpath.MustNewByParts("web/unsecure/base_url")))
+}
+
+func TestSyncStartStop(t *testing.T) {
+	dbc, _ := cstesting.MockDB(t)
+	defer dbc.Close()
+
+	sy := ccd.NewSync(dbc.NewSession(nil), config.NewInMemoryStore(), config.NewInMemoryStore())
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			sy.Start(time.Millisecond)
+		}()
+		go func() {
+			defer wg.Done()
+			sy.Stop()
+		}()
+	}
+	wg.Wait()
+	sy.Stop()
+	sy.Stop()
+}
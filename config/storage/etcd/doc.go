@@ -14,7 +14,19 @@
 
 // Package etcd uses etcd service for reading and writing configuration paths.
 //
-// todo(CS): https://github.com/coreos/etcd/tree/master/client
+// The Storage type implements config.Storager and stores each fully qualified
+// path (e.g. stores/2/web/unsecure/base_url) below a key prefix in the etcd
+// key space. All values are getting converted to strings.
+//
+// The package does not depend on a specific etcd client version. You must
+// provide an implementation of the Client interface, which is a thin adapter
+// around e.g. clientv3.KV and clientv3.Watcher from
+// https://github.com/coreos/etcd/tree/master/clientv3
+//
+// Running several app nodes requires that each node listens to remote edits.
+// Start Storage.Watch in a goroutine and pass the config.Service as
+// config.Publisher to it. Each changed key triggers the subscribed
+// config.MessageReceivers.
 //
 // Maybe implements synchronization with MySQL core_config_data table.
 package etcd
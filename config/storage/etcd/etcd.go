@@ -0,0 +1,190 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcd
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/log"
+	"github.com/corestoreio/csfw/util/conv"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// DefaultPrefix gets prepended to all fully qualified paths when stored in
+// etcd.
+const DefaultPrefix = "csfw/config/"
+
+// DefaultTimeout applies to each request to the etcd cluster.
+const DefaultTimeout = 5 * time.Second
+
+// Client defines the subset of the etcd API which the Storage requires. An
+// adapter around the official etcd client must implement this interface.
+type Client interface {
+	// Put sets the value for a key.
+	Put(ctx context.Context, key, value string) error
+	// Get retrieves the value for a key. If the key cannot be found, found
+	// must be false and err nil.
+	Get(ctx context.Context, key string) (value string, found bool, err error)
+	// Keys returns all keys starting with prefix.
+	Keys(ctx context.Context, prefix string) ([]string, error)
+	// Watch watches for changes on all keys starting with prefix. The
+	// returned channel must be closed once ctx has been cancelled.
+	Watch(ctx context.Context, prefix string) <-chan Event
+}
+
+// Event gets emitted by the Client.Watch channel whenever a key changes.
+type Event struct {
+	// Key the full key including the prefix.
+	Key string
+	// Deleted set to true if the key has been removed.
+	Deleted bool
+	// Err reports a failure of the watcher. A non-nil error terminates
+	// Storage.Watch.
+	Err error
+}
+
+var errKeyNotFound = errors.NewNotFoundf(`[etcd] Key not found`)
+
+// Storage connects an etcd cluster with the config.Service type. Implements
+// interface config.Storager.
+type Storage struct {
+	// Prefix gets prepended to each fully qualified path. Must end with a
+	// slash. Default: DefaultPrefix
+	Prefix string
+	// Timeout for each request to etcd. Default: DefaultTimeout
+	Timeout time.Duration
+	// Log for debugging purposes. Default log.BlackHole.
+	Log    log.Logger
+	client Client
+}
+
+// New creates a new etcd backed configuration storage.
+func New(c Client) *Storage {
+	return &Storage{
+		Prefix:  DefaultPrefix,
+		Timeout: DefaultTimeout,
+		Log:     log.BlackHole{}, // disabled debug and info logging
+		client:  c,
+	}
+}
+
+func (s *Storage) key(p cfgpath.Path) (string, error) {
+	fq, err := p.FQ()
+	if err != nil {
+		return "", errors.Wrap(err, "[etcd] Path.FQ")
+	}
+	return s.Prefix + fq.String(), nil
+}
+
+func (s *Storage) ctx() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), s.Timeout)
+}
+
+// Set writes a key with its value into etcd. The value gets converted to a
+// string.
+func (s *Storage) Set(key cfgpath.Path, value interface{}) error {
+	k, err := s.key(key)
+	if err != nil {
+		return errors.Wrap(err, "[etcd] Set.key")
+	}
+	v, err := conv.ToStringE(value)
+	if err != nil {
+		return errors.Wrapf(err, "[etcd] Set.conv.ToStringE. Key: %q Value: %v", k, value)
+	}
+	ctx, cancel := s.ctx()
+	defer cancel()
+	if err := s.client.Put(ctx, k, v); err != nil {
+		return errors.Wrapf(err, "[etcd] Set.Client.Put. Key: %q", k)
+	}
+	return nil
+}
+
+// Get returns a value from etcd by its key. It is guaranteed that the type in
+// the empty interface is a string. Error behaviour: NotFound.
+func (s *Storage) Get(key cfgpath.Path) (interface{}, error) {
+	k, err := s.key(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "[etcd] Get.key")
+	}
+	ctx, cancel := s.ctx()
+	defer cancel()
+	v, ok, err := s.client.Get(ctx, k)
+	if err != nil {
+		return nil, errors.Wrapf(err, "[etcd] Get.Client.Get. Key: %q", k)
+	}
+	if !ok {
+		return nil, errKeyNotFound
+	}
+	return v, nil
+}
+
+// AllKeys returns all fully qualified paths stored below the Prefix. Keys
+// which cannot be parsed into a cfgpath.Path getting skipped and logged in
+// debug mode.
+func (s *Storage) AllKeys() (cfgpath.PathSlice, error) {
+	ctx, cancel := s.ctx()
+	defer cancel()
+	keys, err := s.client.Keys(ctx, s.Prefix)
+	if err != nil {
+		return nil, errors.Wrapf(err, "[etcd] AllKeys.Client.Keys. Prefix: %q", s.Prefix)
+	}
+	ret := make(cfgpath.PathSlice, 0, len(keys))
+	for _, k := range keys {
+		p, err := s.splitKey(k)
+		if err != nil {
+			if s.Log.IsDebug() {
+				s.Log.Debug("etcd.Storage.AllKeys.splitKey", log.Err(err), log.String("key", k))
+			}
+			continue
+		}
+		ret = append(ret, p)
+	}
+	return ret, nil
+}
+
+func (s *Storage) splitKey(k string) (cfgpath.Path, error) {
+	if !strings.HasPrefix(k, s.Prefix) {
+		return cfgpath.Path{}, errors.NewNotValidf("[etcd] Key %q does not start with prefix %q", k, s.Prefix)
+	}
+	return cfgpath.SplitFQ(k[len(s.Prefix):])
+}
+
+// Watch listens for changes below the Prefix and publishes each changed path
+// to pub, which is mostly the config.Service. Watch blocks until ctx gets
+// cancelled, the watch channel gets closed or the Client reports an error.
+// Keys which cannot be parsed into a cfgpath.Path getting skipped.
+func (s *Storage) Watch(ctx context.Context, pub config.Publisher) error {
+	for ev := range s.client.Watch(ctx, s.Prefix) {
+		if ev.Err != nil {
+			return errors.Wrapf(ev.Err, "[etcd] Watch. Prefix: %q", s.Prefix)
+		}
+		p, err := s.splitKey(ev.Key)
+		if err != nil {
+			if s.Log.IsDebug() {
+				s.Log.Debug("etcd.Storage.Watch.splitKey", log.Err(err), log.String("key", ev.Key))
+			}
+			continue
+		}
+		if s.Log.IsDebug() {
+			s.Log.Debug("etcd.Storage.Watch.Publish", log.Stringer("path", p), log.Bool("deleted", ev.Deleted))
+		}
+		pub.Publish(p)
+	}
+	return errors.Wrap(ctx.Err(), "[etcd] Watch")
+}
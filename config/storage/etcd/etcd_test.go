@@ -0,0 +1,233 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcd_test
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/corestoreio/csfw/config"
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/config/storage/etcd"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ config.Storager = (*etcd.Storage)(nil)
+var _ etcd.Client = (*fakeClient)(nil)
+
+// fakeClient is an in-process stand-in for an etcd cluster.
+type fakeClient struct {
+	mu       sync.Mutex
+	kv       map[string]string
+	watchers []chan etcd.Event
+	putErr   error
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{
+		kv: make(map[string]string),
+	}
+}
+
+func (fc *fakeClient) Put(_ context.Context, key, value string) error {
+	if fc.putErr != nil {
+		return fc.putErr
+	}
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	fc.kv[key] = value
+	for _, w := range fc.watchers {
+		w <- etcd.Event{Key: key}
+	}
+	return nil
+}
+
+func (fc *fakeClient) Get(_ context.Context, key string) (string, bool, error) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	v, ok := fc.kv[key]
+	return v, ok, nil
+}
+
+func (fc *fakeClient) Keys(_ context.Context, prefix string) ([]string, error) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	var keys []string
+	for k := range fc.kv {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (fc *fakeClient) Watch(ctx context.Context, _ string) <-chan etcd.Event {
+	ch := make(chan etcd.Event, 10)
+	fc.mu.Lock()
+	fc.watchers = append(fc.watchers, ch)
+	fc.mu.Unlock()
+	go func() {
+		<-ctx.Done()
+		fc.mu.Lock()
+		defer fc.mu.Unlock()
+		close(ch)
+		fc.watchers = nil
+	}()
+	return ch
+}
+
+func TestStorageSetGet(t *testing.T) {
+	fc := newFakeClient()
+	s := etcd.New(fc)
+
+	p := cfgpath.MustNewByParts("web/unsecure/base_url").BindStore(2)
+	assert.NoError(t, s.Set(p, "http://corestore.io"))
+	assert.NoError(t, s.Set(cfgpath.MustNewByParts("aa/bb/cc"), 4711))
+
+	assert.Exactly(t, "http://corestore.io", fc.kv["csfw/config/stores/2/web/unsecure/base_url"])
+
+	v, err := s.Get(p)
+	assert.NoError(t, err)
+	assert.Exactly(t, "http://corestore.io", v)
+
+	v, err = s.Get(cfgpath.MustNewByParts("aa/bb/cc"))
+	assert.NoError(t, err)
+	assert.Exactly(t, "4711", v)
+
+	v, err = s.Get(p.BindWebsite(1))
+	assert.True(t, errors.IsNotFound(err), "Error: %+v", err)
+	assert.Nil(t, v)
+}
+
+func TestStorageSetError(t *testing.T) {
+	fc := newFakeClient()
+	fc.putErr = errors.NewTemporaryf("cluster unavailable")
+	s := etcd.New(fc)
+	err := s.Set(cfgpath.MustNewByParts("aa/bb/cc"), 1)
+	assert.True(t, errors.IsTemporary(errors.Cause(err)), "Error: %+v", err)
+
+	err = s.Set(cfgpath.Path{Route: cfgpath.NewRoute("a/b")}, 1)
+	assert.True(t, errors.IsNotValid(errors.Cause(err)), "Error: %+v", err)
+}
+
+func TestStorageAllKeys(t *testing.T) {
+	fc := newFakeClient()
+	fc.kv["csfw/config/default/0/aa/bb/cc"] = "1"
+	fc.kv["csfw/config/websites/3/aa/bb/cc"] = "2"
+	fc.kv["csfw/config/stores/4/xx/yy/zz"] = "3"
+	fc.kv["csfw/config/stores/x/xx/yy/zz"] = "invalid scope ID"
+	fc.kv["other/key"] = "ignored"
+	s := etcd.New(fc)
+
+	keys, err := s.AllKeys()
+	assert.NoError(t, err)
+	want := []string{
+		"default/0/aa/bb/cc",
+		"stores/4/xx/yy/zz",
+		"websites/3/aa/bb/cc",
+	}
+	have := make([]string, len(keys))
+	for i, k := range keys {
+		have[i] = k.String()
+	}
+	assert.Exactly(t, want, have)
+}
+
+type testPublisher struct {
+	paths chan cfgpath.Path
+}
+
+func (tp testPublisher) Publish(p cfgpath.Path) {
+	tp.paths <- p
+}
+
+func TestStorageWatch(t *testing.T) {
+	fc := newFakeClient()
+	s := etcd.New(fc)
+	pub := testPublisher{paths: make(chan cfgpath.Path, 2)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error)
+	go func() { errc <- s.Watch(ctx, pub) }()
+
+	// wait until the watcher has been registered
+	for {
+		fc.mu.Lock()
+		l := len(fc.watchers)
+		fc.mu.Unlock()
+		if l > 0 {
+			break
+		}
+	}
+
+	// simulate a remote edit on another node
+	assert.NoError(t, fc.Put(ctx, "csfw/config/websites/1/aa/bb/cc", "remote"))
+	assert.NoError(t, fc.Put(ctx, "csfw/config/invalid", "skipped"))
+	assert.NoError(t, fc.Put(ctx, "csfw/config/stores/5/xx/yy/zz", "remote"))
+
+	assert.Exactly(t, "websites/1/aa/bb/cc", (<-pub.paths).String())
+	assert.Exactly(t, "stores/5/xx/yy/zz", (<-pub.paths).String())
+
+	cancel()
+	err := <-errc
+	assert.Exactly(t, context.Canceled, errors.Cause(err))
+}
+
+func TestStorageWithService(t *testing.T) {
+	fc := newFakeClient()
+	s := etcd.New(fc)
+	srv := config.MustNewService(s, config.WithPubSub())
+
+	called := make(chan cfgpath.Path, 1)
+	_, err := srv.Subscribe(cfgpath.NewRoute("aa/bb"), messageReceiver(func(p cfgpath.Path) error {
+		called <- p
+		return nil
+	}))
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error)
+	go func() { errc <- s.Watch(ctx, srv) }()
+	for {
+		fc.mu.Lock()
+		l := len(fc.watchers)
+		fc.mu.Unlock()
+		if l > 0 {
+			break
+		}
+	}
+
+	assert.NoError(t, fc.Put(ctx, "csfw/config/default/0/aa/bb/cc", "remote"))
+	assert.Exactly(t, "default/0/aa/bb/cc", (<-called).String())
+
+	str, err := srv.String(cfgpath.MustNewByParts("aa/bb/cc"))
+	assert.NoError(t, err)
+	assert.Exactly(t, "remote", str)
+
+	cancel()
+	<-errc
+	assert.NoError(t, srv.Close())
+}
+
+type messageReceiver func(cfgpath.Path) error
+
+func (mr messageReceiver) MessageConfig(p cfgpath.Path) error {
+	return mr(p)
+}
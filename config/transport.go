@@ -0,0 +1,56 @@
+// Copyright 2015-2016, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"github.com/corestoreio/csfw/config/cfgpath"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// Transport distributes written paths to the other nodes of a cluster. The pub/sub
+// system of a Service works only in-process, so without a Transport a write on
+// one node never invalidates the cached values on other nodes. Implementations
+// must filter out their own messages to avoid echo loops. See package
+// config/cfgredis for a Redis based Transport.
+type Transport interface {
+	// Broadcast sends a path, which has been written on this node, to all
+	// other nodes.
+	Broadcast(cfgpath.Path) error
+	// Listen starts to receive the paths from all other nodes and passes
+	// them to the Publisher. Listen must not block.
+	Listen(Publisher) error
+	// Close stops listening and releases all connections.
+	Close() error
+}
+
+// Close closes the Transport, if set, and terminates the pub/sub goroutine.
+// Both getting closed even if one of them fails; if both fail the returned
+// error is an *errors.MultiErr. A second call to Close returns an error with
+// behaviour AlreadyClosed, if the pub/sub service has been started.
+func (s *Service) Close() error {
+	var tErr error
+	if s.transport != nil {
+		if err := s.transport.Close(); err != nil {
+			tErr = errors.Wrap(err, "[config] Service.Close.Transport")
+		}
+	}
+	if err := s.pubSub.Close(); err != nil {
+		if tErr == nil {
+			return err
+		}
+		return errors.NewMultiErr(tErr, err)
+	}
+	return tErr
+}
@@ -42,6 +42,11 @@ func (ir IPRange) In(test gonet.IP) bool {
 	return ir.from != nil && ir.to != nil && tv6 != nil && bytes.Compare(tv6, ir.from) >= 0 && bytes.Compare(tv6, ir.to) <= 0
 }
 
+// String returns the range in the format From-To.
+func (ir IPRange) String() string {
+	return ir.from.String() + "-" + ir.to.String()
+}
+
 // InStr checks if the test IP address string lies within the range.
 func (ir IPRange) InStr(ip string) bool {
 	return ir.In(gonet.ParseIP(ip))
@@ -117,3 +117,12 @@ func BenchmarkIPRangeV4(b *testing.B) {
 		b.Fatal("benchmarkIPRange must be true")
 	}
 }
+
+func TestIPRange_String(t *testing.T) {
+	if have, want := csnet.NewIPRange("74.50.146.0", "74.50.146.4").String(), "74.50.146.0-74.50.146.4"; have != want {
+		t.Errorf("Have %q Want %q", have, want)
+	}
+	if have, want := csnet.NewIPRange("2001:db8::", "2001:db8::ff").String(), "2001:db8::-2001:db8::ff"; have != want {
+		t.Errorf("Have %q Want %q", have, want)
+	}
+}
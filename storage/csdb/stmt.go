@@ -20,6 +20,7 @@ import (
 	"time"
 
 	"github.com/corestoreio/csfw/log"
+	"github.com/corestoreio/csfw/storage/dbr"
 	"github.com/corestoreio/csfw/util/errors"
 )
 
@@ -77,6 +78,19 @@ func NewResurrectStmt(p Preparer, SQL string) *ResurrectStmt {
 	}
 }
 
+// NewResurrectStmtBuilder creates a new resurrected statement from the SQL of
+// a dbr builder. The arguments of the builder are ignored, pass them to the
+// functions of the sql.Stmt.
+//		rs, err := csdb.NewResurrectStmtBuilder(db, sess.Select("value").From("core_config_data").
+//			Where(dbr.ConditionRaw("path = ?", "")))
+func NewResurrectStmtBuilder(p Preparer, b dbr.QueryBuilder) (*ResurrectStmt, error) {
+	query, _, err := b.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "[csdb] NewResurrectStmtBuilder.ToSql")
+	}
+	return NewResurrectStmt(p, query), nil
+}
+
 // StartIdleChecker starts the internal goroutine which checks the idle time.
 // You can only start it once. sql.Stmt.Close() errors gets logged to Info. Those
 // errors will only be returned if you stop the idle checker goroutine.
@@ -140,6 +140,34 @@ func TestResurrectStmtSqlMockShouldPrepareOnceAndThenBecomeIdle(t *testing.T) {
 	}
 }
 
+func TestNewResurrectStmtBuilder(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	dbc, err := dbr.NewConnection(dbr.WithDB(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rs, err := csdb.NewResurrectStmtBuilder(db, dbc.NewSession().InsertInto("xtable").Columns("path", "value").Values("", 0))
+	assert.NoError(t, err)
+	assert.Exactly(t, "INSERT INTO xtable (`path`,`value`) VALUES (?,?)", rs.SQL)
+	tw := &typeWriter{
+		Write: rs,
+	}
+
+	mock.ExpectPrepare("INSERT INTO xtable \\(`path`,`value`\\) VALUES .+").
+		ExpectExec().WithArgs("gopher", 3141).WillReturnResult(sqlmock.NewResult(1, 1))
+	assert.NoError(t, tw.Save("gopher", 3141))
+	assert.NoError(t, tw.Write.StopIdleChecker())
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expections: %s", err)
+	}
+}
+
 func TestResurrectStmtSqlMockShouldPrepareTwoTimesWithThreeCalls(t *testing.T) {
 
 	db, mock, err := sqlmock.New()
@@ -3,6 +3,8 @@ package dbr
 type alias struct {
 	Expression string
 	Alias      string
+	// Select contains a derived table. If set, Expression gets ignored.
+	Select *SelectBuilder
 }
 
 func newAlias(as ...string) alias {
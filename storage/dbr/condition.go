@@ -0,0 +1,191 @@
+package dbr
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// comparisonOperators contains the allowed operators for CompareColumns.
+var comparisonOperators = map[string]bool{
+	"=":   true,
+	"<>":  true,
+	"!=":  true,
+	"<":   true,
+	"<=":  true,
+	">":   true,
+	">=":  true,
+	"<=>": true,
+}
+
+// conditionOp creates a condition where the column gets quoted and the
+// operator and the placeholders are getting appended.
+func conditionOp(column, operator string, values ...interface{}) ConditionArg {
+	return func(wf *whereFragment) {
+		wf.Condition = Quoter.QuoteAs(column) + operator
+		wf.Values = values
+	}
+}
+
+// Equal creates the condition `column` = ?. A nil value creates an IS NULL
+// condition.
+func Equal(column string, value interface{}) ConditionArg {
+	if value == nil {
+		return IsNull(column)
+	}
+	return conditionOp(column, " = ?", value)
+}
+
+// NotEqual creates the condition `column` != ?. A nil value creates an IS
+// NOT NULL condition.
+func NotEqual(column string, value interface{}) ConditionArg {
+	if value == nil {
+		return IsNotNull(column)
+	}
+	return conditionOp(column, " != ?", value)
+}
+
+// Gt creates the condition `column` > ?.
+func Gt(column string, value interface{}) ConditionArg {
+	return conditionOp(column, " > ?", value)
+}
+
+// Gte creates the condition `column` >= ?.
+func Gte(column string, value interface{}) ConditionArg {
+	return conditionOp(column, " >= ?", value)
+}
+
+// Lt creates the condition `column` < ?.
+func Lt(column string, value interface{}) ConditionArg {
+	return conditionOp(column, " < ?", value)
+}
+
+// Lte creates the condition `column` <= ?.
+func Lte(column string, value interface{}) ConditionArg {
+	return conditionOp(column, " <= ?", value)
+}
+
+// Between creates the condition `column` BETWEEN ? AND ?.
+func Between(column string, min, max interface{}) ConditionArg {
+	return conditionOp(column, " BETWEEN ? AND ?", min, max)
+}
+
+// NotBetween creates the condition `column` NOT BETWEEN ? AND ?.
+func NotBetween(column string, min, max interface{}) ConditionArg {
+	return conditionOp(column, " NOT BETWEEN ? AND ?", min, max)
+}
+
+// Like creates the condition `column` LIKE ?. The wild cards must be part of
+// the pattern.
+func Like(column string, pattern string) ConditionArg {
+	return conditionOp(column, " LIKE ?", pattern)
+}
+
+// NotLike creates the condition `column` NOT LIKE ?.
+func NotLike(column string, pattern string) ConditionArg {
+	return conditionOp(column, " NOT LIKE ?", pattern)
+}
+
+// IsNull creates the condition `column` IS NULL.
+func IsNull(column string) ConditionArg {
+	return conditionOp(column, " IS NULL")
+}
+
+// IsNotNull creates the condition `column` IS NOT NULL.
+func IsNotNull(column string) ConditionArg {
+	return conditionOp(column, " IS NOT NULL")
+}
+
+// In creates the condition `column` IN (?,?,?) with one placeholder per
+// value. A single slice argument gets expanded into its elements. Without any
+// values the condition is always false.
+//		In("entity_id", 1, 2, 3)
+//		In("entity_id", []int64{1, 2, 3})
+func In(column string, values ...interface{}) ConditionArg {
+	values = expandSlice(values)
+	if len(values) == 0 {
+		return ConditionRaw("1=0")
+	}
+	return conditionOp(column, " IN ("+placeholders(len(values))+")", values...)
+}
+
+// NotIn creates the condition `column` NOT IN (?,?,?) with one placeholder
+// per value. A single slice argument gets expanded into its elements. Without
+// any values the condition is always true.
+func NotIn(column string, values ...interface{}) ConditionArg {
+	values = expandSlice(values)
+	if len(values) == 0 {
+		return ConditionRaw("1=1")
+	}
+	return conditionOp(column, " NOT IN ("+placeholders(len(values))+")", values...)
+}
+
+// expandSlice returns the elements of a single slice or array argument. A
+// []byte stays a single value.
+func expandSlice(values []interface{}) []interface{} {
+	if len(values) != 1 {
+		return values
+	}
+	if _, ok := values[0].([]byte); ok {
+		return values
+	}
+	rv := reflect.ValueOf(values[0])
+	if k := rv.Kind(); k != reflect.Slice && k != reflect.Array {
+		return values
+	}
+	ret := make([]interface{}, rv.Len())
+	for i := range ret {
+		ret[i] = rv.Index(i).Interface()
+	}
+	return ret
+}
+
+// CompareColumns compares two columns with each other, for example in a
+// JOIN condition. Both columns get quoted. Allowed operators: =, <>, !=, <,
+// <=, >, >= and <=>. Any other operator returns a NotValid error when
+// creating the SQL.
+func CompareColumns(left, operator, right string) ConditionArg {
+	return func(wf *whereFragment) {
+		if !comparisonOperators[operator] {
+			wf.err = errors.NewNotValidf("[dbr] CompareColumns: Unknown operator %q", operator)
+			return
+		}
+		wf.Condition = Quoter.QuoteAs(left) + " " + operator + " " + Quoter.QuoteAs(right)
+	}
+}
+
+// EqualColumns creates the condition `left` = `right`.
+func EqualColumns(left, right string) ConditionArg {
+	return CompareColumns(left, "=", right)
+}
+
+// And glues the conditions together with AND and wraps them in parenthesis.
+// Mostly useful within Or. Without any conditions the condition is always
+// true.
+func And(conditions ...ConditionArg) ConditionArg {
+	return nested(false, "1=1", conditions...)
+}
+
+// Or glues the conditions together with OR and wraps them in parenthesis.
+// Without any conditions the condition is always false.
+//		Or(Gt("qty", 0), And(Equal("backorders", 1), IsNull("stock_id")))
+//		((`qty` > ?) OR ((`backorders` = ?) AND (`stock_id` IS NULL)))
+func Or(conditions ...ConditionArg) ConditionArg {
+	return nested(true, "1=0", conditions...)
+}
+
+func nested(isOr bool, empty string, conditions ...ConditionArg) ConditionArg {
+	return func(wf *whereFragment) {
+		if len(conditions) == 0 {
+			wf.Condition = empty
+			return
+		}
+		wf.Nested = newWhereFragments(conditions...)
+		wf.IsOr = isOr
+	}
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
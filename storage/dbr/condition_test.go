@@ -0,0 +1,106 @@
+package dbr
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConditions(t *testing.T) {
+	tests := []struct {
+		cond     ConditionArg
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{Equal("a", 1), "(`a` = ?)", []interface{}{1}},
+		{Equal("a", nil), "(`a` IS NULL)", nil},
+		{NotEqual("e.a", "x"), "(`e`.`a` != ?)", []interface{}{"x"}},
+		{NotEqual("a", nil), "(`a` IS NOT NULL)", nil},
+		{Gt("price", 9.99), "(`price` > ?)", []interface{}{9.99}},
+		{Gte("price", 10), "(`price` >= ?)", []interface{}{10}},
+		{Lt("qty", 0), "(`qty` < ?)", []interface{}{0}},
+		{Lte("qty", 5), "(`qty` <= ?)", []interface{}{5}},
+		{Between("entity_id", 2, 8), "(`entity_id` BETWEEN ? AND ?)", []interface{}{2, 8}},
+		{NotBetween("entity_id", 2, 8), "(`entity_id` NOT BETWEEN ? AND ?)", []interface{}{2, 8}},
+		{Like("sku", "SHIRT-%"), "(`sku` LIKE ?)", []interface{}{"SHIRT-%"}},
+		{NotLike("sku", "%-X"), "(`sku` NOT LIKE ?)", []interface{}{"%-X"}},
+		{IsNull("e.value"), "(`e`.`value` IS NULL)", nil},
+		{IsNotNull("value"), "(`value` IS NOT NULL)", nil},
+		{In("store_id", 1, 2, 3), "(`store_id` IN (?,?,?))", []interface{}{1, 2, 3}},
+		{In("store_id"), "(1=0)", nil},
+		{In("entity_id", []int64{4, 5, 6}), "(`entity_id` IN (?,?,?))", []interface{}{int64(4), int64(5), int64(6)}},
+		{In("entity_id", []int64{}), "(1=0)", nil},
+		{In("sku", []string{"a"}), "(`sku` IN (?))", []interface{}{"a"}},
+		{NotIn("type_id", "simple", "virtual"), "(`type_id` NOT IN (?,?))", []interface{}{"simple", "virtual"}},
+		{NotIn("type_id"), "(1=1)", nil},
+		{NotIn("type_id", []string{"simple", "virtual"}), "(`type_id` NOT IN (?,?))", []interface{}{"simple", "virtual"}},
+		{NotIn("store_id", [2]int{1, 2}), "(`store_id` NOT IN (?,?))", []interface{}{1, 2}},
+		{EqualColumns("t.entity_id", "c.parent_id"), "(`t`.`entity_id` = `c`.`parent_id`)", nil},
+		{CompareColumns("updated_at", ">=", "created_at"), "(`updated_at` >= `created_at`)", nil},
+		{Or(Gt("qty", 0), And(Equal("backorders", 1), IsNull("stock_id"))), "((`qty` > ?) OR ((`backorders` = ?) AND (`stock_id` IS NULL)))", []interface{}{0, 1}},
+		{Or(), "(1=0)", nil},
+		{And(), "(1=1)", nil},
+		{Or(ConditionRaw("a = ?", 1), ConditionMap(Eq{"b": 2})), "((a = ?) OR (`b` = ?))", []interface{}{1, 2}},
+		{Or(Gt("qty", 1), ConditionMap(Eq{})), "((`qty` > ?))", []interface{}{1}},
+		{Or(ConditionMap(Eq{}), Gt("qty", 1), And(ConditionMap(Eq{}))), "((`qty` > ?))", []interface{}{1}},
+	}
+	for i, test := range tests {
+		var args []interface{}
+		buf := new(bytes.Buffer)
+		err := writeWhereFragmentsToSql(newWhereFragments(test.cond), buf, &args)
+		assert.NoError(t, err, "Index %d", i)
+		assert.Exactly(t, test.wantSQL, buf.String(), "Index %d", i)
+		assert.Exactly(t, test.wantArgs, args, "Index %d", i)
+	}
+}
+
+func TestConditionsBuilders(t *testing.T) {
+	s := createFakeSession()
+
+	sql, args, err := s.Select("e.entity_id").From("catalog_product_entity", "e").
+		Join(JoinTable("cataloginventory_stock_item", "si"), nil, EqualColumns("si.product_id", "e.entity_id"), Gt("si.qty", 0)).
+		Where(In("e.type_id", "simple", "virtual"), Or(Like("e.sku", "A%"), Between("e.entity_id", 10, 20))).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Exactly(t, "SELECT e.entity_id FROM `catalog_product_entity` AS `e` INNER JOIN `cataloginventory_stock_item` AS `si` ON (`si`.`product_id` = `e`.`entity_id`) AND (`si`.`qty` > ?) WHERE (`e`.`type_id` IN (?,?)) AND ((`e`.`sku` LIKE ?) OR (`e`.`entity_id` BETWEEN ? AND ?))", sql)
+	assert.Exactly(t, []interface{}{0, "simple", "virtual", "A%", 10, 20}, args)
+
+	sql, args, err = s.Update("catalog_product_entity").Set("has_options", 0).
+		Where(NotIn("entity_id", 1, 2), IsNull("required_options")).ToSql()
+	assert.NoError(t, err)
+	assert.Exactly(t, "UPDATE `catalog_product_entity` SET `has_options` = ? WHERE (`entity_id` NOT IN (?,?)) AND (`required_options` IS NULL)", sql)
+	assert.Exactly(t, []interface{}{0, 1, 2}, args)
+
+	sql, args, err = s.DeleteFrom("core_config_data").Where(Like("path", "dev/%"), Lt("scope_id", 3)).ToSql()
+	assert.NoError(t, err)
+	assert.Exactly(t, "DELETE FROM `core_config_data` WHERE (`path` LIKE ?) AND (`scope_id` < ?)", sql)
+	assert.Exactly(t, []interface{}{"dev/%", 3}, args)
+
+	full, err := Preprocess(sql, args)
+	assert.NoError(t, err)
+	assert.Exactly(t, "DELETE FROM `core_config_data` WHERE (`path` LIKE 'dev/%') AND (`scope_id` < 3)", full)
+}
+
+func TestCompareColumnsInvalidOperator(t *testing.T) {
+	s := createFakeSession()
+	_, _, err := s.Select("a").From("b").Where(Or(Gt("c", 1), CompareColumns("d", "; DROP", "e"))).ToSql()
+	assert.True(t, errors.IsNotValid(err), "%+v", err)
+}
+
+func TestConditionInSliceInterpolate(t *testing.T) {
+	s := createFakeSession()
+	sql, args, err := s.Select("a").From("b").Where(In("entity_id", []int64{1, 2, 3}), NotIn("sku", []string{"x"})).ToSql()
+	assert.NoError(t, err)
+	full, err := Preprocess(sql, args)
+	assert.NoError(t, err)
+	assert.Exactly(t, "SELECT a FROM `b` WHERE (`entity_id` IN (1,2,3)) AND (`sku` NOT IN ('x'))", full)
+}
+
+func TestConditionNestedEmpty(t *testing.T) {
+	s := createFakeSession()
+	sql, _, err := s.Select("a").From("b").Where(Equal("c", 1), And(ConditionMap(Eq{})), Or(ConditionMap(Eq{}), Gt("qty", 1))).ToSql()
+	assert.NoError(t, err)
+	assert.Exactly(t, "SELECT a FROM `b` WHERE (`c` = ?) AND ((`qty` > ?))", sql)
+}
@@ -0,0 +1,106 @@
+package dbr
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func createMockSession(t *testing.T) (*Session, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cxn, err := NewConnection(WithDB(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cxn.NewSession(), mock
+}
+
+func TestContextCanceledBeforeQuery(t *testing.T) {
+	s, mock := createMockSession(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := s.InsertInto("a").Columns("b").Values(1).ExecContext(ctx)
+	assert.EqualError(t, err, context.Canceled.Error())
+	_, err = s.Update("a").Set("b", 1).ExecContext(ctx)
+	assert.EqualError(t, err, context.Canceled.Error())
+	_, err = s.DeleteFrom("a").Where(ConditionRaw("b = ?", 1)).ExecContext(ctx)
+	assert.EqualError(t, err, context.Canceled.Error())
+	var people []*dbrPerson
+	_, err = s.Select("*").From("dbr_people").LoadStructsContext(ctx, &people)
+	assert.EqualError(t, err, context.Canceled.Error())
+	_, err = s.BeginTx(ctx, nil)
+	assert.EqualError(t, err, context.Canceled.Error())
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestContextTimeout(t *testing.T) {
+	s, mock := createMockSession(t)
+
+	mock.ExpectExec("INSERT INTO a").WillDelayFor(time.Second).WillReturnResult(sqlmock.NewResult(1, 1))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := s.InsertInto("a").Columns("b").Values(1).ExecContext(ctx)
+	assert.EqualError(t, err, "canceling query due to user request")
+
+	mock.ExpectQuery("SELECT (.+) FROM `dbr_people`").WillDelayFor(time.Second).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Gopher"))
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	var people []*dbrPerson
+	_, err = s.Select("*").From("dbr_people").LoadStructsContext(ctx, &people)
+	assert.EqualError(t, err, "canceling query due to user request")
+	assert.Empty(t, people)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestContextQuery(t *testing.T) {
+	s, mock := createMockSession(t)
+	ctx := context.Background()
+
+	mock.ExpectQuery("SELECT (.+) FROM `dbr_people`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Gopher").AddRow(2, "Rustacean"))
+	var people []*dbrPerson
+	n, err := s.Select("*").From("dbr_people").LoadStructsContext(ctx, &people)
+	assert.NoError(t, err)
+	assert.Exactly(t, 2, n)
+	assert.Exactly(t, "Rustacean", people[1].Name)
+
+	mock.ExpectQuery("SELECT name FROM `dbr_people`").WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("Gopher"))
+	var name string
+	assert.NoError(t, s.Select("name").From("dbr_people").Where(ConditionRaw("id = ?", 1)).LoadValueContext(ctx, &name))
+	assert.Exactly(t, "Gopher", name)
+
+	mock.ExpectExec("UPDATE `dbr_people` SET `name` = 'Gopher' WHERE \\(id = 1\\)").WillReturnResult(sqlmock.NewResult(0, 1))
+	res, err := s.Update("dbr_people").Set("name", "Gopher").Where(ConditionRaw("id = ?", 1)).ExecContext(ctx)
+	assert.NoError(t, err)
+	ra, _ := res.RowsAffected()
+	assert.Exactly(t, int64(1), ra)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBeginTx(t *testing.T) {
+	s, mock := createMockSession(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM `dbr_people`").WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	tx, err := s.BeginTx(context.Background(), &sql.TxOptions{Isolation: sql.LevelDefault})
+	assert.NoError(t, err)
+	_, err = tx.DeleteFrom("dbr_people").ExecContext(context.Background())
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Commit())
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
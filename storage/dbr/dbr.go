@@ -1,6 +1,7 @@
 package dbr
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/corestoreio/csfw/util/errors"
@@ -167,6 +168,8 @@ func (s *Session) ApplyOpts(opts ...SessionOption) (previous SessionOption) {
 }
 
 // SessionRunner can do anything that a Session can except start a transaction.
+// All builders provide context aware functions like ExecContext or
+// LoadStructsContext to cancel a running query.
 type SessionRunner interface {
 	Select(cols ...string) *SelectBuilder
 	SelectBySql(sql string, args ...interface{}) *SelectBuilder
@@ -177,7 +180,11 @@ type SessionRunner interface {
 	DeleteFrom(from ...string) *DeleteBuilder
 }
 
+// runner gets implemented by *sql.DB and *sql.Tx.
 type runner interface {
 	Exec(query string, args ...interface{}) (sql.Result, error)
 	Query(query string, args ...interface{}) (*sql.Rows, error)
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
 }
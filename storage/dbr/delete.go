@@ -1,11 +1,13 @@
 package dbr
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"time"
 
 	"github.com/corestoreio/csfw/util/bufferpool"
+	"github.com/corestoreio/csfw/util/errors"
 )
 
 // DeleteBuilder contains the clauses for a DELETE statement
@@ -22,7 +24,7 @@ type DeleteBuilder struct {
 	OffsetValid    bool
 }
 
-var _ queryBuilder = (*DeleteBuilder)(nil)
+var _ QueryBuilder = (*DeleteBuilder)(nil)
 
 // DeleteFrom creates a new DeleteBuilder for the given table
 func (sess *Session) DeleteFrom(from ...string) *DeleteBuilder {
@@ -97,7 +99,9 @@ func (b *DeleteBuilder) ToSql() (string, []interface{}, error) {
 	// Write WHERE clause if we have any fragments
 	if len(b.WhereFragments) > 0 {
 		sql.WriteString(" WHERE ")
-		writeWhereFragmentsToSql(b.WhereFragments, sql, &args)
+		if err := writeWhereFragmentsToSql(b.WhereFragments, sql, &args); err != nil {
+			return "", nil, errors.Wrap(err, "[dbr] DeleteBuilder.ToSql.Where")
+		}
 	}
 
 	// Ordering and limiting
@@ -126,6 +130,12 @@ func (b *DeleteBuilder) ToSql() (string, []interface{}, error) {
 // Exec executes the statement represented by the DeleteBuilder
 // It returns the raw database/sql Result and an error if there was one
 func (b *DeleteBuilder) Exec() (sql.Result, error) {
+	return b.ExecContext(context.Background())
+}
+
+// ExecContext same as Exec but the statement gets canceled when the context
+// expires.
+func (b *DeleteBuilder) ExecContext(ctx context.Context) (sql.Result, error) {
 	sql, args, err := b.ToSql()
 	if err != nil {
 		return nil, b.EventErrKv("dbr.delete.exec.tosql", err, nil)
@@ -140,7 +150,7 @@ func (b *DeleteBuilder) Exec() (sql.Result, error) {
 	startTime := time.Now()
 	defer func() { b.TimingKv("dbr.delete", time.Since(startTime).Nanoseconds(), kvs{"sql": fullSql}) }()
 
-	result, err := b.runner.Exec(fullSql)
+	result, err := b.runner.ExecContext(ctx, fullSql)
 	if err != nil {
 		return result, b.EventErrKv("dbr.delete.exec.exec", err, kvs{"sql": fullSql})
 	}
@@ -2,10 +2,12 @@ package dbr
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"database/sql/driver"
 	"fmt"
 	"reflect"
+	"sort"
 	"time"
 
 	"github.com/corestoreio/csfw/util/bufferpool"
@@ -22,9 +24,19 @@ type InsertBuilder struct {
 	Vals [][]interface{}
 	Recs []interface{}
 	Maps map[string]interface{}
+
+	// IsIgnore writes INSERT IGNORE INTO
+	IsIgnore bool
+	// IsReplace writes REPLACE INTO
+	IsReplace bool
+	// DupKeys contains the assignments of the ON DUPLICATE KEY UPDATE clause.
+	DupKeys []*DupKey
+	// IsDupKeyAll updates all inserted columns, from Cols or the keys of
+	// Maps, in the ON DUPLICATE KEY UPDATE clause.
+	IsDupKeyAll bool
 }
 
-var _ queryBuilder = (*InsertBuilder)(nil)
+var _ QueryBuilder = (*InsertBuilder)(nil)
 
 // InsertInto instantiates a InsertBuilder for the given table
 func (sess *Session) InsertInto(into string) *InsertBuilder {
@@ -114,14 +126,29 @@ func (b *InsertBuilder) ToSql() (string, []interface{}, error) {
 		}
 	}
 
+	if b.IsReplace && (b.IsIgnore || len(b.DupKeys) > 0 || b.IsDupKeyAll) {
+		return "", nil, errors.NewNotValidf("[dbr] REPLACE cannot be combined with IGNORE or ON DUPLICATE KEY UPDATE")
+	}
+
 	var sql = bufferpool.Get()
 
-	sql.WriteString("INSERT INTO ")
+	switch {
+	case b.IsReplace:
+		sql.WriteString("REPLACE INTO ")
+	case b.IsIgnore:
+		sql.WriteString("INSERT IGNORE INTO ")
+	default:
+		sql.WriteString("INSERT INTO ")
+	}
 	sql.WriteString(b.Into)
 	sql.WriteString(" (")
 
 	if len(b.Maps) != 0 {
-		return b.MapToSql(sql)
+		query, args, err := b.MapToSql(sql)
+		if err != nil {
+			return "", nil, err
+		}
+		return b.appendOnDuplicateKey(query, args)
 	}
 	defer bufferpool.Put(sql)
 
@@ -173,7 +200,7 @@ func (b *InsertBuilder) ToSql() (string, []interface{}, error) {
 		}
 	}
 
-	return sql.String(), args, nil
+	return b.appendOnDuplicateKey(sql.String(), args)
 }
 
 // MapToSql serialized the InsertBuilder to a SQL string
@@ -181,11 +208,14 @@ func (b *InsertBuilder) ToSql() (string, []interface{}, error) {
 // It returns the string with placeholders and a slice of query arguments
 func (b *InsertBuilder) MapToSql(sql *bytes.Buffer) (string, []interface{}, error) {
 	defer bufferpool.Put(sql)
-	keys := make([]string, len(b.Maps))
+	keys := make([]string, 0, len(b.Maps))
+	for k := range b.Maps {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys) // stable SQL for prepared statements
 	vals := make([]interface{}, len(b.Maps))
-	i := 0
-	for k, v := range b.Maps {
-		keys[i] = k
+	for i, k := range keys {
+		v := b.Maps[k]
 		if dbVal, ok := v.(driver.Valuer); ok {
 			if val, err := dbVal.Value(); err == nil {
 				vals[i] = val
@@ -195,7 +225,6 @@ func (b *InsertBuilder) MapToSql(sql *bytes.Buffer) (string, []interface{}, erro
 		} else {
 			vals[i] = v
 		}
-		i++
 	}
 	var args []interface{}
 	var placeholder = bufferpool.Get() // Build the placeholder like "(?,?,?)"
@@ -228,6 +257,12 @@ func (b *InsertBuilder) MapToSql(sql *bytes.Buffer) (string, []interface{}, erro
 // the first inserted row only. The reason for this is to make it possible to
 // reproduce easily the same INSERT statement against some other server.
 func (b *InsertBuilder) Exec() (sql.Result, error) {
+	return b.ExecContext(context.Background())
+}
+
+// ExecContext same as Exec but the statement gets canceled when the context
+// expires.
+func (b *InsertBuilder) ExecContext(ctx context.Context) (sql.Result, error) {
 	sql, args, err := b.ToSql()
 	if err != nil {
 		return nil, b.EventErrKv("dbr.insert.exec.tosql", err, nil)
@@ -242,7 +277,7 @@ func (b *InsertBuilder) Exec() (sql.Result, error) {
 	startTime := time.Now()
 	defer func() { b.TimingKv("dbr.insert", time.Since(startTime).Nanoseconds(), kvs{"sql": fullSql}) }()
 
-	result, err := b.runner.Exec(fullSql)
+	result, err := b.runner.ExecContext(ctx, fullSql)
 	if err != nil {
 		return result, b.EventErrKv("dbr.insert.exec.exec", err, kvs{"sql": fullSql})
 	}
@@ -0,0 +1,234 @@
+package dbr
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"strings"
+
+	"github.com/corestoreio/csfw/util/bufferpool"
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// DupKey an assignment in the ON DUPLICATE KEY UPDATE clause. An empty Expr
+// assigns the new value of the column: `col`=VALUES(`col`).
+type DupKey struct {
+	Column string
+	// Expr optional SQL expression with placeholders, e.g.
+	// "`qty`+VALUES(`qty`)".
+	Expr string
+	Args []interface{}
+}
+
+// Ignore writes INSERT IGNORE INTO to skip rows which would violate a unique
+// key.
+func (b *InsertBuilder) Ignore() *InsertBuilder {
+	b.IsIgnore = true
+	return b
+}
+
+// Replace writes REPLACE INTO. An existing row with the same primary or
+// unique key gets deleted before the new row gets inserted.
+func (b *InsertBuilder) Replace() *InsertBuilder {
+	b.IsReplace = true
+	return b
+}
+
+// OnDuplicateKey appends an ON DUPLICATE KEY UPDATE clause which updates the
+// columns with the new values. If no column has been provided all inserted
+// columns get updated. These columns are resolved when the SQL gets created,
+// so OnDuplicateKey can be called before Columns or together with Map.
+//		sess.InsertInto("core_config_data").Columns("scope", "scope_id", "path", "value").
+//			Values("default", 0, "web/unsecure/base_url", "http://corestore.io").
+//			OnDuplicateKey("value")
+//		// INSERT INTO core_config_data (`scope`,`scope_id`,`path`,`value`) VALUES (?,?,?,?)
+//		// ON DUPLICATE KEY UPDATE `value`=VALUES(`value`)
+func (b *InsertBuilder) OnDuplicateKey(columns ...string) *InsertBuilder {
+	if len(columns) == 0 {
+		b.IsDupKeyAll = true
+		return b
+	}
+	for _, c := range columns {
+		b.DupKeys = append(b.DupKeys, &DupKey{Column: c})
+	}
+	return b
+}
+
+// OnDuplicateKeyExpr appends an assignment with an SQL expression to the ON
+// DUPLICATE KEY UPDATE clause.
+//		OnDuplicateKeyExpr("qty", "`qty`+VALUES(`qty`)")
+//		OnDuplicateKeyExpr("updated_at", "?", time.Now())
+func (b *InsertBuilder) OnDuplicateKeyExpr(column, expr string, args ...interface{}) *InsertBuilder {
+	b.DupKeys = append(b.DupKeys, &DupKey{Column: column, Expr: expr, Args: args})
+	return b
+}
+
+// appendOnDuplicateKey appends the ON DUPLICATE KEY UPDATE clause and its
+// arguments.
+func (b *InsertBuilder) appendOnDuplicateKey(query string, args []interface{}) (string, []interface{}, error) {
+	dupKeys := b.DupKeys
+	if b.IsDupKeyAll {
+		dupKeys = b.allDupKeys()
+	}
+	if len(dupKeys) == 0 {
+		return query, args, nil
+	}
+	var sql = bufferpool.Get()
+	defer bufferpool.Put(sql)
+
+	sql.WriteString(query)
+	sql.WriteString(" ON DUPLICATE KEY UPDATE ")
+	for i, dk := range dupKeys {
+		if i > 0 {
+			sql.WriteString(", ")
+		}
+		Quoter.writeQuotedColumn(dk.Column, sql)
+		sql.WriteRune('=')
+		if dk.Expr == "" {
+			sql.WriteString("VALUES(")
+			Quoter.writeQuotedColumn(dk.Column, sql)
+			sql.WriteRune(')')
+			continue
+		}
+		sql.WriteString(dk.Expr)
+		args = append(args, dk.Args...)
+	}
+	return sql.String(), args, nil
+}
+
+// allDupKeys returns an assignment for each inserted column followed by the
+// DupKeys. Inserted columns which have an explicit DupKey are skipped.
+func (b *InsertBuilder) allDupKeys() []*DupKey {
+	cols := b.Cols
+	if len(b.Maps) > 0 {
+		cols = make([]string, 0, len(b.Maps))
+		for k := range b.Maps {
+			cols = append(cols, k)
+		}
+		sort.Strings(cols) // same order as MapToSql
+	}
+	explicit := make(map[string]bool, len(b.DupKeys))
+	for _, dk := range b.DupKeys {
+		explicit[dk.Column] = true
+	}
+	dupKeys := make([]*DupKey, 0, len(cols)+len(b.DupKeys))
+	for _, c := range cols {
+		if !explicit[c] {
+			dupKeys = append(dupKeys, &DupKey{Column: c})
+		}
+	}
+	return append(dupKeys, b.DupKeys...)
+}
+
+// MaxAllowedPacket returns the server variable max_allowed_packet in bytes
+// which limits the size of a statement.
+func (sess *Session) MaxAllowedPacket() (int, error) {
+	var size int
+	if err := sess.cxn.DB.QueryRow("SELECT @@max_allowed_packet").Scan(&size); err != nil {
+		return 0, sess.EventErr("dbr.max_allowed_packet", err)
+	}
+	return size, nil
+}
+
+// ExecChunks executes a bulk insert of all values and records in multiple
+// statements. Each statement gets at most maxPacket bytes, see
+// Session.MaxAllowedPacket. Returns one sql.Result per statement: its
+// RowsAffected reports the rows of the chunk and its LastInsertId the ID of
+// the first inserted row of the chunk. With ON DUPLICATE KEY UPDATE an
+// updated row counts as two affected rows. A maxPacket of zero executes one
+// statement. The ID fields of the records are not set. Returns a NotValid
+// error behaviour if a single row exceeds maxPacket.
+func (b *InsertBuilder) ExecChunks(maxPacket int) ([]sql.Result, error) {
+	return b.ExecChunksContext(context.Background(), maxPacket)
+}
+
+// ExecChunksContext same as ExecChunks but with a context.
+func (b *InsertBuilder) ExecChunksContext(ctx context.Context, maxPacket int) ([]sql.Result, error) {
+	chunks, err := b.chunks(maxPacket)
+	if err != nil {
+		return nil, b.EventErrKv("dbr.insert.exec_chunks.chunks", err, kvs{"table": b.Into})
+	}
+	results := make([]sql.Result, 0, len(chunks))
+	for _, c := range chunks {
+		res, err := c.ExecContext(ctx)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+// chunks splits the values and records into builders whose interpolated SQL
+// does not exceed maxPacket.
+func (b *InsertBuilder) chunks(maxPacket int) ([]*InsertBuilder, error) {
+	if maxPacket <= 0 || len(b.Maps) > 0 || len(b.Vals)+len(b.Recs) < 2 {
+		return []*InsertBuilder{b}, nil
+	}
+
+	rows := make([][]interface{}, 0, len(b.Vals)+len(b.Recs))
+	rows = append(rows, b.Vals...)
+	if len(b.Recs) > 0 {
+		rb := *b
+		rb.Vals = nil
+		_, args, err := rb.ToSql()
+		if err != nil {
+			return nil, errors.Wrap(err, "[dbr] InsertBuilder.chunks.ToSql")
+		}
+		for i := range b.Recs {
+			rows = append(rows, args[i*len(b.Cols):(i+1)*len(b.Cols)])
+		}
+	}
+
+	placeholder := "(" + strings.TrimSuffix(strings.Repeat("?,", len(b.Cols)), ",") + ")"
+	rowSize := func(row []interface{}) (int, error) {
+		s, err := Preprocess(placeholder, row)
+		return len(s), err
+	}
+
+	// the size of the statement without the rows
+	first := *b
+	first.Vals, first.Recs = rows[:1], nil
+	query, args, err := first.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "[dbr] InsertBuilder.chunks.ToSql")
+	}
+	full, err := Preprocess(query, args)
+	if err != nil {
+		return nil, errors.Wrap(err, "[dbr] InsertBuilder.chunks.Preprocess")
+	}
+	firstSize, err := rowSize(rows[0])
+	if err != nil {
+		return nil, errors.Wrap(err, "[dbr] InsertBuilder.chunks.Preprocess")
+	}
+	overhead := len(full) - firstSize
+
+	var chunks []*InsertBuilder
+	start, size := 0, overhead
+	for i, row := range rows {
+		rs, err := rowSize(row)
+		if err != nil {
+			return nil, errors.Wrap(err, "[dbr] InsertBuilder.chunks.Preprocess")
+		}
+		if overhead+rs > maxPacket {
+			return nil, errors.NewNotValidf("[dbr] Row %d with %d bytes exceeds the maximum packet size of %d bytes", i, overhead+rs, maxPacket)
+		}
+		if i > start {
+			rs++ // comma
+		}
+		if size+rs > maxPacket {
+			chunks = append(chunks, b.chunk(rows[start:i]))
+			start, size = i, overhead
+			rs--
+		}
+		size += rs
+	}
+	return append(chunks, b.chunk(rows[start:])), nil
+}
+
+func (b *InsertBuilder) chunk(rows [][]interface{}) *InsertBuilder {
+	c := *b
+	c.Vals = rows
+	c.Recs = nil
+	return &c
+}
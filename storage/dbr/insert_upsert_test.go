@@ -0,0 +1,116 @@
+package dbr
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInsertUpsertToSql(t *testing.T) {
+	s := createFakeSession()
+
+	tests := []struct {
+		b        *InsertBuilder
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{
+			s.InsertInto("a").Columns("b", "c").Values(1, 2).Ignore(),
+			"INSERT IGNORE INTO a (`b`,`c`) VALUES (?,?)",
+			[]interface{}{1, 2},
+		},
+		{
+			s.InsertInto("a").Columns("b", "c").Values(1, 2).Replace(),
+			"REPLACE INTO a (`b`,`c`) VALUES (?,?)",
+			[]interface{}{1, 2},
+		},
+		{
+			s.InsertInto("a").Columns("b", "c").Values(1, 2).Values(3, 4).OnDuplicateKey(),
+			"INSERT INTO a (`b`,`c`) VALUES (?,?),(?,?) ON DUPLICATE KEY UPDATE `b`=VALUES(`b`), `c`=VALUES(`c`)",
+			[]interface{}{1, 2, 3, 4},
+		},
+		{
+			s.InsertInto("a").Columns("b", "c").Values(1, 2).OnDuplicateKey("c").OnDuplicateKeyExpr("d", "`d`+?", 5),
+			"INSERT INTO a (`b`,`c`) VALUES (?,?) ON DUPLICATE KEY UPDATE `c`=VALUES(`c`), `d`=`d`+?",
+			[]interface{}{1, 2, 5},
+		},
+		{
+			s.InsertInto("a").Map(map[string]interface{}{"c": 2, "b": 1}).OnDuplicateKey("c"),
+			"INSERT INTO a (`b`,`c`) VALUES (?,?) ON DUPLICATE KEY UPDATE `c`=VALUES(`c`)",
+			[]interface{}{1, 2},
+		},
+		{
+			s.InsertInto("core_config_data").Map(map[string]interface{}{"path": "a/b/c", "value": "x", "scope_id": 0}).OnDuplicateKey(),
+			"INSERT INTO core_config_data (`path`,`scope_id`,`value`) VALUES (?,?,?) ON DUPLICATE KEY UPDATE `path`=VALUES(`path`), `scope_id`=VALUES(`scope_id`), `value`=VALUES(`value`)",
+			[]interface{}{"a/b/c", 0, "x"},
+		},
+		{
+			s.InsertInto("a").OnDuplicateKey().Columns("b", "c").Values(1, 2),
+			"INSERT INTO a (`b`,`c`) VALUES (?,?) ON DUPLICATE KEY UPDATE `b`=VALUES(`b`), `c`=VALUES(`c`)",
+			[]interface{}{1, 2},
+		},
+		{
+			s.InsertInto("a").OnDuplicateKey().OnDuplicateKeyExpr("c", "`c`+VALUES(`c`)").Columns("b", "c").Values(1, 2),
+			"INSERT INTO a (`b`,`c`) VALUES (?,?) ON DUPLICATE KEY UPDATE `b`=VALUES(`b`), `c`=`c`+VALUES(`c`)",
+			[]interface{}{1, 2},
+		},
+		{
+			s.InsertInto("a").Columns("something_id", "user_id", "other").Record(someRecord{1, 88, false}).Ignore().OnDuplicateKey("other"),
+			"INSERT IGNORE INTO a (`something_id`,`user_id`,`other`) VALUES (?,?,?) ON DUPLICATE KEY UPDATE `other`=VALUES(`other`)",
+			[]interface{}{1, int64(88), false},
+		},
+	}
+	for i, test := range tests {
+		sql, args, err := test.b.ToSql()
+		assert.NoError(t, err, "Index %d", i)
+		assert.Exactly(t, test.wantSQL, sql, "Index %d", i)
+		assert.Exactly(t, test.wantArgs, args, "Index %d", i)
+	}
+
+	_, _, err := s.InsertInto("a").Columns("b").Values(1).Replace().OnDuplicateKey().ToSql()
+	assert.True(t, errors.IsNotValid(err), "%+v", err)
+}
+
+func TestInsertExecChunks(t *testing.T) {
+	s, mock := createMockSession(t)
+
+	// INSERT INTO a (`b`,`c`) VALUES  has 31 bytes and each row 7 bytes
+	mock.ExpectExec("INSERT INTO a \\(`b`,`c`\\) VALUES \\(1,'v'\\),\\(2,'w'\\)$").WillReturnResult(sqlmock.NewResult(11, 2))
+	mock.ExpectExec("INSERT INTO a \\(`b`,`c`\\) VALUES \\(3,'x'\\),\\(4,'y'\\)$").WillReturnResult(sqlmock.NewResult(13, 2))
+	mock.ExpectExec("INSERT INTO a \\(`b`,`c`\\) VALUES \\(5,'z'\\)$").WillReturnResult(sqlmock.NewResult(15, 1))
+
+	b := s.InsertInto("a").Columns("b", "c").Values(1, "v").Values(2, "w").Values(3, "x")
+	type bc struct {
+		B int
+		C string
+	}
+	b.Record(&bc{4, "y"}).Record(&bc{5, "z"})
+	results, err := b.ExecChunks(50)
+	assert.NoError(t, err)
+	assert.Len(t, results, 3)
+	for i, want := range []int64{11, 13, 15} {
+		id, err := results[i].LastInsertId()
+		assert.NoError(t, err)
+		assert.Exactly(t, want, id)
+	}
+	ra, err := results[2].RowsAffected()
+	assert.NoError(t, err)
+	assert.Exactly(t, int64(1), ra)
+
+	_, err = b.ExecChunks(37)
+	assert.True(t, errors.IsNotValid(err), "%+v", err)
+
+	mock.ExpectExec("INSERT INTO a \\(`b`,`c`\\) VALUES \\(1,'v'\\),\\(2,'w'\\) ON DUPLICATE KEY UPDATE `c`=VALUES\\(`c`\\)$").WillReturnResult(sqlmock.NewResult(0, 4))
+	results, err = s.InsertInto("a").Columns("b", "c").Values(1, "v").Values(2, "w").OnDuplicateKey("c").ExecChunks(0)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+
+	mock.ExpectQuery("SELECT @@max_allowed_packet").WillReturnRows(sqlmock.NewRows([]string{"@@max_allowed_packet"}).AddRow(4194304))
+	size, err := s.MaxAllowedPacket()
+	assert.NoError(t, err)
+	assert.Exactly(t, 4194304, size)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -2,12 +2,14 @@ package dbr
 
 import "fmt"
 
-type queryBuilder interface {
+// QueryBuilder gets implemented by all builders and renders the SQL with ?
+// placeholders and its arguments.
+type QueryBuilder interface {
 	ToSql() (string, []interface{}, error)
 	EventReceiver
 }
 
-func makeSql(b queryBuilder) (string, error) {
+func makeSql(b QueryBuilder) (string, error) {
 	sRaw, vals, err := b.ToSql()
 	if err != nil {
 		return "", b.EventErrKv("dbr.makeSql.tosql", err, nil)
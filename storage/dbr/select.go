@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/corestoreio/csfw/util/bufferpool"
+	"github.com/corestoreio/csfw/util/errors"
 )
 
 // SelectBuilder contains the clauses for a SELECT statement
@@ -26,9 +27,20 @@ type SelectBuilder struct {
 	LimitValid      bool
 	OffsetCount     uint64
 	OffsetValid     bool
+	// CTEs common table expressions which are getting written in front of
+	// the SELECT.
+	CTEs        []*cteFragment
+	IsRecursive bool
+	// Unions contains all SELECTs which are getting appended via UNION [ALL].
+	Unions []*unionFragment
+	// KeysetColumn, KeysetValue and KeysetValid are set by After for the
+	// keyset pagination.
+	KeysetColumn string
+	KeysetValue  interface{}
+	KeysetValid  bool
 }
 
-var _ queryBuilder = (*SelectBuilder)(nil)
+var _ QueryBuilder = (*SelectBuilder)(nil)
 
 // Select creates a new SelectBuilder that select that given columns
 func (sess *Session) Select(cols ...string) *SelectBuilder {
@@ -145,18 +157,41 @@ func (b *SelectBuilder) ToSql() (string, []interface{}, error) {
 		return b.RawFullSql, b.RawArguments, nil
 	}
 
+	var sql = bufferpool.Get()
+	defer bufferpool.Put(sql)
+
+	var args []interface{}
+
+	if err := b.writeCTEsToSql(sql, &args); err != nil {
+		return "", nil, errors.Wrap(err, "[dbr] SelectBuilder.ToSql.With")
+	}
+
+	inParens := len(b.Unions) > 0 && b.hasOrderOrLimit()
+	if inParens {
+		sql.WriteRune('(')
+	}
+	if err := b.writeSelectToSql(sql, &args); err != nil {
+		return "", nil, errors.Wrap(err, "[dbr] SelectBuilder.ToSql")
+	}
+	if inParens {
+		sql.WriteRune(')')
+	}
+
+	if err := b.writeUnionsToSql(sql, &args); err != nil {
+		return "", nil, errors.Wrap(err, "[dbr] SelectBuilder.ToSql.Union")
+	}
+	return sql.String(), args, nil
+}
+
+// writeSelectToSql writes the SELECT statement without the CTEs and UNIONs.
+func (b *SelectBuilder) writeSelectToSql(sql QueryWriter, args *[]interface{}) error {
 	if len(b.Columns) == 0 {
 		panic("no columns specified")
 	}
-	if len(b.FromTable.Expression) == 0 {
+	if len(b.FromTable.Expression) == 0 && b.FromTable.Select == nil {
 		panic("no table specified")
 	}
 
-	var sql = bufferpool.Get()
-	defer bufferpool.Put(sql)
-
-	var args []interface{}
-
 	sql.WriteString("SELECT ")
 
 	if b.IsDistinct {
@@ -180,22 +215,30 @@ func (b *SelectBuilder) ToSql() (string, []interface{}, error) {
 	}
 
 	sql.WriteString(" FROM ")
-	sql.WriteString(b.FromTable.QuoteAs())
+	if err := b.FromTable.writeQuoteAs(sql, args); err != nil {
+		return errors.Wrap(err, "[dbr] From")
+	}
 
 	if len(b.JoinFragments) > 0 {
 		for _, f := range b.JoinFragments {
 			sql.WriteRune(' ')
 			sql.WriteString(f.JoinType)
 			sql.WriteString(" JOIN ")
-			sql.WriteString(f.Table.QuoteAs())
+			if err := f.Table.writeQuoteAs(sql, args); err != nil {
+				return errors.Wrap(err, "[dbr] Join")
+			}
 			sql.WriteString(" ON ")
-			writeWhereFragmentsToSql(f.OnConditions, sql, &args)
+			if err := writeWhereFragmentsToSql(f.OnConditions, sql, args); err != nil {
+				return errors.Wrap(err, "[dbr] Join.On")
+			}
 		}
 	}
 
-	if len(b.WhereFragments) > 0 {
+	if wfs := b.keysetFragments(); len(wfs) > 0 {
 		sql.WriteString(" WHERE ")
-		writeWhereFragmentsToSql(b.WhereFragments, sql, &args)
+		if err := writeWhereFragmentsToSql(wfs, sql, args); err != nil {
+			return errors.Wrap(err, "[dbr] Where")
+		}
 	}
 
 	if len(b.GroupBys) > 0 {
@@ -210,12 +253,14 @@ func (b *SelectBuilder) ToSql() (string, []interface{}, error) {
 
 	if len(b.HavingFragments) > 0 {
 		sql.WriteString(" HAVING ")
-		writeWhereFragmentsToSql(b.HavingFragments, sql, &args)
+		if err := writeWhereFragmentsToSql(b.HavingFragments, sql, args); err != nil {
+			return errors.Wrap(err, "[dbr] Having")
+		}
 	}
 
-	if len(b.OrderBys) > 0 {
+	if orderBys := b.keysetOrderBys(); len(orderBys) > 0 {
 		sql.WriteString(" ORDER BY ")
-		for i, s := range b.OrderBys {
+		for i, s := range orderBys {
 			if i > 0 {
 				sql.WriteString(", ")
 			}
@@ -232,5 +277,5 @@ func (b *SelectBuilder) ToSql() (string, []interface{}, error) {
 		sql.WriteString(" OFFSET ")
 		fmt.Fprint(sql, b.OffsetCount)
 	}
-	return sql.String(), args, nil
+	return nil
 }
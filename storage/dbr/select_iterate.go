@@ -0,0 +1,231 @@
+package dbr
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/corestoreio/csfw/util/errors"
+)
+
+// Rows streams the result set of a SelectBuilder. Contrary to LoadStructs
+// the rows are not getting materialized into a slice. Rows must be closed
+// after usage. Rows embeds *sql.Rows so Next, Scan, Err and Close are
+// available.
+type Rows struct {
+	*sql.Rows
+	sess    *Session
+	fullSql string
+	columns []string
+	// recordType, fieldMap and holder are getting calculated once for the
+	// first ScanStruct call and reused for all other rows.
+	recordType reflect.Type
+	fieldMap   [][]int
+	holder     []interface{}
+}
+
+// Rows executes the SelectBuilder and returns the rows for streaming.
+func (b *SelectBuilder) Rows() (*Rows, error) {
+	return b.RowsContext(context.Background())
+}
+
+// RowsContext same as Rows but the query gets canceled when the context
+// expires.
+func (b *SelectBuilder) RowsContext(ctx context.Context) (*Rows, error) {
+	tSQL, tArg, err := b.ToSql()
+	if err != nil {
+		return nil, b.EventErr("dbr.select.rows.tosql", err)
+	}
+
+	fullSql, err := Preprocess(tSQL, tArg)
+	if err != nil {
+		return nil, b.EventErr("dbr.select.rows.interpolate", err)
+	}
+
+	startTime := time.Now()
+	defer func() { b.TimingKv("dbr.select", time.Since(startTime).Nanoseconds(), kvs{"sql": fullSql}) }()
+
+	rows, err := b.runner.QueryContext(ctx, fullSql)
+	if err != nil {
+		return nil, b.EventErrKv("dbr.select.rows.query", err, kvs{"sql": fullSql})
+	}
+	columns, err := rows.Columns()
+	if err != nil {
+		_ = rows.Close()
+		return nil, b.EventErrKv("dbr.select.rows.Columns", err, kvs{"sql": fullSql})
+	}
+	return &Rows{
+		Rows:    rows,
+		sess:    b.Session,
+		fullSql: fullSql,
+		columns: columns,
+	}, nil
+}
+
+// ScanStruct scans the current row into dest. dest must be a pointer to a
+// struct. The same struct can be reused for all rows to keep the memory
+// constant; fields which are not part of the result set are left untouched.
+func (r *Rows) ScanStruct(dest interface{}) error {
+	valueOfDest := reflect.ValueOf(dest)
+	indirectOfDest := reflect.Indirect(valueOfDest)
+	if valueOfDest.Kind() != reflect.Ptr || indirectOfDest.Kind() != reflect.Struct {
+		panic("you need to pass in the address of a struct")
+	}
+
+	if recordType := indirectOfDest.Type(); r.recordType != recordType {
+		fieldMap, err := r.sess.calculateFieldMap(recordType, r.columns, false)
+		if err != nil {
+			return r.sess.EventErrKv("dbr.select.rows.calculateFieldMap", err, kvs{"sql": r.fullSql})
+		}
+		r.recordType = recordType
+		r.fieldMap = fieldMap
+		r.holder = make([]interface{}, len(fieldMap))
+	}
+
+	scannable, err := r.sess.prepareHolderFor(indirectOfDest, r.fieldMap, r.holder)
+	if err != nil {
+		return r.sess.EventErrKv("dbr.select.rows.holderFor", err, kvs{"sql": r.fullSql})
+	}
+	if err := r.Scan(scannable...); err != nil {
+		return r.sess.EventErrKv("dbr.select.rows.scan", err, kvs{"sql": r.fullSql})
+	}
+	return nil
+}
+
+// value returns a copy of the field value of the column from the last
+// ScanStruct call.
+func (r *Rows) value(column string) (interface{}, error) {
+	for i, c := range r.columns {
+		if c == column && r.fieldMap != nil && r.fieldMap[i] != nil {
+			return reflect.Indirect(reflect.ValueOf(r.holder[i])).Interface(), nil
+		}
+	}
+	return nil, errors.NewNotFoundf("[dbr] Column %q not found in the result set or the struct", column)
+}
+
+// Iterate executes the SelectBuilder and scans each row into the same
+// record. record must be a pointer to a struct. After each row the function
+// fn gets called with the record. An error returned by fn stops the
+// iteration and gets returned unchanged. Returns the number of processed
+// rows.
+//		p := new(Product)
+//		n, err := sel.Iterate(p, func(record interface{}) error {
+//			return export(p)
+//		})
+func (b *SelectBuilder) Iterate(record interface{}, fn func(record interface{}) error) (int, error) {
+	return b.IterateContext(context.Background(), record, fn)
+}
+
+// IterateContext same as Iterate but the query gets canceled when the
+// context expires.
+func (b *SelectBuilder) IterateContext(ctx context.Context, record interface{}, fn func(record interface{}) error) (int, error) {
+	n, _, err := b.iterate(ctx, "", record, fn)
+	return n, err
+}
+
+// iterate loops over all rows and returns additionally the value of the
+// column of the last row, if column has been provided.
+func (b *SelectBuilder) iterate(ctx context.Context, column string, record interface{}, fn func(record interface{}) error) (n int, last interface{}, err error) {
+	rows, err := b.RowsContext(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := rows.ScanStruct(record); err != nil {
+			return n, nil, err
+		}
+		if err := fn(record); err != nil {
+			return n, nil, err
+		}
+		n++
+	}
+	if err := rows.Err(); err != nil {
+		return n, nil, b.EventErrKv("dbr.select.iterate.rows_err", err, kvs{"sql": rows.fullSql})
+	}
+	if column != "" && n > 0 {
+		if last, err = rows.value(column); err != nil {
+			return n, nil, err
+		}
+	}
+	return n, last, nil
+}
+
+// After enables keyset pagination. Only rows whose column is greater than
+// the value are getting selected and the rows are getting sorted by the
+// column in front of all other ORDER BY columns. The column should be the
+// primary key or at least unique. Calling After again replaces the previous
+// value.
+//		SELECT ... WHERE ... AND (`entity_id` > ?) ORDER BY `entity_id` ASC LIMIT 1000
+func (b *SelectBuilder) After(column string, value interface{}) *SelectBuilder {
+	b.KeysetColumn = column
+	b.KeysetValue = value
+	b.KeysetValid = true
+	return b
+}
+
+// IterateKeyset walks the whole result set in pages of pageSize rows with
+// keyset pagination on column. Each page is a new query which starts after
+// the column value of the last row of the previous page, so long running
+// batch jobs neither hold a huge result set nor suffer from slow OFFSETs.
+// The column must be part of the result set and of the record. A previous
+// call to After defines the start value. Returns the number of processed
+// rows.
+func (b *SelectBuilder) IterateKeyset(column string, pageSize uint64, record interface{}, fn func(record interface{}) error) (int, error) {
+	return b.IterateKeysetContext(context.Background(), column, pageSize, record, fn)
+}
+
+// IterateKeysetContext same as IterateKeyset but all queries are getting
+// canceled when the context expires.
+func (b *SelectBuilder) IterateKeysetContext(ctx context.Context, column string, pageSize uint64, record interface{}, fn func(record interface{}) error) (int, error) {
+	if pageSize == 0 {
+		return 0, errors.NewNotValidf("[dbr] IterateKeyset: pageSize must be greater than zero")
+	}
+	if b.KeysetColumn != column {
+		b.KeysetColumn = column
+		b.KeysetValid = false
+	}
+	b.Limit(pageSize)
+
+	// the result set contains the column name without the table prefix
+	resultColumn := Quoter.unQuote(column)
+	if pos := strings.LastIndexByte(resultColumn, '.'); pos >= 0 {
+		resultColumn = resultColumn[pos+1:]
+	}
+
+	var total int
+	for {
+		n, last, err := b.iterate(ctx, resultColumn, record, fn)
+		total += n
+		if err != nil {
+			return total, errors.Wrapf(err, "[dbr] IterateKeyset after %v", b.KeysetValue)
+		}
+		if uint64(n) < pageSize {
+			return total, nil
+		}
+		b.After(column, last)
+	}
+}
+
+// keysetFragments returns the WHERE fragments including the keyset
+// condition.
+func (b *SelectBuilder) keysetFragments() []*whereFragment {
+	if b.KeysetColumn == "" || !b.KeysetValid {
+		return b.WhereFragments
+	}
+	wfs := make([]*whereFragment, len(b.WhereFragments), len(b.WhereFragments)+1)
+	copy(wfs, b.WhereFragments)
+	return append(wfs, newWhereFragments(Gt(b.KeysetColumn, b.KeysetValue))...)
+}
+
+// keysetOrderBys returns the ORDER BY columns with the keyset column in
+// front.
+func (b *SelectBuilder) keysetOrderBys() []string {
+	if b.KeysetColumn == "" {
+		return b.OrderBys
+	}
+	return append([]string{Quoter.QuoteAs(b.KeysetColumn) + " ASC"}, b.OrderBys...)
+}
@@ -0,0 +1,122 @@
+package dbr
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type iterProduct struct {
+	EntityID int64  `db:"entity_id"`
+	Sku      string `db:"sku"`
+}
+
+func TestSelectAfter(t *testing.T) {
+	s := createFakeSession()
+	sql, args, err := s.Select("entity_id", "sku").From("catalog_product_entity", "e").
+		Where(Equal("type_id", "simple")).OrderBy("sku").
+		After("e.entity_id", 42).Limit(10).ToSql()
+	assert.NoError(t, err)
+	assert.Exactly(t, "SELECT entity_id, sku FROM `catalog_product_entity` AS `e` WHERE (`type_id` = ?) AND (`e`.`entity_id` > ?) ORDER BY `e`.`entity_id` ASC, sku LIMIT 10", sql)
+	assert.Exactly(t, []interface{}{"simple", 42}, args)
+}
+
+func TestSelectRowsScanStruct(t *testing.T) {
+	s, mock := createMockSession(t)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT entity_id, sku FROM `catalog_product_entity`")).
+		WillReturnRows(sqlmock.NewRows([]string{"entity_id", "sku", "unknown"}).
+			AddRow(1, "A", "x").AddRow(2, "B", "y"))
+
+	rows, err := s.Select("entity_id", "sku").From("catalog_product_entity").Rows()
+	assert.NoError(t, err)
+	p := new(iterProduct)
+	var skus []string
+	for rows.Next() {
+		assert.NoError(t, rows.ScanStruct(p))
+		skus = append(skus, p.Sku)
+	}
+	assert.NoError(t, rows.Err())
+	assert.NoError(t, rows.Close())
+	assert.Exactly(t, []string{"A", "B"}, skus)
+	assert.Exactly(t, int64(2), p.EntityID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSelectIterate(t *testing.T) {
+	s, mock := createMockSession(t)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT entity_id, sku FROM `catalog_product_entity`")).
+		WillReturnRows(sqlmock.NewRows([]string{"entity_id", "sku"}).
+			AddRow(1, "A").AddRow(2, "B").AddRow(3, "C"))
+
+	var ids []int64
+	p := new(iterProduct)
+	n, err := s.Select("entity_id", "sku").From("catalog_product_entity").Iterate(p, func(record interface{}) error {
+		assert.True(t, record == p, "record must be reused")
+		ids = append(ids, p.EntityID)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Exactly(t, 3, n)
+	assert.Exactly(t, []int64{1, 2, 3}, ids)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSelectIterateStop(t *testing.T) {
+	s, mock := createMockSession(t)
+	mock.ExpectQuery("SELECT (.+) FROM `catalog_product_entity`").
+		WillReturnRows(sqlmock.NewRows([]string{"entity_id", "sku"}).
+			AddRow(1, "A").AddRow(2, "B").AddRow(3, "C"))
+
+	errStop := errors.New("stop")
+	n, err := s.Select("entity_id", "sku").From("catalog_product_entity").Iterate(new(iterProduct), func(record interface{}) error {
+		if record.(*iterProduct).EntityID == 2 {
+			return errStop
+		}
+		return nil
+	})
+	assert.True(t, err == errStop, "%+v", err)
+	assert.Exactly(t, 1, n)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSelectIterateKeyset(t *testing.T) {
+	s, mock := createMockSession(t)
+	cols := []string{"entity_id", "sku"}
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT entity_id, sku FROM `catalog_product_entity` AS `e` WHERE (`type_id` = 'simple') ORDER BY `e`.`entity_id` ASC LIMIT 2")).
+		WillReturnRows(sqlmock.NewRows(cols).AddRow(3, "A").AddRow(7, "B"))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT entity_id, sku FROM `catalog_product_entity` AS `e` WHERE (`type_id` = 'simple') AND (`e`.`entity_id` > 7) ORDER BY `e`.`entity_id` ASC LIMIT 2")).
+		WillReturnRows(sqlmock.NewRows(cols).AddRow(8, "C").AddRow(11, "D"))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT entity_id, sku FROM `catalog_product_entity` AS `e` WHERE (`type_id` = 'simple') AND (`e`.`entity_id` > 11) ORDER BY `e`.`entity_id` ASC LIMIT 2")).
+		WillReturnRows(sqlmock.NewRows(cols).AddRow(12, "E"))
+
+	var skus []string
+	p := new(iterProduct)
+	n, err := s.Select("entity_id", "sku").From("catalog_product_entity", "e").
+		Where(Equal("type_id", "simple")).
+		IterateKeyset("e.entity_id", 2, p, func(_ interface{}) error {
+			skus = append(skus, p.Sku)
+			return nil
+		})
+	assert.NoError(t, err)
+	assert.Exactly(t, 5, n)
+	assert.Exactly(t, []string{"A", "B", "C", "D", "E"}, skus)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSelectIterateKeysetMissingColumn(t *testing.T) {
+	s, mock := createMockSession(t)
+	mock.ExpectQuery("SELECT sku FROM `catalog_product_entity`").
+		WillReturnRows(sqlmock.NewRows([]string{"sku"}).AddRow("A"))
+
+	_, err := s.Select("sku").From("catalog_product_entity").
+		IterateKeyset("entity_id", 1, new(iterProduct), func(_ interface{}) error { return nil })
+	assert.True(t, errors.IsNotFound(err), "%+v", err)
+
+	_, err = s.Select("sku").From("catalog_product_entity").
+		IterateKeyset("entity_id", 0, new(iterProduct), func(_ interface{}) error { return nil })
+	assert.True(t, errors.IsNotValid(err), "%+v", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -23,10 +23,10 @@ func JoinColumns(columns ...string) []string {
 	return columns
 }
 
-func (b *SelectBuilder) join(j string, t, c []string, on ...ConditionArg) *SelectBuilder {
+func (b *SelectBuilder) join(j string, t alias, c []string, on ...ConditionArg) *SelectBuilder {
 	b.JoinFragments = append(b.JoinFragments, &joinFragment{
 		JoinType:     j,
-		Table:        newAlias(t...),
+		Table:        t,
 		Columns:      c,
 		OnConditions: newWhereFragments(on...),
 	})
@@ -35,15 +35,32 @@ func (b *SelectBuilder) join(j string, t, c []string, on ...ConditionArg) *Selec
 
 // Join creates a join construct with the onConditions glued together with AND
 func (b *SelectBuilder) Join(table, columns []string, onConditions ...ConditionArg) *SelectBuilder {
-	return b.join("INNER", table, columns, onConditions...)
+	return b.join("INNER", newAlias(table...), columns, onConditions...)
 }
 
 // LeftJoin creates a join construct with the onConditions glued together with AND
 func (b *SelectBuilder) LeftJoin(table, columns []string, onConditions ...ConditionArg) *SelectBuilder {
-	return b.join("LEFT", table, columns, onConditions...)
+	return b.join("LEFT", newAlias(table...), columns, onConditions...)
 }
 
 // LeftJoin creates a join construct with the onConditions glued together with AND
 func (b *SelectBuilder) RightJoin(table, columns []string, onConditions ...ConditionArg) *SelectBuilder {
-	return b.join("RIGHT", table, columns, onConditions...)
+	return b.join("RIGHT", newAlias(table...), columns, onConditions...)
+}
+
+// JoinSub joins the derived table of the sub select with the alias. The
+// arguments of the sub select are getting placed before the arguments of the
+// onConditions.
+func (b *SelectBuilder) JoinSub(sub *SelectBuilder, as string, columns []string, onConditions ...ConditionArg) *SelectBuilder {
+	return b.join("INNER", newSubAlias(sub, as), columns, onConditions...)
+}
+
+// LeftJoinSub same as JoinSub but creates a LEFT JOIN.
+func (b *SelectBuilder) LeftJoinSub(sub *SelectBuilder, as string, columns []string, onConditions ...ConditionArg) *SelectBuilder {
+	return b.join("LEFT", newSubAlias(sub, as), columns, onConditions...)
+}
+
+// RightJoinSub same as JoinSub but creates a RIGHT JOIN.
+func (b *SelectBuilder) RightJoinSub(sub *SelectBuilder, as string, columns []string, onConditions ...ConditionArg) *SelectBuilder {
+	return b.join("RIGHT", newSubAlias(sub, as), columns, onConditions...)
 }
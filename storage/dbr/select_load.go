@@ -1,6 +1,8 @@
 package dbr
 
 import (
+	"context"
+	"database/sql"
 	"reflect"
 	"time"
 )
@@ -16,10 +18,44 @@ import (
 // dest must be a pointer to a slice of pointers to structs
 // Returns the number of items found (which is not necessarily the # of items set)
 func (b *SelectBuilder) LoadStructs(dest interface{}) (int, error) {
+	return b.LoadStructsContext(context.Background(), dest)
+}
+
+// LoadStructsContext same as LoadStructs but the query gets canceled when the context
+// expires.
+func (b *SelectBuilder) LoadStructsContext(ctx context.Context, dest interface{}) (int, error) {
+	valueOfDest, recordType := structSliceOf(dest)
+
 	//
-	// Validate the dest, and extract the reflection values we need.
+	// Get full SQL
 	//
+	tSQL, tArg, err := b.ToSql()
+	if err != nil {
+		return 0, b.EventErr("dbr.select.load_structs.tosql", err)
+	}
 
+	fullSql, err := Preprocess(tSQL, tArg)
+	if err != nil {
+		return 0, b.EventErr("dbr.select.load_all.interpolate", err)
+	}
+
+	// Start the timer:
+	startTime := time.Now()
+	defer func() { b.TimingKv("dbr.select", time.Since(startTime).Nanoseconds(), kvs{"sql": fullSql}) }()
+
+	// Run the query:
+	rows, err := b.runner.QueryContext(ctx, fullSql)
+	if err != nil {
+		return 0, b.EventErrKv("dbr.select.load_all.query", err, kvs{"sql": fullSql})
+	}
+	defer rows.Close()
+
+	return b.loadStructs(rows, valueOfDest, recordType, fullSql)
+}
+
+// structSliceOf validates the dest, and extracts the reflection values we
+// need. dest must be a pointer to a slice of pointers to structs.
+func structSliceOf(dest interface{}) (reflect.Value, reflect.Type) {
 	// This must be a pointer to a slice
 	valueOfDest := reflect.ValueOf(dest)
 	kindOfDest := valueOfDest.Kind()
@@ -46,43 +82,24 @@ func (b *SelectBuilder) LoadStructs(dest interface{}) (int, error) {
 	if recordType.Kind() != reflect.Struct {
 		panic("Elements need to be pointers to structures")
 	}
+	return valueOfDest, recordType
+}
 
-	//
-	// Get full SQL
-	//
-	tSQL, tArg, err := b.ToSql()
-	if err != nil {
-		return 0, b.EventErr("dbr.select.load_structs.tosql", err)
-	}
-
-	fullSql, err := Preprocess(tSQL, tArg)
-	if err != nil {
-		return 0, b.EventErr("dbr.select.load_all.interpolate", err)
-	}
-
+// loadStructs scans all rows into the slice valueOfDest of pointers to
+// recordType.
+func (sess *Session) loadStructs(rows *sql.Rows, valueOfDest reflect.Value, recordType reflect.Type, fullSql string) (int, error) {
 	numberOfRowsReturned := 0
 
-	// Start the timer:
-	startTime := time.Now()
-	defer func() { b.TimingKv("dbr.select", time.Since(startTime).Nanoseconds(), kvs{"sql": fullSql}) }()
-
-	// Run the query:
-	rows, err := b.runner.Query(fullSql)
-	if err != nil {
-		return 0, b.EventErrKv("dbr.select.load_all.query", err, kvs{"sql": fullSql})
-	}
-	defer rows.Close()
-
 	// Get the columns returned
 	columns, err := rows.Columns()
 	if err != nil {
-		return numberOfRowsReturned, b.EventErrKv("dbr.select.load_one.rows.Columns", err, kvs{"sql": fullSql})
+		return numberOfRowsReturned, sess.EventErrKv("dbr.select.load_one.rows.Columns", err, kvs{"sql": fullSql})
 	}
 
 	// Create a map of this result set to the struct fields
-	fieldMap, err := b.calculateFieldMap(recordType, columns, false)
+	fieldMap, err := sess.calculateFieldMap(recordType, columns, false)
 	if err != nil {
-		return numberOfRowsReturned, b.EventErrKv("dbr.select.load_all.calculateFieldMap", err, kvs{"sql": fullSql})
+		return numberOfRowsReturned, sess.EventErrKv("dbr.select.load_all.calculateFieldMap", err, kvs{"sql": fullSql})
 	}
 
 	// Build a 'holder', which is an []interface{}. Each value will be the set to address of the field corresponding to our newly made records:
@@ -96,15 +113,15 @@ func (b *SelectBuilder) LoadStructs(dest interface{}) (int, error) {
 		newRecord := reflect.Indirect(pointerToNewRecord)
 
 		// Prepare the holder for this record
-		scannable, err := b.prepareHolderFor(newRecord, fieldMap, holder)
+		scannable, err := sess.prepareHolderFor(newRecord, fieldMap, holder)
 		if err != nil {
-			return numberOfRowsReturned, b.EventErrKv("dbr.select.load_all.holderFor", err, kvs{"sql": fullSql})
+			return numberOfRowsReturned, sess.EventErrKv("dbr.select.load_all.holderFor", err, kvs{"sql": fullSql})
 		}
 
 		// Load up our new structure with the row's values
 		err = rows.Scan(scannable...)
 		if err != nil {
-			return numberOfRowsReturned, b.EventErrKv("dbr.select.load_all.scan", err, kvs{"sql": fullSql})
+			return numberOfRowsReturned, sess.EventErrKv("dbr.select.load_all.scan", err, kvs{"sql": fullSql})
 		}
 
 		// Append our new record to the slice:
@@ -116,7 +133,7 @@ func (b *SelectBuilder) LoadStructs(dest interface{}) (int, error) {
 
 	// Check for errors at the end. Supposedly these are error that can happen during iteration.
 	if err = rows.Err(); err != nil {
-		return numberOfRowsReturned, b.EventErrKv("dbr.select.load_all.rows_err", err, kvs{"sql": fullSql})
+		return numberOfRowsReturned, sess.EventErrKv("dbr.select.load_all.rows_err", err, kvs{"sql": fullSql})
 	}
 
 	return numberOfRowsReturned, nil
@@ -126,6 +143,12 @@ func (b *SelectBuilder) LoadStructs(dest interface{}) (int, error) {
 // dest must be a pointer to a struct
 // Returns ErrNotFound if nothing was found
 func (b *SelectBuilder) LoadStruct(dest interface{}) error {
+	return b.LoadStructContext(context.Background(), dest)
+}
+
+// LoadStructContext same as LoadStruct but the query gets canceled when the context
+// expires.
+func (b *SelectBuilder) LoadStructContext(ctx context.Context, dest interface{}) error {
 	//
 	// Validate the dest, and extract the reflection values we need.
 	//
@@ -157,7 +180,7 @@ func (b *SelectBuilder) LoadStruct(dest interface{}) error {
 	defer func() { b.TimingKv("dbr.select", time.Since(startTime).Nanoseconds(), kvs{"sql": fullSql}) }()
 
 	// Run the query:
-	rows, err := b.runner.Query(fullSql)
+	rows, err := b.runner.QueryContext(ctx, fullSql)
 	if err != nil {
 		return b.EventErrKv("dbr.select.load_one.query", err, kvs{"sql": fullSql})
 	}
@@ -203,6 +226,12 @@ func (b *SelectBuilder) LoadStruct(dest interface{}) error {
 // LoadValues executes the SelectBuilder and loads the resulting data into a slice of primitive values
 // Returns ErrNotFound if no value was found, and it was therefore not set.
 func (b *SelectBuilder) LoadValues(dest interface{}) (int, error) {
+	return b.LoadValuesContext(context.Background(), dest)
+}
+
+// LoadValuesContext same as LoadValues but the query gets canceled when the context
+// expires.
+func (b *SelectBuilder) LoadValuesContext(ctx context.Context, dest interface{}) (int, error) {
 	// Validate the dest and reflection values we need
 
 	// This must be a pointer to a slice
@@ -248,7 +277,7 @@ func (b *SelectBuilder) LoadValues(dest interface{}) (int, error) {
 	defer func() { b.TimingKv("dbr.select", time.Since(startTime).Nanoseconds(), kvs{"sql": fullSql}) }()
 
 	// Run the query:
-	rows, err := b.runner.Query(fullSql)
+	rows, err := b.runner.QueryContext(ctx, fullSql)
 	if err != nil {
 		return numberOfRowsReturned, b.EventErrKv("dbr.select.load_all_values.query", err, kvs{"sql": fullSql})
 	}
@@ -282,6 +311,12 @@ func (b *SelectBuilder) LoadValues(dest interface{}) (int, error) {
 // LoadValue executes the SelectBuilder and loads the resulting data into a primitive value
 // Returns ErrNotFound if no value was found, and it was therefore not set.
 func (b *SelectBuilder) LoadValue(dest interface{}) error {
+	return b.LoadValueContext(context.Background(), dest)
+}
+
+// LoadValueContext same as LoadValue but the query gets canceled when the context
+// expires.
+func (b *SelectBuilder) LoadValueContext(ctx context.Context, dest interface{}) error {
 	// Validate the dest
 	valueOfDest := reflect.ValueOf(dest)
 	kindOfDest := valueOfDest.Kind()
@@ -308,7 +343,7 @@ func (b *SelectBuilder) LoadValue(dest interface{}) error {
 	defer func() { b.TimingKv("dbr.select", time.Since(startTime).Nanoseconds(), kvs{"sql": fullSql}) }()
 
 	// Run the query:
-	rows, err := b.runner.Query(fullSql)
+	rows, err := b.runner.QueryContext(ctx, fullSql)
 	if err != nil {
 		return b.EventErrKv("dbr.select.load_value.query", err, kvs{"sql": fullSql})
 	}
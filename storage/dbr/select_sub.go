@@ -0,0 +1,171 @@
+package dbr
+
+import "github.com/corestoreio/csfw/util/errors"
+
+type (
+	cteFragment struct {
+		// Name of the common table expression
+		Name string
+		// Columns optional column names of the CTE
+		Columns []string
+		Select  *SelectBuilder
+	}
+	unionFragment struct {
+		// IsAll writes UNION ALL instead of UNION
+		IsAll  bool
+		Select *SelectBuilder
+	}
+)
+
+func newSubAlias(sub *SelectBuilder, as string) alias {
+	return alias{
+		Alias:  as,
+		Select: sub,
+	}
+}
+
+// writeQuoteAs writes the quoted table name and its alias. A derived table
+// gets written as parenthesized sub select with its arguments.
+func (t alias) writeQuoteAs(w QueryWriter, args *[]interface{}) error {
+	if t.Select == nil {
+		_, _ = w.WriteString(t.QuoteAs())
+		return nil
+	}
+	if t.Alias == "" {
+		return errors.NewNotValidf("[dbr] A derived table requires an alias")
+	}
+	if err := writeSubSelect(w, args, t.Select); err != nil {
+		return err
+	}
+	_, _ = w.WriteString(" AS ")
+	Quoter.writeQuotedColumn(Quoter.unQuote(t.Alias), w)
+	return nil
+}
+
+// writeSubSelect writes the sub select in parenthesis and appends its
+// arguments.
+func writeSubSelect(w QueryWriter, args *[]interface{}, sub *SelectBuilder) error {
+	sSQL, sArgs, err := sub.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "[dbr] Sub Select")
+	}
+	_, _ = w.WriteRune('(')
+	_, _ = w.WriteString(sSQL)
+	_, _ = w.WriteRune(')')
+	*args = append(*args, sArgs...)
+	return nil
+}
+
+// FromSub sets a derived table as the FROM clause. MySQL requires the alias.
+//		SELECT ... FROM (SELECT ...) AS `alias`
+func (b *SelectBuilder) FromSub(sub *SelectBuilder, as string) *SelectBuilder {
+	b.FromTable = newSubAlias(sub, as)
+	return b
+}
+
+// Union appends the selects via UNION which removes duplicate rows. To sort
+// or limit the whole result set wrap the builder with FromSub into a new
+// SelectBuilder.
+func (b *SelectBuilder) Union(selects ...*SelectBuilder) *SelectBuilder {
+	return b.union(false, selects...)
+}
+
+// UnionAll appends the selects via UNION ALL.
+func (b *SelectBuilder) UnionAll(selects ...*SelectBuilder) *SelectBuilder {
+	return b.union(true, selects...)
+}
+
+func (b *SelectBuilder) union(isAll bool, selects ...*SelectBuilder) *SelectBuilder {
+	for _, s := range selects {
+		b.Unions = append(b.Unions, &unionFragment{
+			IsAll:  isAll,
+			Select: s,
+		})
+	}
+	return b
+}
+
+// With adds a common table expression in front of the SELECT. The columns are
+// optional.
+//		WITH `name` (`col1`, `col2`) AS (SELECT ...) SELECT ...
+func (b *SelectBuilder) With(name string, sub *SelectBuilder, columns ...string) *SelectBuilder {
+	b.CTEs = append(b.CTEs, &cteFragment{
+		Name:    name,
+		Columns: columns,
+		Select:  sub,
+	})
+	return b
+}
+
+// WithRecursive same as With but writes WITH RECURSIVE. The sub select
+// usually contains an UNION ALL whose second part references the name of
+// the CTE, for example to load a category tree.
+func (b *SelectBuilder) WithRecursive(name string, sub *SelectBuilder, columns ...string) *SelectBuilder {
+	b.IsRecursive = true
+	return b.With(name, sub, columns...)
+}
+
+// hasOrderOrLimit reports if the SELECT must be parenthesized within an
+// UNION.
+func (b *SelectBuilder) hasOrderOrLimit() bool {
+	return b.RawFullSql == "" && (len(b.OrderBys) > 0 || b.KeysetColumn != "" || b.LimitValid || b.OffsetValid)
+}
+
+func (b *SelectBuilder) writeCTEsToSql(sql QueryWriter, args *[]interface{}) error {
+	if len(b.CTEs) == 0 {
+		return nil
+	}
+	sql.WriteString("WITH ")
+	if b.IsRecursive {
+		sql.WriteString("RECURSIVE ")
+	}
+	for i, c := range b.CTEs {
+		if c.Name == "" || c.Select == nil {
+			return errors.NewNotValidf("[dbr] CTE at index %d requires a name and a SELECT", i)
+		}
+		if i > 0 {
+			sql.WriteString(", ")
+		}
+		Quoter.writeQuotedColumn(c.Name, sql)
+		if len(c.Columns) > 0 {
+			sql.WriteString(" (")
+			for j, col := range c.Columns {
+				if j > 0 {
+					sql.WriteString(", ")
+				}
+				Quoter.writeQuotedColumn(col, sql)
+			}
+			sql.WriteRune(')')
+		}
+		sql.WriteString(" AS ")
+		if err := writeSubSelect(sql, args, c.Select); err != nil {
+			return errors.Wrapf(err, "[dbr] CTE %q", c.Name)
+		}
+	}
+	sql.WriteRune(' ')
+	return nil
+}
+
+func (b *SelectBuilder) writeUnionsToSql(sql QueryWriter, args *[]interface{}) error {
+	for _, u := range b.Unions {
+		if u.IsAll {
+			sql.WriteString(" UNION ALL ")
+		} else {
+			sql.WriteString(" UNION ")
+		}
+		s := u.Select
+		if s.hasOrderOrLimit() || len(s.Unions) > 0 || len(s.CTEs) > 0 {
+			if err := writeSubSelect(sql, args, s); err != nil {
+				return err
+			}
+			continue
+		}
+		uSQL, uArgs, err := s.ToSql()
+		if err != nil {
+			return errors.Wrap(err, "[dbr] Union")
+		}
+		sql.WriteString(uSQL)
+		*args = append(*args, uArgs...)
+	}
+	return nil
+}
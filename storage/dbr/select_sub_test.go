@@ -0,0 +1,130 @@
+package dbr
+
+import (
+	"testing"
+
+	"github.com/corestoreio/csfw/util/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectFromSub(t *testing.T) {
+	s := createFakeSession()
+	sub := s.Select("entity_id", "COUNT(*) AS cnt").From("catalog_product_entity_int").
+		Where(ConditionRaw("store_id = ?", 1)).GroupBy("entity_id")
+
+	sql, args, err := s.Select("t.entity_id").FromSub(sub, "t").
+		Where(ConditionRaw("t.cnt > ?", 2)).ToSql()
+	assert.NoError(t, err)
+	assert.Exactly(t, "SELECT t.entity_id FROM (SELECT entity_id, COUNT(*) AS cnt FROM `catalog_product_entity_int` WHERE (store_id = ?) GROUP BY entity_id) AS `t` WHERE (t.cnt > ?)", sql)
+	assert.Exactly(t, []interface{}{1, 2}, args)
+
+	_, _, err = s.Select("a").FromSub(sub, "").ToSql()
+	assert.True(t, errors.IsNotValid(err), "%+v", err)
+}
+
+func TestSelectConditionSub(t *testing.T) {
+	s := createFakeSession()
+	sub := s.Select("entity_id").From("catalog_category_product").
+		Where(ConditionRaw("category_id = ?", 33))
+
+	sql, args, err := s.Select("a", "b").From("catalog_product_entity").
+		Where(ConditionRaw("type_id = ?", "simple")).
+		Where(ConditionSub("entity_id IN", sub)).
+		Where(ConditionRaw("has_options = ?", 0)).
+		Having(ConditionSub("NOT EXISTS", s.Select("1").From("b").Where(ConditionRaw("b.x = ?", "y")))).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Exactly(t, "SELECT a, b FROM `catalog_product_entity` WHERE (type_id = ?) AND (entity_id IN (SELECT entity_id FROM `catalog_category_product` WHERE (category_id = ?))) AND (has_options = ?) HAVING (NOT EXISTS (SELECT 1 FROM `b` WHERE (b.x = ?)))", sql)
+	assert.Exactly(t, []interface{}{"simple", 33, 0, "y"}, args)
+}
+
+func TestSelectJoinSub(t *testing.T) {
+	s := createFakeSession()
+	sub := s.Select("entity_id", "value").From("catalog_product_entity_varchar").
+		Where(ConditionRaw("attribute_id = ?", 71))
+
+	sql, args, err := s.Select("e.entity_id").
+		From("catalog_product_entity", "e").
+		LeftJoinSub(sub, "name", JoinColumns("name.value"), ConditionRaw("name.entity_id = e.entity_id AND name.value <> ?", "")).
+		Where(ConditionRaw("e.entity_id > ?", 5)).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Exactly(t, "SELECT e.entity_id, name.value FROM `catalog_product_entity` AS `e` LEFT JOIN (SELECT entity_id, value FROM `catalog_product_entity_varchar` WHERE (attribute_id = ?)) AS `name` ON (name.entity_id = e.entity_id AND name.value <> ?) WHERE (e.entity_id > ?)", sql)
+	assert.Exactly(t, []interface{}{71, "", 5}, args)
+}
+
+func TestSelectUnion(t *testing.T) {
+	s := createFakeSession()
+	valueTable := func(typ string) *SelectBuilder {
+		return s.Select("attribute_id", "value").From("catalog_product_entity_" + typ).
+			Where(ConditionRaw("entity_id = ?", typ))
+	}
+
+	t.Run("UnionAll", func(t *testing.T) {
+		sql, args, err := valueTable("varchar").UnionAll(valueTable("int"), valueTable("decimal")).ToSql()
+		assert.NoError(t, err)
+		assert.Exactly(t, "SELECT attribute_id, value FROM `catalog_product_entity_varchar` WHERE (entity_id = ?) UNION ALL SELECT attribute_id, value FROM `catalog_product_entity_int` WHERE (entity_id = ?) UNION ALL SELECT attribute_id, value FROM `catalog_product_entity_decimal` WHERE (entity_id = ?)", sql)
+		assert.Exactly(t, []interface{}{"varchar", "int", "decimal"}, args)
+	})
+	t.Run("Union with order and limit", func(t *testing.T) {
+		sql, args, err := valueTable("datetime").OrderBy("value").Limit(1).
+			Union(valueTable("text").Limit(2)).ToSql()
+		assert.NoError(t, err)
+		assert.Exactly(t, "(SELECT attribute_id, value FROM `catalog_product_entity_datetime` WHERE (entity_id = ?) ORDER BY value LIMIT 1) UNION (SELECT attribute_id, value FROM `catalog_product_entity_text` WHERE (entity_id = ?) LIMIT 2)", sql)
+		assert.Exactly(t, []interface{}{"datetime", "text"}, args)
+	})
+	t.Run("Union as derived table", func(t *testing.T) {
+		u := valueTable("varchar").Union(valueTable("int"))
+		sql, args, err := s.Select("*").FromSub(u, "v").Where(ConditionRaw("v.attribute_id = ?", 3)).OrderBy("v.value").ToSql()
+		assert.NoError(t, err)
+		assert.Exactly(t, "SELECT * FROM (SELECT attribute_id, value FROM `catalog_product_entity_varchar` WHERE (entity_id = ?) UNION SELECT attribute_id, value FROM `catalog_product_entity_int` WHERE (entity_id = ?)) AS `v` WHERE (v.attribute_id = ?) ORDER BY v.value", sql)
+		assert.Exactly(t, []interface{}{"varchar", "int", 3}, args)
+	})
+}
+
+func TestSelectWith(t *testing.T) {
+	s := createFakeSession()
+
+	t.Run("CTE", func(t *testing.T) {
+		sql, args, err := s.Select("name").From("cte").
+			With("cte", s.Select("name").From("store").Where(ConditionRaw("website_id = ?", 1))).
+			Where(ConditionRaw("name <> ?", "admin")).
+			ToSql()
+		assert.NoError(t, err)
+		assert.Exactly(t, "WITH `cte` AS (SELECT name FROM `store` WHERE (website_id = ?)) SELECT name FROM `cte` WHERE (name <> ?)", sql)
+		assert.Exactly(t, []interface{}{1, "admin"}, args)
+	})
+	t.Run("Recursive category tree", func(t *testing.T) {
+		anchor := s.Select("entity_id", "parent_id", "1").From("catalog_category_entity").
+			Where(ConditionRaw("entity_id = ?", 2))
+		recursive := s.Select("c.entity_id", "c.parent_id", "t.level + 1").
+			From("catalog_category_entity", "c").
+			Join(JoinTable("tree", "t"), nil, ConditionRaw("t.entity_id = c.parent_id")).
+			Where(ConditionRaw("t.level < ?", 5))
+
+		sql, args, err := s.Select("*").From("tree").
+			WithRecursive("tree", anchor.UnionAll(recursive), "entity_id", "parent_id", "level").
+			Where(ConditionRaw("level > ?", 1)).
+			ToSql()
+		assert.NoError(t, err)
+		assert.Exactly(t, "WITH RECURSIVE `tree` (`entity_id`, `parent_id`, `level`) AS (SELECT entity_id, parent_id, 1 FROM `catalog_category_entity` WHERE (entity_id = ?) UNION ALL SELECT c.entity_id, c.parent_id, t.level + 1 FROM `catalog_category_entity` AS `c` INNER JOIN `tree` AS `t` ON (t.entity_id = c.parent_id) WHERE (t.level < ?)) SELECT * FROM `tree` WHERE (level > ?)", sql)
+		assert.Exactly(t, []interface{}{2, 5, 1}, args)
+	})
+	t.Run("Missing name", func(t *testing.T) {
+		_, _, err := s.Select("a").From("b").With("", s.Select("a").From("c")).ToSql()
+		assert.True(t, errors.IsNotValid(err), "%+v", err)
+	})
+}
+
+func TestSelectSubInterpolate(t *testing.T) {
+	s := createFakeSession()
+	sub := s.Select("entity_id").From("catalog_category_product").Where(ConditionRaw("category_id = ?", 33))
+	sql, args, err := s.Select("a").From("b").
+		Where(ConditionRaw("c = ?", "d")).
+		Where(ConditionSub("entity_id IN", sub)).
+		ToSql()
+	assert.NoError(t, err)
+	full, err := Preprocess(sql, args)
+	assert.NoError(t, err)
+	assert.Exactly(t, "SELECT a FROM `b` WHERE (c = 'd') AND (entity_id IN (SELECT entity_id FROM `catalog_category_product` WHERE (category_id = 33)))", full)
+}
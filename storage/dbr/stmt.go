@@ -0,0 +1,255 @@
+package dbr
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+)
+
+// PreparedStmt is a prepared statement created by a builder. The statement can be
+// executed many times with new arguments without rendering the SQL again and
+// without interpolating the arguments on the client side. The arguments must
+// be passed in the order of the ? placeholders of the builder. Slices as
+// arguments are not supported because they cannot be expanded anymore.
+type PreparedStmt struct {
+	*sql.Stmt
+	// SQL the prepared query with its placeholders.
+	SQL  string
+	sess *Session
+}
+
+// prepare renders the SQL of the builder and creates a prepared statement.
+func (sess *Session) prepare(ctx context.Context, r runner, b QueryBuilder) (*PreparedStmt, error) {
+	query, _, err := b.ToSql()
+	if err != nil {
+		return nil, sess.EventErr("dbr.prepare.tosql", err)
+	}
+	stmt, err := r.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, sess.EventErrKv("dbr.prepare", err, kvs{"sql": query})
+	}
+	return &PreparedStmt{
+		Stmt: stmt,
+		SQL:  query,
+		sess: sess,
+	}, nil
+}
+
+// Prepare creates a prepared statement from the SelectBuilder. The
+// arguments of the builder are ignored.
+func (b *SelectBuilder) Prepare() (*PreparedStmt, error) {
+	return b.PrepareContext(context.Background())
+}
+
+// PrepareContext same as Prepare but with a context.
+func (b *SelectBuilder) PrepareContext(ctx context.Context) (*PreparedStmt, error) {
+	return b.prepare(ctx, b.runner, b)
+}
+
+// Prepare creates a prepared statement from the InsertBuilder. The
+// arguments of the builder are ignored.
+func (b *InsertBuilder) Prepare() (*PreparedStmt, error) {
+	return b.PrepareContext(context.Background())
+}
+
+// PrepareContext same as Prepare but with a context.
+func (b *InsertBuilder) PrepareContext(ctx context.Context) (*PreparedStmt, error) {
+	return b.prepare(ctx, b.runner, b)
+}
+
+// Prepare creates a prepared statement from the UpdateBuilder. The
+// arguments of the builder are ignored.
+func (b *UpdateBuilder) Prepare() (*PreparedStmt, error) {
+	return b.PrepareContext(context.Background())
+}
+
+// PrepareContext same as Prepare but with a context.
+func (b *UpdateBuilder) PrepareContext(ctx context.Context) (*PreparedStmt, error) {
+	return b.prepare(ctx, b.runner, b)
+}
+
+// Prepare creates a prepared statement from the DeleteBuilder. The
+// arguments of the builder are ignored.
+func (b *DeleteBuilder) Prepare() (*PreparedStmt, error) {
+	return b.PrepareContext(context.Background())
+}
+
+// PrepareContext same as Prepare but with a context.
+func (b *DeleteBuilder) PrepareContext(ctx context.Context) (*PreparedStmt, error) {
+	return b.prepare(ctx, b.runner, b)
+}
+
+// PreparedStmt returns a transaction-specific prepared statement from an
+// existing statement. The returned statement gets closed with the
+// transaction.
+func (tx *Tx) PreparedStmt(st *PreparedStmt) *PreparedStmt {
+	return &PreparedStmt{
+		Stmt: tx.Tx.Stmt(st.Stmt),
+		SQL:  st.SQL,
+		sess: tx.Session,
+	}
+}
+
+// LoadStructs executes the prepared statement with the arguments and loads
+// the resulting data into a slice of structs. dest must be a pointer to a
+// slice of pointers to structs. Returns the number of items found.
+func (st *PreparedStmt) LoadStructs(dest interface{}, args ...interface{}) (int, error) {
+	return st.LoadStructsContext(context.Background(), dest, args...)
+}
+
+// LoadStructsContext same as LoadStructs but with a context.
+func (st *PreparedStmt) LoadStructsContext(ctx context.Context, dest interface{}, args ...interface{}) (int, error) {
+	valueOfDest, recordType := structSliceOf(dest)
+
+	startTime := time.Now()
+	defer func() { st.sess.TimingKv("dbr.stmt.select", time.Since(startTime).Nanoseconds(), kvs{"sql": st.SQL}) }()
+
+	rows, err := st.QueryContext(ctx, args...)
+	if err != nil {
+		return 0, st.sess.EventErrKv("dbr.stmt.load_all.query", err, kvs{"sql": st.SQL})
+	}
+	defer rows.Close()
+
+	return st.sess.loadStructs(rows, valueOfDest, recordType, st.SQL)
+}
+
+// LoadValue executes the prepared statement with the arguments and loads the
+// first column of the first row into dest. Returns ErrNotFound if no row has
+// been found.
+func (st *PreparedStmt) LoadValue(dest interface{}, args ...interface{}) error {
+	return st.LoadValueContext(context.Background(), dest, args...)
+}
+
+// LoadValueContext same as LoadValue but with a context.
+func (st *PreparedStmt) LoadValueContext(ctx context.Context, dest interface{}, args ...interface{}) error {
+	startTime := time.Now()
+	defer func() { st.sess.TimingKv("dbr.stmt.select", time.Since(startTime).Nanoseconds(), kvs{"sql": st.SQL}) }()
+
+	err := st.QueryRowContext(ctx, args...).Scan(dest)
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	}
+	if err != nil {
+		return st.sess.EventErrKv("dbr.stmt.load_value.scan", err, kvs{"sql": st.SQL})
+	}
+	return nil
+}
+
+// DefaultStmtCacheSize maximum number of statements in a StmtCache created
+// with a size lower than one.
+const DefaultStmtCacheSize = 256
+
+// StmtCache caches prepared statements keyed by their generated SQL. Two
+// builders which render the same SQL share the same statement. If the cache
+// is full the least recently used statement gets evicted and closed, so a
+// statement returned by the cache should be executed right away and not be
+// kept. StmtCache is safe for concurrent use.
+type StmtCache struct {
+	sess  *Session
+	size  int
+	mu    sync.Mutex
+	lru   *list.List // front: most recently used *stmtCacheEntry
+	stmts map[string]*list.Element
+}
+
+type stmtCacheEntry struct {
+	query string
+	st    *PreparedStmt
+}
+
+// NewStmtCache creates a new statement cache for the connection of the
+// session which holds at most size statements. A size lower than one sets
+// DefaultStmtCacheSize.
+func (sess *Session) NewStmtCache(size int) *StmtCache {
+	if size < 1 {
+		size = DefaultStmtCacheSize
+	}
+	return &StmtCache{
+		sess:  sess,
+		size:  size,
+		lru:   list.New(),
+		stmts: make(map[string]*list.Element),
+	}
+}
+
+// Prepare returns the cached statement for the SQL of the builder or
+// prepares and caches a new one. The statement must not be closed by the
+// caller.
+func (sc *StmtCache) Prepare(b QueryBuilder) (*PreparedStmt, error) {
+	return sc.PrepareContext(context.Background(), b)
+}
+
+// PrepareContext same as Prepare but with a context.
+func (sc *StmtCache) PrepareContext(ctx context.Context, b QueryBuilder) (*PreparedStmt, error) {
+	query, _, err := b.ToSql()
+	if err != nil {
+		return nil, sc.sess.EventErr("dbr.stmt_cache.tosql", err)
+	}
+
+	sc.mu.Lock()
+	if el, ok := sc.stmts[query]; ok {
+		sc.lru.MoveToFront(el)
+		sc.mu.Unlock()
+		return el.Value.(*stmtCacheEntry).st, nil
+	}
+	sc.mu.Unlock()
+
+	// prepare without holding the lock because it requires a round trip to
+	// the server.
+	st, err := sc.sess.prepare(ctx, sc.sess.cxn.DB, b)
+	if err != nil {
+		return nil, err
+	}
+
+	sc.mu.Lock()
+	if el, ok := sc.stmts[query]; ok {
+		// another goroutine has been faster
+		sc.lru.MoveToFront(el)
+		sc.mu.Unlock()
+		sc.close(query, st)
+		return el.Value.(*stmtCacheEntry).st, nil
+	}
+	sc.stmts[query] = sc.lru.PushFront(&stmtCacheEntry{query: query, st: st})
+	var evicted *stmtCacheEntry
+	if sc.lru.Len() > sc.size {
+		evicted = sc.lru.Remove(sc.lru.Back()).(*stmtCacheEntry)
+		delete(sc.stmts, evicted.query)
+	}
+	sc.mu.Unlock()
+
+	if evicted != nil {
+		sc.close(evicted.query, evicted.st)
+	}
+	return st, nil
+}
+
+func (sc *StmtCache) close(query string, st *PreparedStmt) error {
+	if err := st.Close(); err != nil {
+		return sc.sess.EventErrKv("dbr.stmt_cache.close", err, kvs{"sql": query})
+	}
+	return nil
+}
+
+// Len returns the number of cached statements.
+func (sc *StmtCache) Len() int {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	return sc.lru.Len()
+}
+
+// Close closes all statements and clears the cache. Returns the first error.
+func (sc *StmtCache) Close() error {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	var firstErr error
+	for query, el := range sc.stmts {
+		if err := sc.close(query, el.Value.(*stmtCacheEntry).st); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delete(sc.stmts, query)
+	}
+	sc.lru.Init()
+	return firstErr
+}
@@ -0,0 +1,113 @@
+package dbr
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStmtLoadStructs(t *testing.T) {
+	s, mock := createMockSession(t)
+
+	prep := mock.ExpectPrepare("SELECT id, name FROM `dbr_people` WHERE \\(id > \\?\\)")
+	prep.ExpectQuery().WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(2, "Gopher").AddRow(3, "Rustacean"))
+	prep.ExpectQuery().WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(3, "Rustacean"))
+	prep.ExpectQuery().WithArgs(3).WillReturnRows(sqlmock.NewRows([]string{"name"}))
+
+	st, err := s.Select("id", "name").From("dbr_people").Where(ConditionRaw("id > ?", 0)).Prepare()
+	assert.NoError(t, err)
+	assert.Exactly(t, "SELECT id, name FROM `dbr_people` WHERE (id > ?)", st.SQL)
+
+	var people []*dbrPerson
+	n, err := st.LoadStructs(&people, 1)
+	assert.NoError(t, err)
+	assert.Exactly(t, 2, n)
+
+	people = nil
+	n, err = st.LoadStructsContext(context.Background(), &people, 2)
+	assert.NoError(t, err)
+	assert.Exactly(t, 1, n)
+	assert.Exactly(t, "Rustacean", people[0].Name)
+
+	var name string
+	assert.Exactly(t, ErrNotFound, st.LoadValue(&name, 3))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStmtExec(t *testing.T) {
+	s, mock := createMockSession(t)
+
+	prep := mock.ExpectPrepare("UPDATE `dbr_people` SET `name` = \\? WHERE \\(id = \\?\\)")
+	prep.ExpectExec().WithArgs("Gopher", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	prep.ExpectExec().WithArgs("Rustacean", 2).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	st, err := s.Update("dbr_people").Set("name", "").Where(ConditionRaw("id = ?", 0)).Prepare()
+	assert.NoError(t, err)
+	for i, name := range []string{"Gopher", "Rustacean"} {
+		_, err := st.Exec(name, i+1)
+		assert.NoError(t, err)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectPrepare("DELETE FROM `dbr_people` WHERE \\(id = \\?\\)").ExpectExec().WithArgs(5).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	tx, err := s.Begin()
+	assert.NoError(t, err)
+	st, err = tx.DeleteFrom("dbr_people").Where(ConditionRaw("id = ?", 0)).Prepare()
+	assert.NoError(t, err)
+	_, err = st.Exec(5)
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Commit())
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStmtCache(t *testing.T) {
+	s, mock := createMockSession(t)
+	sc := s.NewStmtCache(0)
+
+	prep := mock.ExpectPrepare("SELECT name FROM `dbr_people` WHERE \\(id = \\?\\)")
+	prep.ExpectQuery().WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("Gopher"))
+	prep.ExpectQuery().WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("Rustacean"))
+	mock.ExpectPrepare("INSERT INTO dbr_people").WillBeClosed()
+	prep.WillBeClosed()
+
+	for i, want := range []string{"Gopher", "Rustacean"} {
+		// each iteration builds a new SelectBuilder which renders the same SQL
+		st, err := sc.Prepare(s.Select("name").From("dbr_people").Where(ConditionRaw("id = ?", i+1)))
+		assert.NoError(t, err)
+		var name string
+		assert.NoError(t, st.LoadValue(&name, i+1))
+		assert.Exactly(t, want, name)
+	}
+	_, err := sc.Prepare(s.InsertInto("dbr_people").Columns("name").Values(""))
+	assert.NoError(t, err)
+	assert.Exactly(t, 2, sc.Len())
+
+	assert.NoError(t, sc.Close())
+	assert.Exactly(t, 0, sc.Len())
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStmtCacheEvict(t *testing.T) {
+	s, mock := createMockSession(t)
+	sc := s.NewStmtCache(2)
+
+	mock.ExpectPrepare("SELECT a FROM `dbr_people`").WillBeClosed()
+	mock.ExpectPrepare("SELECT b FROM `dbr_people`").WillBeClosed()
+	mock.ExpectPrepare("SELECT c FROM `dbr_people`").WillBeClosed()
+	mock.ExpectPrepare("SELECT b FROM `dbr_people`")
+	mock.ExpectPrepare("SELECT a FROM `dbr_people`")
+
+	for _, col := range []string{"a", "b", "a", "c", "b", "a"} {
+		_, err := sc.Prepare(s.Select(col).From("dbr_people"))
+		assert.NoError(t, err, "Column %q", col)
+	}
+	// order of use: a, b, a, c (evicts b), b (evicts a), a (evicts c)
+	assert.Exactly(t, 2, sc.Len())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
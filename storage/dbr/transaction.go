@@ -1,6 +1,7 @@
 package dbr
 
 import (
+	"context"
 	"database/sql"
 )
 
@@ -12,7 +13,15 @@ type Tx struct {
 
 // Begin creates a transaction for the given session
 func (sess *Session) Begin() (*Tx, error) {
-	tx, err := sess.cxn.DB.Begin()
+	return sess.BeginTx(context.Background(), nil)
+}
+
+// BeginTx creates a transaction for the given session with an optional
+// isolation level and read-only flag. The transaction gets rolled back when
+// the context expires.
+//		tx, err := sess.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable, ReadOnly: true})
+func (sess *Session) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	tx, err := sess.cxn.DB.BeginTx(ctx, opts)
 	if err != nil {
 		return nil, sess.EventErr("dbr.begin.error", err)
 	} else {
@@ -1,12 +1,14 @@
 package dbr
 
 import (
+	"context"
 	"database/sql"
 	"database/sql/driver"
 	"fmt"
 	"time"
 
 	"github.com/corestoreio/csfw/util/bufferpool"
+	"github.com/corestoreio/csfw/util/errors"
 )
 
 type expr struct {
@@ -37,7 +39,7 @@ type UpdateBuilder struct {
 	OffsetValid    bool
 }
 
-var _ queryBuilder = (*UpdateBuilder)(nil)
+var _ QueryBuilder = (*UpdateBuilder)(nil)
 
 type setClause struct {
 	column string
@@ -187,7 +189,9 @@ func (b *UpdateBuilder) ToSql() (string, []interface{}, error) {
 	// Write WHERE clause if we have any fragments
 	if len(b.WhereFragments) > 0 {
 		sql.WriteString(" WHERE ")
-		writeWhereFragmentsToSql(b.WhereFragments, sql, &args)
+		if err := writeWhereFragmentsToSql(b.WhereFragments, sql, &args); err != nil {
+			return "", nil, errors.Wrap(err, "[dbr] UpdateBuilder.ToSql.Where")
+		}
 	}
 
 	// Ordering and limiting
@@ -217,6 +221,12 @@ func (b *UpdateBuilder) ToSql() (string, []interface{}, error) {
 // Exec executes the statement represented by the UpdateBuilder
 // It returns the raw database/sql Result and an error if there was one
 func (b *UpdateBuilder) Exec() (sql.Result, error) {
+	return b.ExecContext(context.Background())
+}
+
+// ExecContext same as Exec but the statement gets canceled when the context
+// expires.
+func (b *UpdateBuilder) ExecContext(ctx context.Context) (sql.Result, error) {
 	sql, args, err := b.ToSql()
 	if err != nil {
 		return nil, b.EventErrKv("dbr.update.exec.tosql", err, nil)
@@ -231,7 +241,7 @@ func (b *UpdateBuilder) Exec() (sql.Result, error) {
 	startTime := time.Now()
 	defer func() { b.TimingKv("dbr.update", time.Since(startTime).Nanoseconds(), kvs{"sql": fullSql}) }()
 
-	result, err := b.runner.Exec(fullSql)
+	result, err := b.runner.ExecContext(ctx, fullSql)
 	if err != nil {
 		return result, b.EventErrKv("dbr.update.exec.exec", err, kvs{"sql": fullSql})
 	}
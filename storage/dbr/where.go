@@ -34,6 +34,14 @@ type whereFragment struct {
 	Condition   string
 	Values      []interface{}
 	EqualityMap map[string]interface{}
+	// Sub gets appended in parenthesis to the Condition.
+	Sub *SelectBuilder
+	// Nested conditions are glued together with AND or with OR if IsOr has
+	// been set. Created by the functions And and Or.
+	Nested []*whereFragment
+	IsOr   bool
+	// err gets returned when writing the fragment.
+	err error
 }
 
 type ConditionArg func(*whereFragment)
@@ -48,6 +56,19 @@ func ConditionRaw(raw string, values ...interface{}) ConditionArg {
 	}
 }
 
+// ConditionSub appends the sub select in parenthesis to the condition. The
+// arguments of the sub select are getting added at the position of the sub
+// select. Examples:
+//		ConditionSub("`entity_id` IN", sel)   // (`entity_id` IN (SELECT ...))
+//		ConditionSub("EXISTS", sel)           // (EXISTS (SELECT ...))
+//		ConditionSub("`value` >", sel)        // (`value` > (SELECT ...))
+func ConditionSub(cond string, sub *SelectBuilder) ConditionArg {
+	return func(wf *whereFragment) {
+		wf.Condition = cond
+		wf.Sub = sub
+	}
+}
+
 func ConditionMap(eq Eq) ConditionArg {
 	return func(wf *whereFragment) {
 		// todo add argsValuer
@@ -65,10 +86,24 @@ func newWhereFragments(wargs ...ConditionArg) []*whereFragment {
 }
 
 // Invariant: only called when len(fragments) > 0
-func writeWhereFragmentsToSql(fragments []*whereFragment, sql QueryWriter, args *[]interface{}) {
+func writeWhereFragmentsToSql(fragments []*whereFragment, sql QueryWriter, args *[]interface{}) error {
 	anyConditions := false
 	for _, f := range fragments {
-		if f.Condition != "" {
+		if f.err != nil {
+			return f.err
+		}
+		if f.Nested != nil {
+			if f.isEmpty() {
+				continue
+			}
+			if anyConditions {
+				_, _ = sql.WriteString(" AND ")
+			}
+			anyConditions = true
+			if err := writeNestedFragmentsToSql(f, sql, args); err != nil {
+				return err
+			}
+		} else if f.Condition != "" {
 			if anyConditions {
 				_, _ = sql.WriteString(" AND (")
 			} else {
@@ -76,14 +111,60 @@ func writeWhereFragmentsToSql(fragments []*whereFragment, sql QueryWriter, args
 				anyConditions = true
 			}
 			_, _ = sql.WriteString(f.Condition)
-			_, _ = sql.WriteRune(')')
 			if len(f.Values) > 0 {
 				*args = append(*args, f.Values...)
 			}
+			if f.Sub != nil {
+				_, _ = sql.WriteRune(' ')
+				if err := writeSubSelect(sql, args, f.Sub); err != nil {
+					return err
+				}
+			}
+			_, _ = sql.WriteRune(')')
 		} else if f.EqualityMap != nil {
 			anyConditions = writeEqualityMapToSql(f.EqualityMap, sql, args, anyConditions)
 		}
 	}
+	return nil
+}
+
+// isEmpty reports if the fragment writes no SQL at all, for example an empty
+// ConditionMap.
+func (f *whereFragment) isEmpty() bool {
+	if f.err != nil || f.Condition != "" || len(f.EqualityMap) > 0 {
+		return false
+	}
+	for _, n := range f.Nested {
+		if !n.isEmpty() {
+			return false
+		}
+	}
+	return true
+}
+
+// writeNestedFragmentsToSql writes the nested fragments in parenthesis and
+// glued together with AND or OR.
+func writeNestedFragmentsToSql(f *whereFragment, sql QueryWriter, args *[]interface{}) error {
+	glue := " AND "
+	if f.IsOr {
+		glue = " OR "
+	}
+	_, _ = sql.WriteRune('(')
+	i := 0
+	for _, n := range f.Nested {
+		if n.isEmpty() {
+			continue
+		}
+		if i > 0 {
+			_, _ = sql.WriteString(glue)
+		}
+		i++
+		if err := writeWhereFragmentsToSql([]*whereFragment{n}, sql, args); err != nil {
+			return err
+		}
+	}
+	_, _ = sql.WriteRune(')')
+	return nil
 }
 
 func writeEqualityMapToSql(eq map[string]interface{}, sql QueryWriter, args *[]interface{}, anyConditions bool) bool {